@@ -0,0 +1,13 @@
+data "maas_allocatable_machines" "kvm" {
+  min_cpu_count = 8
+  min_memory = 16384
+  min_storage = 100
+  zone = "default"
+  tags = [
+    maas_tag.kvm.name,
+  ]
+}
+
+output "kvm_machines_available" {
+  value = data.maas_allocatable_machines.kvm.machines_count
+}
@@ -0,0 +1,7 @@
+data "maas_available_images" "default" {
+  boot_source = 1
+}
+
+locals {
+  ubuntu_releases = distinct([for i in data.maas_available_images.default.images : i.release if i.os == "ubuntu"])
+}
@@ -0,0 +1,3 @@
+data "maas_boot_source_selections" "default" {
+  boot_source = 1
+}
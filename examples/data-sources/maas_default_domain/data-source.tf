@@ -0,0 +1 @@
+data "maas_default_domain" "default" {}
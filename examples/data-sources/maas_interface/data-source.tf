@@ -0,0 +1,4 @@
+data "maas_interface" "virsh_vm1_eth0" {
+  machine = maas_machine.virsh_vm1.id
+  name = "eth0"
+}
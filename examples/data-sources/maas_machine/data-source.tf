@@ -0,0 +1,7 @@
+data "maas_machine" "machine01" {
+  machine = "machine01"
+}
+
+output "machine01_power_state" {
+  value = data.maas_machine.machine01.power_state
+}
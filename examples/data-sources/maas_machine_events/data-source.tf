@@ -0,0 +1,9 @@
+data "maas_machine_events" "machine" {
+  machine = "machine-01"
+  level   = "WARNING"
+  limit   = 20
+}
+
+output "machine_events" {
+  value = data.maas_machine_events.machine.events
+}
@@ -0,0 +1,4 @@
+data "maas_network_interface_link" "virsh_vm1_pxe" {
+  machine = maas_instance.virsh_vm1.id
+  subnet = data.maas_subnet.pxe.id
+}
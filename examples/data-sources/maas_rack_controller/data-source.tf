@@ -0,0 +1,7 @@
+data "maas_rack_controller" "rack01" {
+  hostname = "rack01"
+}
+
+output "rack01_system_id" {
+  value = data.maas_rack_controller.rack01.system_id
+}
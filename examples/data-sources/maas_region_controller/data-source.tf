@@ -0,0 +1,7 @@
+data "maas_region_controller" "region01" {
+  hostname = "region01"
+}
+
+output "region01_version" {
+  value = data.maas_region_controller.region01.version
+}
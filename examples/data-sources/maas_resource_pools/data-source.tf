@@ -0,0 +1,5 @@
+data "maas_resource_pools" "all" {}
+
+output "resource_pool_names" {
+  value = data.maas_resource_pools.all.resource_pools[*].name
+}
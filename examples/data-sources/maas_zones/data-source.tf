@@ -0,0 +1,5 @@
+data "maas_zones" "all" {}
+
+output "zone_names" {
+  value = data.maas_zones.all.zones[*].name
+}
@@ -0,0 +1,2 @@
+# Bcache devices can be imported with the machine identifier (system ID, hostname, FQDN, or MAC address) and the bcache identifier (ID or name). e.g.
+$ terraform import maas_bcache.bcache0 machine-06:bcache0
@@ -0,0 +1,7 @@
+resource "maas_bcache" "bcache0" {
+  machine = maas_machine.virsh_vm2.id
+  name = "bcache0"
+  cache_set = maas_bcache_cache_set.cache0.id
+  backing_device = "sda"
+  cache_mode = "WRITEBACK"
+}
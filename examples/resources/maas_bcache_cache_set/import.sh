@@ -0,0 +1,2 @@
+# Bcache cache sets can be imported with the machine identifier (system ID, hostname, FQDN, or MAC address) and the cache set identifier (ID or name). e.g.
+$ terraform import maas_bcache_cache_set.cache0 machine-06:cache0
@@ -0,0 +1,4 @@
+resource "maas_bcache_cache_set" "cache0" {
+  machine = maas_machine.virsh_vm2.id
+  cache_device = "nvme0n1"
+}
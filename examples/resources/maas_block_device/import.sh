@@ -1,2 +1,2 @@
-# Block devices can be imported with the machine identifier (system ID, hostname, or FQDN) and the block device identifier (ID or name). e.g.
+# Block devices can be imported with the machine identifier (system ID, hostname, FQDN, or MAC address) and the block device identifier (ID or name). e.g.
 $ terraform import maas_block_device.vdb machine-06:vdb
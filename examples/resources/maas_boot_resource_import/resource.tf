@@ -0,0 +1,28 @@
+resource "maas_boot_source_selection" "jammy" {
+  boot_source = 1
+  os          = "ubuntu"
+  release     = "jammy"
+  arches      = ["amd64"]
+}
+
+resource "maas_boot_resource_import" "jammy" {
+  os           = maas_boot_source_selection.jammy.os
+  release      = maas_boot_source_selection.jammy.release
+  architecture = "amd64/generic"
+
+  triggers = {
+    arches = join(",", sort(maas_boot_source_selection.jammy.arches))
+  }
+
+  timeouts {
+    create = "90m"
+  }
+}
+
+resource "maas_instance" "kvm" {
+  deploy_params {
+    distro_series = "jammy"
+  }
+
+  depends_on = [maas_boot_resource_import.jammy]
+}
@@ -0,0 +1,2 @@
+# Boot sources can be imported with their ID. e.g.
+$ terraform import maas_boot_source.mirror 1
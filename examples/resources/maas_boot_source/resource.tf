@@ -0,0 +1,4 @@
+resource "maas_boot_source" "mirror" {
+  url              = "http://images.example.com/ephemeral-v3/stable/"
+  keyring_filename = "/usr/share/keyrings/ubuntu-cloudimage-keyring.gpg"
+}
@@ -0,0 +1,2 @@
+# Boot source selections can be imported with the boot source ID and the selection ID or OS/RELEASE. e.g.
+$ terraform import maas_boot_source_selection.jammy 1:ubuntu/jammy
@@ -0,0 +1,6 @@
+resource "maas_boot_source_selection" "jammy" {
+  boot_source = maas_boot_source.mirror.id
+  os          = "ubuntu"
+  release     = "jammy"
+  arches      = ["amd64", "arm64"]
+}
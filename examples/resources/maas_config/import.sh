@@ -0,0 +1,2 @@
+# An existing MAAS config setting can be imported using its key. e.g.
+$ terraform import maas_config.ntp_servers ntp_servers
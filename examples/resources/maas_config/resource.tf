@@ -0,0 +1,14 @@
+resource "maas_config" "default_distro_series" {
+  key   = "default_distro_series"
+  value = "jammy"
+}
+
+resource "maas_config" "ntp_servers" {
+  key   = "ntp_servers"
+  value = "ntp1.example.com ntp2.example.com"
+}
+
+resource "maas_config" "enable_http_proxy" {
+  key   = "enable_http_proxy"
+  value = "false"
+}
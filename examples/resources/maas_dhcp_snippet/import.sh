@@ -0,0 +1,2 @@
+# DHCP snippets can be imported with their ID or name. e.g.
+$ terraform import maas_dhcp_snippet.tf_global tf-global
@@ -0,0 +1,14 @@
+resource "maas_dhcp_snippet" "tf_pxe_options" {
+  name = "tf-pxe-options"
+  description = "PXE options for the Terraform managed subnet"
+  value = <<-EOT
+    option pxe-system-type code 93 = unsigned integer 16;
+  EOT
+  subnet = maas_subnet.tf_subnet.id
+}
+
+resource "maas_dhcp_snippet" "tf_global" {
+  name = "tf-global"
+  value = "option domain-search \"example.com\";"
+  enabled = false
+}
@@ -1,2 +1,5 @@
 # DNS records can be imported using the type and the identifier (ID or FQDN). e.g.
 $ terraform import maas_dns_record.test_a A/AAAA:test-a.cloudbase
+
+# DNS records can also be imported using only the FQDN, if it has addresses or a single data record. e.g.
+$ terraform import maas_dns_record.test_txt test-txt.cloudbase
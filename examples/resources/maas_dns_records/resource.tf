@@ -0,0 +1,22 @@
+resource "maas_dns_records" "cloudbase" {
+  domain = maas_dns_domain.cloudbase.name
+
+  records {
+    name = "test-a"
+    type = "A/AAAA"
+    data = "10.99.11.33"
+  }
+
+  records {
+    name = "test-txt"
+    type = "TXT"
+    data = "test"
+    ttl  = 600
+  }
+
+  records {
+    name = "test-cname"
+    type = "CNAME"
+    data = "test-a"
+  }
+}
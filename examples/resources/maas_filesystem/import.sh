@@ -0,0 +1,3 @@
+# File systems can be imported with the machine identifier (system ID, hostname, FQDN, or MAC address), the block device identifier (ID or name), and optionally the partition identifier (ID or path). e.g.
+$ terraform import maas_filesystem.srv machine-06:vdd
+$ terraform import maas_filesystem.swap machine-06:vdc:42
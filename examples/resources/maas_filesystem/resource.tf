@@ -0,0 +1,15 @@
+resource "maas_filesystem" "srv" {
+  machine = maas_machine.virsh_vm2.id
+  block_device = "vdd"
+  fs_type = "xfs"
+  mount_point = "/srv"
+  mount_options = "noatime"
+}
+
+resource "maas_filesystem" "swap" {
+  machine = maas_machine.virsh_vm2.id
+  block_device = "vdc"
+  partition = maas_partition.vdc_part2.partition_id
+  fs_type = "swap"
+  mount_point = "none"
+}
@@ -1,2 +1,2 @@
-# The machines imported as `maas_instance` resources must be already deployed. They can be imported using one of the deployed machine attributes: system ID, hostname, or FQDN. e.g.
+# The machines imported as `maas_instance` resources must be already deployed. They can be imported using one of the deployed machine attributes: system ID, hostname, FQDN, or MAC address. e.g.
 $ terraform import maas_instance.virsh_vm machine-01
@@ -0,0 +1,2 @@
+# Logical volumes can be imported with the machine identifier (system ID, hostname, FQDN, or MAC address), the volume group identifier (ID or name), and the logical volume identifier (ID or name). e.g.
+$ terraform import maas_logical_volume.lv0 machine-06:vg0:lv0
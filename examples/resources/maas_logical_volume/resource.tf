@@ -0,0 +1,8 @@
+resource "maas_logical_volume" "lv0" {
+  machine = maas_machine.virsh_vm2.id
+  volume_group = maas_volume_group.vg0.id
+  name = "lv0"
+  size_gigabytes = 10
+  fs_type = "ext4"
+  mount_point = "/data"
+}
@@ -1,2 +1,2 @@
-# MAAS machines can be imported using one of the attributes: system ID, hostname, or FQDN. e.g.
+# MAAS machines can be imported using one of the attributes: system ID, hostname, FQDN, or MAC address. e.g.
 $ terraform import maas_machine.virsh_vm1 vm1.maas
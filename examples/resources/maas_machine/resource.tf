@@ -6,3 +6,17 @@ resource "maas_machine" "virsh_vm1" {
   }
   pxe_mac_address = "52:54:00:89:f5:3e"
 }
+
+resource "maas_machine" "virsh_vm2" {
+  power_type = "virsh"
+  power_parameters = {
+    power_address = "qemu+ssh://ubuntu@10.113.1.26/system"
+    power_id = "test-vm2"
+  }
+  pxe_mac_address = "52:54:00:7c:f7:77"
+  commissioning_params {
+    commissioning_scripts = ["update_firmware"]
+    testing_scripts = ["none"]
+    skip_bmc_config = true
+  }
+}
@@ -0,0 +1,2 @@
+# The machine network configuration can be imported with the machine identifier (system ID, hostname, FQDN, or MAC address). e.g.
+$ terraform import maas_machine_network.machine01 machine01
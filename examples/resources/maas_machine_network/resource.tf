@@ -0,0 +1,31 @@
+resource "maas_machine_network" "machine01" {
+  machine = "machine01"
+
+  interfaces {
+    name = "eth0"
+  }
+  interfaces {
+    name = "eth1"
+  }
+  interfaces {
+    name      = "bond0"
+    type      = "bond"
+    parents   = ["eth0", "eth1"]
+    bond_mode = "802.3ad"
+    links {
+      subnet          = "10.10.0.0/16"
+      mode            = "STATIC"
+      ip_address      = "10.10.0.20"
+      default_gateway = true
+    }
+  }
+  interfaces {
+    name    = "bond0.100"
+    type    = "vlan"
+    parents = ["bond0"]
+    vlan    = "5003"
+    links {
+      subnet = "10.100.0.0/16"
+    }
+  }
+}
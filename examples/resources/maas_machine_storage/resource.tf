@@ -0,0 +1,44 @@
+resource "maas_machine_storage" "storage" {
+  machine = maas_machine.virsh_vm2.id
+  wipe = true
+
+  disk {
+    name = "vda"
+
+    partition {
+      name = "efi"
+      size = "512M"
+      bootable = true
+      fs_type = "fat32"
+      mount_point = "/boot/efi"
+    }
+
+    partition {
+      name = "pv0"
+      size = "40G"
+    }
+  }
+
+  disk {
+    name = "vdb"
+  }
+
+  volume_group {
+    name = "vg0"
+    devices = ["pv0", "vdb"]
+
+    logical_volume {
+      name = "root"
+      size = "30G"
+      fs_type = "ext4"
+      mount_point = "/"
+    }
+
+    logical_volume {
+      name = "data"
+      size = "50G"
+      fs_type = "xfs"
+      mount_point = "/data"
+    }
+  }
+}
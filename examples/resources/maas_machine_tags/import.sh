@@ -0,0 +1,5 @@
+# Machine tags can be imported with the machine identifier (system ID, hostname, FQDN, or MAC address) and the managed tag names. e.g.
+$ terraform import maas_machine_tags.worker_1 worker-1:gpu,rack-a
+
+# Without the tag names, all the manual tags of the machine are managed. e.g.
+$ terraform import maas_machine_tags.worker_1 worker-1
@@ -0,0 +1,11 @@
+resource "maas_tag" "gpu" {
+  name = "gpu"
+}
+
+resource "maas_machine_tags" "worker_1" {
+  machine = "worker-1"
+  tags = [
+    maas_tag.gpu.name,
+    "rack-a",
+  ]
+}
@@ -0,0 +1,2 @@
+# The MAAS network discovery settings can be imported using any ID. e.g.
+$ terraform import maas_network_discovery.default network_discovery
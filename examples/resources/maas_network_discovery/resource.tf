@@ -0,0 +1,4 @@
+resource "maas_network_discovery" "default" {
+  network_discovery         = "enabled"
+  active_discovery_interval = 3600
+}
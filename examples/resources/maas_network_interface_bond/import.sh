@@ -0,0 +1,2 @@
+# A bond network interface can be imported using the machine identifier (system ID, hostname, FQDN, or MAC address) and its own identifier (name or ID). e.g.
+$ terraform import maas_network_interface_bond.bond0 server1:bond0
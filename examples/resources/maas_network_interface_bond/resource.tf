@@ -0,0 +1,14 @@
+resource "maas_network_interface_bond" "bond0" {
+  machine = maas_machine.server1.id
+  name    = "bond0"
+  parents = [
+    maas_network_interface_physical.server1_nic1.name,
+    maas_network_interface_physical.server1_nic2.name,
+  ]
+  vlan                  = data.maas_vlan.default.id
+  mtu                   = 9000
+  bond_mode             = "802.3ad"
+  bond_lacp_rate        = "fast"
+  bond_xmit_hash_policy = "layer3+4"
+  bond_miimon           = 100
+}
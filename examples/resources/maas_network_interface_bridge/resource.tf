@@ -0,0 +1,10 @@
+resource "maas_network_interface_bridge" "br0" {
+  machine     = maas_machine.hypervisor1.id
+  name        = "br0"
+  parent      = maas_network_interface_bond.bond0.name
+  vlan        = data.maas_vlan.default.id
+  mtu         = 9000
+  bridge_type = "standard"
+  bridge_stp  = false
+  bridge_fd   = 15
+}
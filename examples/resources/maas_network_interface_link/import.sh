@@ -0,0 +1,2 @@
+# A network interface link can be imported using the machine identifier (system ID, hostname, FQDN, or MAC address), the network interface identifier (MAC address, name, or ID), and the link ID. e.g.
+$ terraform import maas_network_interface_link.virsh_vm1_nic1 vm1:eth0:42
@@ -1,2 +1,2 @@
-# A physical network interface can be imported using the machine identifier (system ID, hostname, or FQDN) and its own identifier (MAC address, name, or ID). e.g.
+# A physical network interface can be imported using the machine identifier (system ID, hostname, FQDN, or MAC address) and its own identifier (MAC address, name, or ID). e.g.
 $ terraform import maas_network_interface_physical.virsh_vm1 vm1:eth0
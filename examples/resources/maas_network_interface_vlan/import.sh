@@ -0,0 +1,2 @@
+# A VLAN network interface can be imported using the machine identifier (system ID, hostname, FQDN, or MAC address) and its own identifier (name or ID). e.g.
+$ terraform import maas_network_interface_vlan.eth0_100 server1:eth0.100
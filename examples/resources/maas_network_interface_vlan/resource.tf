@@ -0,0 +1,9 @@
+resource "maas_network_interface_vlan" "eth0_100" {
+  machine = maas_machine.server1.id
+  parent  = maas_network_interface_physical.server1_nic1.name
+  vlan    = maas_vlan.vid100.id
+  mtu     = 1500
+  tags = [
+    "storage",
+  ]
+}
@@ -0,0 +1,2 @@
+# Notifications can be imported with their ID. e.g.
+$ terraform import maas_notification.maintenance 12
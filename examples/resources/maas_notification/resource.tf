@@ -0,0 +1,10 @@
+resource "maas_notification" "maintenance" {
+  ident    = "maintenance-window"
+  message  = "Maintenance window on {date}, deployments may be delayed."
+  category = "warning"
+  users    = true
+  admins   = true
+  context = {
+    date = "2026-11-02"
+  }
+}
@@ -0,0 +1,2 @@
+# Package repositories can be imported with their ID or name. e.g.
+$ terraform import maas_package_repository.tf_ppa tf-ppa
@@ -0,0 +1,15 @@
+resource "maas_package_repository" "main_archive" {
+  name = "main_archive"
+  url = "http://archive.example.com/ubuntu"
+  disabled_pockets = ["backports"]
+  disabled_components = ["multiverse"]
+}
+
+resource "maas_package_repository" "tf_ppa" {
+  name = "tf-ppa"
+  url = "http://ppa.launchpad.net/example/ppa/ubuntu"
+  distributions = ["jammy"]
+  components = ["main"]
+  arches = ["amd64"]
+  key = file("ppa.asc")
+}
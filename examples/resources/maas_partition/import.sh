@@ -0,0 +1,2 @@
+# Partitions can be imported with the machine identifier (system ID, hostname, FQDN, or MAC address), the block device identifier (ID or name), and the partition identifier (ID or path). e.g.
+$ terraform import maas_partition.vdc_part1 machine-06:vdc:42
@@ -0,0 +1,21 @@
+resource "maas_partition" "vdc_part1" {
+  machine = maas_machine.virsh_vm2.id
+  block_device = "vdc"
+  size = "20G"
+  fs_type = "ext4"
+  mount_point = "/srv"
+}
+
+resource "maas_partition" "vdc_part2" {
+  machine = maas_machine.virsh_vm2.id
+  block_device = "vdc"
+  size_gigabytes = 50
+}
+
+resource "maas_volume_group" "vg1" {
+  machine = maas_machine.virsh_vm2.id
+  name = "vg1"
+  partitions = [
+    maas_partition.vdc_part2.partition_id,
+  ]
+}
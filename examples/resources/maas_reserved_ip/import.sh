@@ -0,0 +1,2 @@
+# Reserved IP addresses can be imported with the IP address. e.g.
+$ terraform import maas_reserved_ip.dns 10.88.88.10
@@ -0,0 +1,6 @@
+resource "maas_reserved_ip" "dns" {
+  subnet      = maas_subnet.tf_subnet.cidr
+  ip_address  = "10.88.88.10"
+  mac_address = "52:54:00:12:34:56"
+  comment     = "DNS server"
+}
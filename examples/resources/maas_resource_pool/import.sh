@@ -0,0 +1,2 @@
+# Resource pools can be imported with their ID or name. e.g.
+$ terraform import maas_resource_pool.ci ci
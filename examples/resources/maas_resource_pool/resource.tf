@@ -0,0 +1,4 @@
+resource "maas_resource_pool" "ci" {
+  name        = "ci"
+  description = "Machines reserved for the CI pipelines"
+}
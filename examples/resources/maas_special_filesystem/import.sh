@@ -0,0 +1,2 @@
+# Special file systems can be imported with the machine identifier (system ID, hostname, FQDN, or MAC address) and the mount point. e.g.
+$ terraform import maas_special_filesystem.tmp machine-06:/tmp
@@ -0,0 +1,7 @@
+resource "maas_special_filesystem" "tmp" {
+  machine = maas_machine.virsh_vm2.id
+  fs_type = "tmpfs"
+  mount_point = "/tmp"
+  size = "2G"
+  mount_options = "noexec,nosuid"
+}
@@ -0,0 +1,2 @@
+# SSH keys can be imported with their ID. e.g.
+$ terraform import maas_ssh_key.operator 5
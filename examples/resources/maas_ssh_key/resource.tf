@@ -0,0 +1,8 @@
+resource "maas_ssh_key" "operator" {
+  key = file("~/.ssh/id_ed25519.pub")
+}
+
+resource "maas_ssh_key" "tf_user" {
+  key = file("tf-user.pub")
+  user = maas_user.tf_user.name
+}
@@ -0,0 +1,2 @@
+# SSH key sources can be imported with the protocol and the username. e.g.
+$ terraform import maas_sshkey_source.ops gh:ops-user
@@ -0,0 +1,4 @@
+resource "maas_sshkey_source" "ops" {
+  protocol = "gh"
+  auth_id  = "ops-user"
+}
@@ -0,0 +1,2 @@
+# SSL keys can be imported with their ID. e.g.
+$ terraform import maas_ssl_key.operator 2
@@ -0,0 +1,3 @@
+resource "maas_ssl_key" "operator" {
+  key = file("operator.pem")
+}
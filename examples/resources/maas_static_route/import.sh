@@ -0,0 +1,2 @@
+# Static routes can be imported with their ID. e.g.
+$ terraform import maas_static_route.tf_static_route 2
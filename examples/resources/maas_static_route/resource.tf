@@ -0,0 +1,6 @@
+resource "maas_static_route" "tf_static_route" {
+  source = maas_subnet.tf_subnet.id
+  destination = maas_subnet.tf_subnet_2.id
+  gateway_ip = "10.88.88.254"
+  metric = 10
+}
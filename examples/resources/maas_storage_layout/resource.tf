@@ -0,0 +1,15 @@
+resource "maas_storage_layout" "machine" {
+  machine   = maas_machine.virsh_vm1.id
+  layout    = "lvm"
+  boot_size = "1G"
+  root_size = "100G"
+}
+
+resource "maas_block_device" "data" {
+  machine        = maas_machine.virsh_vm1.id
+  name           = "vdb"
+  id_path        = "/dev/vdb"
+  size_gigabytes = 100
+
+  depends_on = [maas_storage_layout.machine]
+}
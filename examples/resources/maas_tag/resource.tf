@@ -7,3 +7,10 @@ resource "maas_tag" "kvm" {
     maas_vm_host_machine.kvm[1].id,
   ]
 }
+
+resource "maas_tag" "nvme" {
+  name        = "nvme"
+  comment     = "Machines with NVMe storage"
+  definition  = "//node[@id=\"storage\"]/node[contains(@class, \"nvme\")]"
+  kernel_opts = "nvme_core.default_ps_max_latency_us=0"
+}
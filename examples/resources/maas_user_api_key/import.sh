@@ -0,0 +1,2 @@
+# API keys can be imported with their token key. e.g.
+$ terraform import maas_user_api_key.ci Wz8NbmzFNaZxnAFVvK
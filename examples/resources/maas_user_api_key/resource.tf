@@ -0,0 +1,8 @@
+resource "maas_user_api_key" "ci" {
+  name = "ci-pipeline"
+}
+
+output "ci_api_key" {
+  value     = maas_user_api_key.ci.api_key
+  sensitive = true
+}
@@ -1,2 +1,3 @@
-# Existing MAAS VLANs can be imported using the fabric identifier (ID or name) and the VLAN identifier (ID or traffic segregation ID). e.g.
+# Existing MAAS VLANs can be imported using the fabric identifier (ID or name) and the VLAN identifier (ID, traffic segregation ID, or name). e.g.
 $ terraform import maas_vlan.tf_vlan tf-fabric:14
+$ terraform import maas_vlan.tf_vlan tf-fabric:tf-vlan14
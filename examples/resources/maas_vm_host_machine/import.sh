@@ -1,2 +1,2 @@
-# VM host machines can be imported using the identifier of the MAAS machine (system ID, hostname, or FQDN). e.g.
+# VM host machines can be imported using the identifier of the MAAS machine (system ID, hostname, FQDN, or MAC address). e.g.
 $ terraform import maas_vm_host_machine.test machine-02
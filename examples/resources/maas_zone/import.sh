@@ -0,0 +1,2 @@
+# Zones can be imported with their ID or name. e.g.
+$ terraform import maas_zone.rack_a rack-a
@@ -0,0 +1,4 @@
+resource "maas_zone" "rack_a" {
+  name        = "rack-a"
+  description = "Machines in rack A"
+}
@@ -0,0 +1,84 @@
+package maas
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/maas/gomaasclient/client"
+)
+
+// AuthorisationToken represents a MAAS API token of the authenticated user.
+type AuthorisationToken struct {
+	Name        string `json:"name"`
+	ConsumerKey string `json:"consumer_key"`
+	TokenKey    string `json:"token_key"`
+	TokenSecret string `json:"token_secret"`
+}
+
+// Account implements the MAAS account operations of the authenticated user,
+// which are not covered by gomaasclient.
+type Account struct {
+	ApiClient client.ApiClient
+}
+
+func (a *Account) client() client.ApiClient {
+	return a.ApiClient.GetSubObject("account")
+}
+
+// CreateAuthorisationToken creates an API token with the given name.
+func (a *Account) CreateAuthorisationToken(name string) (token *AuthorisationToken, err error) {
+	qsp := url.Values{}
+	if name != "" {
+		qsp.Set("name", name)
+	}
+	token = new(AuthorisationToken)
+	err = a.client().Post("create_authorisation_token", qsp, func(data []byte) error {
+		return json.Unmarshal(data, token)
+	})
+	return
+}
+
+// ListAuthorisationTokens lists the API tokens. MAAS returns each token as
+// the `CONSUMER_KEY:TOKEN_KEY:TOKEN_SECRET` API key, which is split here.
+func (a *Account) ListAuthorisationTokens() (tokens []AuthorisationToken, err error) {
+	var apiKeys []struct {
+		Name  string `json:"name"`
+		Token string `json:"token"`
+	}
+	err = a.client().Get("list_authorisation_tokens", url.Values{}, func(data []byte) error {
+		return json.Unmarshal(data, &apiKeys)
+	})
+	if err != nil {
+		return
+	}
+	for _, k := range apiKeys {
+		parts := strings.Split(k.Token, ":")
+		if len(parts) != 3 {
+			return nil, fmt.Errorf("unexpected format of API key (%q)", k.Name)
+		}
+		tokens = append(tokens, AuthorisationToken{
+			Name:        k.Name,
+			ConsumerKey: parts[0],
+			TokenKey:    parts[1],
+			TokenSecret: parts[2],
+		})
+	}
+	return
+}
+
+// UpdateTokenName renames the API token with the given token key.
+func (a *Account) UpdateTokenName(tokenKey string, name string) error {
+	qsp := url.Values{}
+	qsp.Set("token", tokenKey)
+	qsp.Set("name", name)
+	return a.client().Post("update_token_name", qsp, func(data []byte) error { return nil })
+}
+
+// DeleteAuthorisationToken deletes the API token with the given token key.
+func (a *Account) DeleteAuthorisationToken(tokenKey string) error {
+	qsp := url.Values{}
+	qsp.Set("token_key", tokenKey)
+	return a.client().Post("delete_authorisation_token", qsp, func(data []byte) error { return nil })
+}
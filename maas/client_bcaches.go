@@ -0,0 +1,184 @@
+package maas
+
+import (
+	"encoding/json"
+	"net/url"
+	"strconv"
+
+	"github.com/maas/gomaasclient/client"
+)
+
+// BcacheCacheSet represents a MAAS bcache cache set.
+type BcacheCacheSet struct {
+	ID          int           `json:"id,omitempty"`
+	Name        string        `json:"name,omitempty"`
+	SystemID    string        `json:"system_id,omitempty"`
+	CacheDevice StorageDevice `json:"cache_device,omitempty"`
+}
+
+// BcacheCacheSetParams are the parameters used to create or update a bcache
+// cache set. Only one of the cache device or partition is set.
+type BcacheCacheSetParams struct {
+	CacheDevice    int
+	CachePartition int
+}
+
+// Bcache represents a MAAS bcache device.
+type Bcache struct {
+	ID            int            `json:"id,omitempty"`
+	Name          string         `json:"name,omitempty"`
+	UUID          string         `json:"uuid,omitempty"`
+	SystemID      string         `json:"system_id,omitempty"`
+	Size          int64          `json:"size,omitempty"`
+	CacheMode     string         `json:"cache_mode,omitempty"`
+	CacheSet      BcacheCacheSet `json:"cache_set,omitempty"`
+	BackingDevice StorageDevice  `json:"backing_device,omitempty"`
+	VirtualDevice StorageDevice  `json:"virtual_device,omitempty"`
+}
+
+// BcacheParams are the parameters used to create or update a bcache device.
+// Only one of the backing device or partition is set.
+type BcacheParams struct {
+	Name             string
+	UUID             string
+	CacheSet         int
+	BackingDevice    int
+	BackingPartition int
+	CacheMode        string
+}
+
+// BcacheCacheSets implements the MAAS bcache cache sets endpoints of a
+// machine, which are not covered by gomaasclient.
+type BcacheCacheSets struct {
+	ApiClient client.ApiClient
+}
+
+func (b *BcacheCacheSets) client(systemID string) client.ApiClient {
+	return b.ApiClient.GetSubObject("nodes").GetSubObject(systemID).GetSubObject("bcache-cache-sets")
+}
+
+func (b *BcacheCacheSets) cacheSetClient(systemID string, id int) client.ApiClient {
+	return b.ApiClient.GetSubObject("nodes").GetSubObject(systemID).GetSubObject("bcache-cache-set").GetSubObject(strconv.Itoa(id))
+}
+
+// Get the bcache cache sets list of the given machine.
+func (b *BcacheCacheSets) Get(systemID string) (cacheSets []BcacheCacheSet, err error) {
+	err = b.client(systemID).Get("", url.Values{}, func(data []byte) error {
+		return json.Unmarshal(data, &cacheSets)
+	})
+	return
+}
+
+// Create a bcache cache set on the given machine.
+func (b *BcacheCacheSets) Create(systemID string, params *BcacheCacheSetParams) (cacheSet *BcacheCacheSet, err error) {
+	cacheSet = new(BcacheCacheSet)
+	err = b.client(systemID).Post("", getBcacheCacheSetValues(params), func(data []byte) error {
+		return json.Unmarshal(data, cacheSet)
+	})
+	return
+}
+
+// GetByID returns the bcache cache set with the given ID.
+func (b *BcacheCacheSets) GetByID(systemID string, id int) (cacheSet *BcacheCacheSet, err error) {
+	cacheSet = new(BcacheCacheSet)
+	err = b.cacheSetClient(systemID, id).Get("", url.Values{}, func(data []byte) error {
+		return json.Unmarshal(data, cacheSet)
+	})
+	return
+}
+
+// Update the bcache cache set with the given ID.
+func (b *BcacheCacheSets) Update(systemID string, id int, params *BcacheCacheSetParams) (cacheSet *BcacheCacheSet, err error) {
+	cacheSet = new(BcacheCacheSet)
+	err = b.cacheSetClient(systemID, id).Put(getBcacheCacheSetValues(params), func(data []byte) error {
+		return json.Unmarshal(data, cacheSet)
+	})
+	return
+}
+
+// Delete the bcache cache set with the given ID.
+func (b *BcacheCacheSets) Delete(systemID string, id int) error {
+	return b.cacheSetClient(systemID, id).Delete()
+}
+
+func getBcacheCacheSetValues(params *BcacheCacheSetParams) url.Values {
+	qsp := url.Values{}
+	if params.CacheDevice != 0 {
+		qsp.Set("cache_device", strconv.Itoa(params.CacheDevice))
+	}
+	if params.CachePartition != 0 {
+		qsp.Set("cache_partition", strconv.Itoa(params.CachePartition))
+	}
+	return qsp
+}
+
+// Bcaches implements the MAAS bcache devices endpoints of a machine, which are
+// not covered by gomaasclient.
+type Bcaches struct {
+	ApiClient client.ApiClient
+}
+
+func (b *Bcaches) client(systemID string) client.ApiClient {
+	return b.ApiClient.GetSubObject("nodes").GetSubObject(systemID).GetSubObject("bcaches")
+}
+
+func (b *Bcaches) bcacheClient(systemID string, id int) client.ApiClient {
+	return b.ApiClient.GetSubObject("nodes").GetSubObject(systemID).GetSubObject("bcache").GetSubObject(strconv.Itoa(id))
+}
+
+// Get the bcache devices list of the given machine.
+func (b *Bcaches) Get(systemID string) (bcaches []Bcache, err error) {
+	err = b.client(systemID).Get("", url.Values{}, func(data []byte) error {
+		return json.Unmarshal(data, &bcaches)
+	})
+	return
+}
+
+// Create a bcache device on the given machine.
+func (b *Bcaches) Create(systemID string, params *BcacheParams) (bcache *Bcache, err error) {
+	bcache = new(Bcache)
+	err = b.client(systemID).Post("", getBcacheValues(params), func(data []byte) error {
+		return json.Unmarshal(data, bcache)
+	})
+	return
+}
+
+// GetByID returns the bcache device with the given ID.
+func (b *Bcaches) GetByID(systemID string, id int) (bcache *Bcache, err error) {
+	bcache = new(Bcache)
+	err = b.bcacheClient(systemID, id).Get("", url.Values{}, func(data []byte) error {
+		return json.Unmarshal(data, bcache)
+	})
+	return
+}
+
+// Update the bcache device with the given ID.
+func (b *Bcaches) Update(systemID string, id int, params *BcacheParams) (bcache *Bcache, err error) {
+	bcache = new(Bcache)
+	err = b.bcacheClient(systemID, id).Put(getBcacheValues(params), func(data []byte) error {
+		return json.Unmarshal(data, bcache)
+	})
+	return
+}
+
+// Delete the bcache device with the given ID.
+func (b *Bcaches) Delete(systemID string, id int) error {
+	return b.bcacheClient(systemID, id).Delete()
+}
+
+func getBcacheValues(params *BcacheParams) url.Values {
+	qsp := url.Values{}
+	qsp.Set("name", params.Name)
+	qsp.Set("cache_set", strconv.Itoa(params.CacheSet))
+	qsp.Set("cache_mode", params.CacheMode)
+	if params.UUID != "" {
+		qsp.Set("uuid", params.UUID)
+	}
+	if params.BackingDevice != 0 {
+		qsp.Set("backing_device", strconv.Itoa(params.BackingDevice))
+	}
+	if params.BackingPartition != 0 {
+		qsp.Set("backing_partition", strconv.Itoa(params.BackingPartition))
+	}
+	return qsp
+}
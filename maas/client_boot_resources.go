@@ -0,0 +1,67 @@
+package maas
+
+import (
+	"encoding/json"
+	"net/url"
+	"strconv"
+
+	"github.com/maas/gomaasclient/client"
+)
+
+// BootResource represents a MAAS boot resource (image).
+type BootResource struct {
+	ID           int                        `json:"id,omitempty"`
+	Type         string                     `json:"type,omitempty"`
+	Name         string                     `json:"name,omitempty"`
+	Architecture string                     `json:"architecture,omitempty"`
+	Sets         map[string]BootResourceSet `json:"sets,omitempty"`
+	ResourceURI  string                     `json:"resource_uri,omitempty"`
+}
+
+// BootResourceSet represents a version of a MAAS boot resource.
+type BootResourceSet struct {
+	Version  string `json:"version,omitempty"`
+	Label    string `json:"label,omitempty"`
+	Size     int64  `json:"size,omitempty"`
+	Complete bool   `json:"complete,omitempty"`
+	Progress int    `json:"progress,omitempty"`
+}
+
+// BootResources implements the MAAS boot resources endpoint, which is not covered by gomaasclient.
+type BootResources struct {
+	ApiClient client.ApiClient
+}
+
+func (b *BootResources) client() client.ApiClient {
+	return b.ApiClient.GetSubObject("boot-resources")
+}
+
+// Get the boot resources list. The list entries don't contain the resource sets.
+func (b *BootResources) Get() (bootResources []BootResource, err error) {
+	err = b.client().Get("", url.Values{}, func(data []byte) error {
+		return json.Unmarshal(data, &bootResources)
+	})
+	return
+}
+
+// GetByID returns the boot resource details, including its resource sets.
+func (b *BootResources) GetByID(id int) (bootResource *BootResource, err error) {
+	bootResource = new(BootResource)
+	err = b.client().GetSubObject(strconv.Itoa(id)).Get("", url.Values{}, func(data []byte) error {
+		return json.Unmarshal(data, bootResource)
+	})
+	return
+}
+
+// Import starts the import of the boot resources selected by the boot sources.
+func (b *BootResources) Import() error {
+	return b.client().Post("import", url.Values{}, func(data []byte) error { return nil })
+}
+
+// IsImporting returns whether the boot resources are currently being imported.
+func (b *BootResources) IsImporting() (isImporting bool, err error) {
+	err = b.client().Get("is_importing", url.Values{}, func(data []byte) error {
+		return json.Unmarshal(data, &isImporting)
+	})
+	return
+}
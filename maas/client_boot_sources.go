@@ -0,0 +1,155 @@
+package maas
+
+import (
+	"encoding/json"
+	"net/url"
+	"strconv"
+
+	"github.com/google/go-querystring/query"
+	"github.com/maas/gomaasclient/client"
+)
+
+// BootSource represents a MAAS boot source, the simplestreams mirror the boot
+// resources are imported from.
+type BootSource struct {
+	ID              int    `json:"id,omitempty"`
+	URL             string `json:"url,omitempty"`
+	KeyringFilename string `json:"keyring_filename,omitempty"`
+	Created         string `json:"created,omitempty"`
+	Updated         string `json:"updated,omitempty"`
+}
+
+// BootSourceSelection represents the boot resources selected to be imported
+// from a MAAS boot source.
+type BootSourceSelection struct {
+	ID           int      `json:"id,omitempty"`
+	BootSourceID int      `json:"boot_source_id,omitempty"`
+	OS           string   `json:"os,omitempty"`
+	Release      string   `json:"release,omitempty"`
+	Arches       []string `json:"arches,omitempty"`
+	Subarches    []string `json:"subarches,omitempty"`
+	Labels       []string `json:"labels,omitempty"`
+}
+
+// BootSourceParams enumerates the parameters used to create or update a boot
+// source. The keyring data can be set only when the boot source is created.
+type BootSourceParams struct {
+	URL             string
+	KeyringFilename string
+	KeyringData     []byte
+}
+
+// BootSourceSelectionParams enumerates the parameters used to create or
+// update a boot source selection.
+type BootSourceSelectionParams struct {
+	OS        string   `url:"os,omitempty"`
+	Release   string   `url:"release,omitempty"`
+	Arches    []string `url:"arches,omitempty"`
+	Subarches []string `url:"subarches,omitempty"`
+	Labels    []string `url:"labels,omitempty"`
+}
+
+// BootSources implements the MAAS boot sources endpoint, which is not covered by gomaasclient.
+type BootSources struct {
+	ApiClient client.ApiClient
+}
+
+func (b *BootSources) client(id int) client.ApiClient {
+	return b.ApiClient.GetSubObject("boot-sources").GetSubObject(strconv.Itoa(id))
+}
+
+// Get the boot source details.
+func (b *BootSources) Get(id int) (bootSource *BootSource, err error) {
+	bootSource = new(BootSource)
+	err = b.client(id).Get("", url.Values{}, func(data []byte) error {
+		return json.Unmarshal(data, bootSource)
+	})
+	return
+}
+
+// GetSelections returns the selections of the boot source.
+func (b *BootSources) GetSelections(id int) (selections []BootSourceSelection, err error) {
+	err = b.client(id).GetSubObject("selections").Get("", url.Values{}, func(data []byte) error {
+		return json.Unmarshal(data, &selections)
+	})
+	return
+}
+
+// Create a boot source. The keyring data is uploaded as a file, as MAAS
+// expects it.
+func (b *BootSources) Create(params *BootSourceParams) (bootSource *BootSource, err error) {
+	qsp := url.Values{}
+	qsp.Set("url", params.URL)
+	if params.KeyringFilename != "" {
+		qsp.Set("keyring_filename", params.KeyringFilename)
+	}
+	var files map[string][]byte
+	if len(params.KeyringData) > 0 {
+		files = map[string][]byte{"keyring_data": params.KeyringData}
+	}
+	res, err := b.ApiClient.GetSubObject("boot-sources").CallPostFiles("", qsp, files)
+	if err != nil {
+		return
+	}
+	data, err := res.GetBytes()
+	if err != nil {
+		return
+	}
+	bootSource = new(BootSource)
+	err = json.Unmarshal(data, bootSource)
+	return
+}
+
+// Update the boot source with the given ID.
+func (b *BootSources) Update(id int, params *BootSourceParams) (bootSource *BootSource, err error) {
+	qsp := url.Values{}
+	qsp.Set("url", params.URL)
+	if params.KeyringFilename != "" {
+		qsp.Set("keyring_filename", params.KeyringFilename)
+	}
+	bootSource = new(BootSource)
+	err = b.client(id).Put(qsp, func(data []byte) error {
+		return json.Unmarshal(data, bootSource)
+	})
+	return
+}
+
+// Delete the boot source with the given ID.
+func (b *BootSources) Delete(id int) error {
+	return b.client(id).Delete()
+}
+
+func (b *BootSources) selectionClient(id int, selectionID int) client.ApiClient {
+	return b.client(id).GetSubObject("selections").GetSubObject(strconv.Itoa(selectionID))
+}
+
+// CreateSelection creates a selection of the boot source.
+func (b *BootSources) CreateSelection(id int, params *BootSourceSelectionParams) (selection *BootSourceSelection, err error) {
+	qsp, err := query.Values(params)
+	if err != nil {
+		return
+	}
+	selection = new(BootSourceSelection)
+	err = b.client(id).GetSubObject("selections").Post("", qsp, func(data []byte) error {
+		return json.Unmarshal(data, selection)
+	})
+	return
+}
+
+// UpdateSelection updates the selection of the boot source.
+func (b *BootSources) UpdateSelection(id int, selectionID int, params *BootSourceSelectionParams) (selection *BootSourceSelection, err error) {
+	qsp, err := query.Values(params)
+	if err != nil {
+		return
+	}
+	selection = new(BootSourceSelection)
+	err = b.selectionClient(id, selectionID).Put(qsp, func(data []byte) error {
+		return json.Unmarshal(data, selection)
+	})
+	return
+}
+
+// DeleteSelection deletes the selection of the boot source.
+func (b *BootSources) DeleteSelection(id int, selectionID int) error {
+	return b.selectionClient(id, selectionID).Delete()
+}
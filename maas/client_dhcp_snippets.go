@@ -0,0 +1,96 @@
+package maas
+
+import (
+	"encoding/json"
+	"net/url"
+	"strconv"
+
+	"github.com/google/go-querystring/query"
+	"github.com/maas/gomaasclient/client"
+	"github.com/maas/gomaasclient/entity"
+)
+
+// DHCPSnippet represents a MAAS DHCP snippet.
+type DHCPSnippet struct {
+	ID            int              `json:"id"`
+	Name          string           `json:"name"`
+	Value         string           `json:"value"`
+	Description   string           `json:"description"`
+	Enabled       bool             `json:"enabled"`
+	Node          *DHCPSnippetNode `json:"node"`
+	Subnet        *entity.Subnet   `json:"subnet"`
+	GlobalSnippet bool             `json:"global_snippet"`
+	ResourceURI   string           `json:"resource_uri"`
+}
+
+// DHCPSnippetNode is the node a DHCP snippet is scoped to.
+type DHCPSnippetNode struct {
+	SystemID string `json:"system_id"`
+	Hostname string `json:"hostname"`
+}
+
+// DHCPSnippetParams enumerates the parameters used to create or update a DHCP
+// snippet.
+type DHCPSnippetParams struct {
+	Name          string `url:"name,omitempty"`
+	Value         string `url:"value,omitempty"`
+	Description   string `url:"description"`
+	Enabled       bool   `url:"enabled"`
+	Node          string `url:"node,omitempty"`
+	Subnet        string `url:"subnet,omitempty"`
+	GlobalSnippet bool   `url:"global_snippet,omitempty"`
+}
+
+// DHCPSnippets implements the MAAS DHCP snippet operations which are not
+// covered by gomaasclient.
+type DHCPSnippets struct {
+	ApiClient client.ApiClient
+}
+
+func (s *DHCPSnippets) client() client.ApiClient {
+	return s.ApiClient.GetSubObject("dhcp-snippets")
+}
+
+func (s *DHCPSnippets) dhcpSnippetClient(id int) client.ApiClient {
+	return s.client().GetSubObject(strconv.Itoa(id))
+}
+
+// Get all the DHCP snippets.
+func (s *DHCPSnippets) Get() (dhcpSnippets []DHCPSnippet, err error) {
+	err = s.client().Get("", url.Values{}, func(data []byte) error {
+		return json.Unmarshal(data, &dhcpSnippets)
+	})
+	return
+}
+
+// Create a DHCP snippet. MAAS validates that the DHCP configuration compiles
+// with the snippet before saving it.
+func (s *DHCPSnippets) Create(params *DHCPSnippetParams) (dhcpSnippet *DHCPSnippet, err error) {
+	qsp, err := query.Values(params)
+	if err != nil {
+		return
+	}
+	dhcpSnippet = new(DHCPSnippet)
+	err = s.client().Post("", qsp, func(data []byte) error {
+		return json.Unmarshal(data, dhcpSnippet)
+	})
+	return
+}
+
+// Update the DHCP snippet with the given ID.
+func (s *DHCPSnippets) Update(id int, params *DHCPSnippetParams) (dhcpSnippet *DHCPSnippet, err error) {
+	qsp, err := query.Values(params)
+	if err != nil {
+		return
+	}
+	dhcpSnippet = new(DHCPSnippet)
+	err = s.dhcpSnippetClient(id).Put(qsp, func(data []byte) error {
+		return json.Unmarshal(data, dhcpSnippet)
+	})
+	return
+}
+
+// Delete the DHCP snippet with the given ID.
+func (s *DHCPSnippets) Delete(id int) error {
+	return s.dhcpSnippetClient(id).Delete()
+}
@@ -0,0 +1,51 @@
+package maas
+
+import (
+	"encoding/json"
+
+	"github.com/google/go-querystring/query"
+	"github.com/maas/gomaasclient/client"
+)
+
+// Event represents a MAAS event.
+type Event struct {
+	ID          int    `json:"id,omitempty"`
+	Node        string `json:"node,omitempty"`
+	Hostname    string `json:"hostname,omitempty"`
+	Username    string `json:"username,omitempty"`
+	Level       string `json:"level,omitempty"`
+	Created     string `json:"created,omitempty"`
+	Type        string `json:"type,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+// EventsParams enumerates the parameters for the events query operation.
+type EventsParams struct {
+	ID    []string `url:"id,omitempty"`
+	Level string   `url:"level,omitempty"`
+	Limit int      `url:"limit,omitempty"`
+}
+
+// Events implements the MAAS events endpoint, which is not covered by gomaasclient.
+type Events struct {
+	ApiClient client.ApiClient
+}
+
+func (e *Events) client() client.ApiClient {
+	return e.ApiClient.GetSubObject("events")
+}
+
+// Query returns the events matching the given parameters, the newest first.
+func (e *Events) Query(params *EventsParams) (events []Event, err error) {
+	qsp, err := query.Values(params)
+	if err != nil {
+		return
+	}
+	result := struct {
+		Events []Event `json:"events"`
+	}{}
+	err = e.client().Get("query", qsp, func(data []byte) error {
+		return json.Unmarshal(data, &result)
+	})
+	return result.Events, err
+}
@@ -0,0 +1,42 @@
+package maas
+
+import (
+	"encoding/json"
+
+	"github.com/google/go-querystring/query"
+	"github.com/maas/gomaasclient/client"
+	"github.com/maas/gomaasclient/entity"
+)
+
+// IPAddressReserveParams contains the parameters of an IP address reservation.
+// Unlike gomaasclient's IPAddressesParams, it includes the MAC address and
+// comment of the reservation.
+type IPAddressReserveParams struct {
+	Subnet  string `url:"subnet,omitempty"`
+	IP      string `url:"ip,omitempty"`
+	MAC     string `url:"mac,omitempty"`
+	Comment string `url:"comment,omitempty"`
+}
+
+// IPAddresses implements the MAAS IP addresses reserve operation with all its
+// parameters, which is not fully covered by gomaasclient.
+type IPAddresses struct {
+	ApiClient client.ApiClient
+}
+
+func (i *IPAddresses) client() client.ApiClient {
+	return i.ApiClient.GetSubObject("ipaddresses")
+}
+
+// Reserve reserves a static IP address.
+func (i *IPAddresses) Reserve(params *IPAddressReserveParams) (ipAddress *entity.IPAddress, err error) {
+	qsp, err := query.Values(params)
+	if err != nil {
+		return
+	}
+	ipAddress = new(entity.IPAddress)
+	err = i.client().Post("reserve", qsp, func(data []byte) error {
+		return json.Unmarshal(data, ipAddress)
+	})
+	return
+}
@@ -0,0 +1,36 @@
+package maas
+
+import (
+	"net/url"
+
+	"github.com/maas/gomaasclient/client"
+)
+
+// MAASServer implements the api.MAASServer interface, used to get and set
+// the MAAS global configuration settings.
+type MAASServer struct {
+	ApiClient client.ApiClient
+}
+
+func (m *MAASServer) client() client.ApiClient {
+	return m.ApiClient.GetSubObject("maas")
+}
+
+// Get returns the JSON encoded value of the given config setting.
+func (m *MAASServer) Get(name string) (value string, err error) {
+	qsp := url.Values{}
+	qsp.Set("name", name)
+	err = m.client().Get("get_config", qsp, func(data []byte) error {
+		value = string(data)
+		return nil
+	})
+	return
+}
+
+// Post sets the value of the given config setting.
+func (m *MAASServer) Post(name, value string) error {
+	qsp := url.Values{}
+	qsp.Set("name", name)
+	qsp.Set("value", value)
+	return m.client().Post("set_config", qsp, func(data []byte) error { return nil })
+}
@@ -0,0 +1,208 @@
+package maas
+
+import (
+	"encoding/json"
+	"net/url"
+
+	"github.com/google/go-querystring/query"
+	"github.com/maas/gomaasclient/client"
+	"github.com/maas/gomaasclient/entity"
+)
+
+// Machine implements the MAAS machine operations which are not covered by
+// gomaasclient. The operations changing the machine invalidate the machines
+// list cache shared with the gomaasclient machine endpoints.
+type Machine struct {
+	ApiClient client.ApiClient
+	cache     *machineCache
+}
+
+// MachineReleaseParams enumerates the parameters for the machine release operation.
+type MachineReleaseParams struct {
+	Comment     string `url:"comment,omitempty"`
+	Erase       bool   `url:"erase,omitempty"`
+	SecureErase bool   `url:"secure_erase,omitempty"`
+	QuickErase  bool   `url:"quick_erase,omitempty"`
+	Force       bool   `url:"force,omitempty"`
+}
+
+// MachineDeployParams enumerates the parameters for the machine deploy
+// operation, including the ones missing from gomaasclient.
+type MachineDeployParams struct {
+	entity.MachineDeployParams
+	EphemeralDeploy bool `url:"ephemeral_deploy,omitempty"`
+}
+
+func (m *Machine) client(systemID string) client.ApiClient {
+	return m.ApiClient.GetSubObject("machines").GetSubObject(systemID)
+}
+
+func (m *Machine) invalidateCache() {
+	if m.cache != nil {
+		m.cache.invalidate()
+	}
+}
+
+// Release the machine.
+func (m *Machine) Release(systemID string, params *MachineReleaseParams) (machine *entity.Machine, err error) {
+	defer m.invalidateCache()
+	qsp, err := query.Values(params)
+	if err != nil {
+		return
+	}
+	machine = new(entity.Machine)
+	err = m.client(systemID).Post("release", qsp, func(data []byte) error {
+		return ignoreSpecialFilesystemsError(json.Unmarshal(data, machine))
+	})
+	return
+}
+
+// Deploy the machine.
+func (m *Machine) Deploy(systemID string, params *MachineDeployParams) (machine *entity.Machine, err error) {
+	defer m.invalidateCache()
+	qsp, err := query.Values(params)
+	if err != nil {
+		return
+	}
+	machine = new(entity.Machine)
+	err = m.client(systemID).Post("deploy", qsp, func(data []byte) error {
+		return ignoreSpecialFilesystemsError(json.Unmarshal(data, machine))
+	})
+	return
+}
+
+// Unlock the machine. gomaasclient only implements the lock operation.
+func (m *Machine) Unlock(systemID string, comment string) (machine *entity.Machine, err error) {
+	defer m.invalidateCache()
+	qsp := make(url.Values)
+	if comment != "" {
+		qsp.Set("comment", comment)
+	}
+	machine = new(entity.Machine)
+	err = m.client(systemID).Post("unlock", qsp, func(data []byte) error {
+		return ignoreSpecialFilesystemsError(json.Unmarshal(data, machine))
+	})
+	return
+}
+
+// PowerOn the machine.
+func (m *Machine) PowerOn(systemID string, comment string) (machine *entity.Machine, err error) {
+	return m.power(systemID, "power_on", comment)
+}
+
+// PowerOff the machine.
+func (m *Machine) PowerOff(systemID string, comment string) (machine *entity.Machine, err error) {
+	return m.power(systemID, "power_off", comment)
+}
+
+func (m *Machine) power(systemID string, op string, comment string) (machine *entity.Machine, err error) {
+	defer m.invalidateCache()
+	qsp := make(url.Values)
+	if comment != "" {
+		qsp.Set("comment", comment)
+	}
+	machine = new(entity.Machine)
+	err = m.client(systemID).Post(op, qsp, func(data []byte) error {
+		return ignoreSpecialFilesystemsError(json.Unmarshal(data, machine))
+	})
+	return
+}
+
+// QueryPowerState queries the machine BMC for its current power state (e.g.
+// `on`, `off`, `unknown`), instead of returning the last state known by MAAS.
+func (m *Machine) QueryPowerState(systemID string) (string, error) {
+	powerState := struct {
+		State string `json:"state"`
+	}{}
+	err := m.client(systemID).Get("query_power_state", url.Values{}, func(data []byte) error {
+		return json.Unmarshal(data, &powerState)
+	})
+	return powerState.State, err
+}
+
+// MachineStorageLayoutParams enumerates the parameters for the machine set storage layout operation.
+type MachineStorageLayoutParams struct {
+	StorageLayout string `url:"storage_layout"`
+	BootSize      int64  `url:"boot_size,omitempty"`
+	RootSize      int64  `url:"root_size,omitempty"`
+	RootDevice    string `url:"root_device,omitempty"`
+	VGName        string `url:"vg_name,omitempty"`
+	LVName        string `url:"lv_name,omitempty"`
+	LVSize        int64  `url:"lv_size,omitempty"`
+	CacheDevice   string `url:"cache_device,omitempty"`
+	CacheMode     string `url:"cache_mode,omitempty"`
+	CacheSize     int64  `url:"cache_size,omitempty"`
+	CacheNoPart   bool   `url:"cache_no_part,omitempty"`
+}
+
+// SetStorageLayout replaces the machine storage configuration with the given layout.
+func (m *Machine) SetStorageLayout(systemID string, params *MachineStorageLayoutParams) (machine *entity.Machine, err error) {
+	defer m.invalidateCache()
+	qsp, err := query.Values(params)
+	if err != nil {
+		return
+	}
+	machine = new(entity.Machine)
+	err = m.client(systemID).Post("set_storage_layout", qsp, func(data []byte) error {
+		return ignoreSpecialFilesystemsError(json.Unmarshal(data, machine))
+	})
+	return
+}
+
+// SetOwnerData sets the given owner data (workload annotations) keys on the
+// machine. The keys with an empty value are removed.
+func (m *Machine) SetOwnerData(systemID string, ownerData map[string]string) (machine *entity.Machine, err error) {
+	defer m.invalidateCache()
+	qsp := make(url.Values)
+	for k, v := range ownerData {
+		qsp.Set(k, v)
+	}
+	machine = new(entity.Machine)
+	err = m.client(systemID).Post("set_owner_data", qsp, func(data []byte) error {
+		return ignoreSpecialFilesystemsError(json.Unmarshal(data, machine))
+	})
+	return
+}
+
+// SpecialFilesystem represents a special file system (e.g. tmpfs) mounted on a
+// machine, which isn't backed by a storage device.
+type SpecialFilesystem struct {
+	FSType       string `json:"fstype,omitempty"`
+	Label        string `json:"label,omitempty"`
+	UUID         string `json:"uuid,omitempty"`
+	MountPoint   string `json:"mount_point,omitempty"`
+	MountOptions string `json:"mount_options,omitempty"`
+}
+
+// GetSpecialFilesystems returns the special file systems mounted on the
+// machine, which gomaasclient doesn't decode.
+func (m *Machine) GetSpecialFilesystems(systemID string) ([]SpecialFilesystem, error) {
+	machine := struct {
+		SpecialFilesystems []SpecialFilesystem `json:"special_filesystems"`
+	}{}
+	err := m.client(systemID).Get("", url.Values{}, func(data []byte) error {
+		return json.Unmarshal(data, &machine)
+	})
+	return machine.SpecialFilesystems, err
+}
+
+// MountSpecial mounts a special file system (e.g. tmpfs) on the machine.
+func (m *Machine) MountSpecial(systemID string, fsType string, mountPoint string, mountOptions string) error {
+	defer m.invalidateCache()
+	qsp := make(url.Values)
+	qsp.Set("fstype", fsType)
+	qsp.Set("mount_point", mountPoint)
+	if mountOptions != "" {
+		qsp.Set("mount_options", mountOptions)
+	}
+	return m.client(systemID).Post("mount_special", qsp, func(data []byte) error { return nil })
+}
+
+// UnmountSpecial unmounts the special file system mounted at the given mount
+// point of the machine.
+func (m *Machine) UnmountSpecial(systemID string, mountPoint string) error {
+	defer m.invalidateCache()
+	qsp := make(url.Values)
+	qsp.Set("mount_point", mountPoint)
+	return m.client(systemID).Post("unmount_special", qsp, func(data []byte) error { return nil })
+}
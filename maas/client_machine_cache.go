@@ -0,0 +1,192 @@
+package maas
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"sync"
+
+	"github.com/maas/gomaasclient/api"
+	"github.com/maas/gomaasclient/client"
+	"github.com/maas/gomaasclient/entity"
+)
+
+// machineCache holds the MAAS machines list, so the repeated machine
+// identifier lookups done during a single Terraform operation (e.g. by
+// getMachine) are served with one API request. It's used only to resolve the
+// identifiers to system IDs, since many endpoints (e.g. tags, network
+// interfaces, block devices) change the machine details without invalidating
+// it, so the machine details are always fetched fresh.
+type machineCache struct {
+	mu       sync.Mutex
+	machines []entity.Machine
+}
+
+func (c *machineCache) get() []entity.Machine {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.machines
+}
+
+func (c *machineCache) set(machines []entity.Machine) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.machines = machines
+}
+
+func (c *machineCache) invalidate() {
+	c.set(nil)
+}
+
+// update replaces the cached copy of the given machine with a fresh one.
+func (c *machineCache) update(machine *entity.Machine) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for i := range c.machines {
+		if c.machines[i].SystemID == machine.SystemID {
+			c.machines[i] = *machine
+			return
+		}
+	}
+}
+
+// cachedMachines implements the api.Machines interface, caching the machines list.
+type cachedMachines struct {
+	api.Machines
+	cache *machineCache
+}
+
+// Get returns a fresh machines list, which also refreshes the cache.
+func (m *cachedMachines) Get() ([]entity.Machine, error) {
+	machines, err := m.Machines.Get()
+	if err := ignoreSpecialFilesystemsError(err); err != nil {
+		return nil, err
+	}
+	m.cache.set(machines)
+	return machines, nil
+}
+
+// lookup returns the cached machines list, fetching it only if it's not
+// cached.
+func (m *cachedMachines) lookup() ([]entity.Machine, error) {
+	if machines := m.cache.get(); machines != nil {
+		return machines, nil
+	}
+	return m.Get()
+}
+
+func (m *cachedMachines) Create(machineParams *entity.MachineParams, powerParams map[string]string) (*entity.Machine, error) {
+	defer m.cache.invalidate()
+	machine, err := m.Machines.Create(machineParams, powerParams)
+	return machine, ignoreSpecialFilesystemsError(err)
+}
+
+func (m *cachedMachines) Allocate(params *entity.MachineAllocateParams) (*entity.Machine, error) {
+	defer m.cache.invalidate()
+	machine, err := m.Machines.Allocate(params)
+	return machine, ignoreSpecialFilesystemsError(err)
+}
+
+func (m *cachedMachines) Release(systemID []string, comment string) error {
+	defer m.cache.invalidate()
+	return m.Machines.Release(systemID, comment)
+}
+
+// cachedMachine implements the api.Machine interface, keeping the machines
+// list cache up to date.
+type cachedMachine struct {
+	api.Machine
+	cache *machineCache
+}
+
+func (m *cachedMachine) Get(systemID string) (*entity.Machine, error) {
+	machine, err := m.Machine.Get(systemID)
+	if err := ignoreSpecialFilesystemsError(err); err != nil {
+		return nil, err
+	}
+	m.cache.update(machine)
+	return machine, nil
+}
+
+func (m *cachedMachine) Update(systemID string, machineParams *entity.MachineParams, powerParams map[string]string) (*entity.Machine, error) {
+	defer m.cache.invalidate()
+	machine, err := m.Machine.Update(systemID, machineParams, powerParams)
+	return machine, ignoreSpecialFilesystemsError(err)
+}
+
+func (m *cachedMachine) Delete(systemID string) error {
+	defer m.cache.invalidate()
+	return m.Machine.Delete(systemID)
+}
+
+func (m *cachedMachine) Commission(systemID string, params *entity.MachineCommissionParams) (*entity.Machine, error) {
+	defer m.cache.invalidate()
+	machine, err := m.Machine.Commission(systemID, params)
+	return machine, ignoreSpecialFilesystemsError(err)
+}
+
+func (m *cachedMachine) Deploy(systemID string, params *entity.MachineDeployParams) (*entity.Machine, error) {
+	defer m.cache.invalidate()
+	machine, err := m.Machine.Deploy(systemID, params)
+	return machine, ignoreSpecialFilesystemsError(err)
+}
+
+func (m *cachedMachine) Lock(systemID string, comment string) (*entity.Machine, error) {
+	defer m.cache.invalidate()
+	machine, err := m.Machine.Lock(systemID, comment)
+	return machine, ignoreSpecialFilesystemsError(err)
+}
+
+func (m *cachedMachine) ClearDefaultGateways(systemID string) (*entity.Machine, error) {
+	defer m.cache.invalidate()
+	machine, err := m.Machine.ClearDefaultGateways(systemID)
+	return machine, ignoreSpecialFilesystemsError(err)
+}
+
+// cachedVMHost implements the api.VMHost interface, invalidating the machines
+// list cache when new machines are composed.
+type cachedVMHost struct {
+	api.VMHost
+	cache *machineCache
+}
+
+func (v *cachedVMHost) Compose(id int, params *entity.VMHostMachineParams) (*entity.Machine, error) {
+	defer v.cache.invalidate()
+	machine, err := v.VMHost.Compose(id, params)
+	return machine, ignoreSpecialFilesystemsError(err)
+}
+
+func (v *cachedVMHost) Delete(id int) error {
+	defer v.cache.invalidate()
+	return v.VMHost.Delete(id)
+}
+
+// ignoreSpecialFilesystemsError ignores the error of decoding the machine
+// special file systems, which gomaasclient types as a list of strings while
+// MAAS returns a list of objects. The rest of the machine is still decoded, so
+// the machines with special file systems (e.g. tmpfs) can be used.
+func ignoreSpecialFilesystemsError(err error) error {
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) && strings.Contains(typeErr.Field, "special_filesystems") {
+		return nil
+	}
+	return err
+}
+
+// getMachinesForLookup returns the machines list used to resolve a machine
+// identifier to a system ID, served from the cache if the client has one. The
+// returned machine details may be stale.
+func getMachinesForLookup(c *client.Client) ([]entity.Machine, error) {
+	if m, ok := c.Machines.(*cachedMachines); ok {
+		return m.lookup()
+	}
+	return c.Machines.Get()
+}
+
+// enableMachineCache wraps the machine endpoints of the given client with the
+// given machines list cache.
+func enableMachineCache(c *client.Client, cache *machineCache) {
+	c.Machines = &cachedMachines{Machines: c.Machines, cache: cache}
+	c.Machine = &cachedMachine{Machine: c.Machine, cache: cache}
+	c.VMHost = &cachedVMHost{VMHost: c.VMHost, cache: cache}
+}
@@ -0,0 +1,47 @@
+package maas
+
+import (
+	"encoding/json"
+	"net/url"
+	"strconv"
+
+	"github.com/maas/gomaasclient/client"
+)
+
+// NodeScriptResult represents the result of a script run on a MAAS node.
+type NodeScriptResult struct {
+	ID         int    `json:"id,omitempty"`
+	Name       string `json:"name,omitempty"`
+	Status     int    `json:"status,omitempty"`
+	StatusName string `json:"status_name,omitempty"`
+	ExitStatus int    `json:"exit_status,omitempty"`
+}
+
+// NodeScriptResultSet represents the results of the scripts run on a MAAS node
+// during a commissioning, testing or installation.
+type NodeScriptResultSet struct {
+	ID         int                `json:"id,omitempty"`
+	Type       int                `json:"type,omitempty"`
+	TypeName   string             `json:"type_name,omitempty"`
+	Status     int                `json:"status,omitempty"`
+	StatusName string             `json:"status_name,omitempty"`
+	Results    []NodeScriptResult `json:"results,omitempty"`
+}
+
+// NodeScriptResults implements the MAAS node script results endpoint, which is not covered by gomaasclient.
+type NodeScriptResults struct {
+	ApiClient client.ApiClient
+}
+
+func (n *NodeScriptResults) client(systemID string) client.ApiClient {
+	return n.ApiClient.GetSubObject("nodes").GetSubObject(systemID).GetSubObject("results")
+}
+
+// Get returns the script result set with the given ID.
+func (n *NodeScriptResults) Get(systemID string, id int) (resultSet *NodeScriptResultSet, err error) {
+	resultSet = new(NodeScriptResultSet)
+	err = n.client(systemID).GetSubObject(strconv.Itoa(id)).Get("", url.Values{}, func(data []byte) error {
+		return json.Unmarshal(data, resultSet)
+	})
+	return
+}
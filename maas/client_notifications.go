@@ -0,0 +1,95 @@
+package maas
+
+import (
+	"encoding/json"
+	"net/url"
+	"strconv"
+
+	"github.com/google/go-querystring/query"
+	"github.com/maas/gomaasclient/client"
+)
+
+// Notification represents a MAAS notification, shown as a banner in the MAAS UI.
+type Notification struct {
+	ID          int                    `json:"id,omitempty"`
+	Ident       string                 `json:"ident,omitempty"`
+	User        interface{}            `json:"user,omitempty"`
+	Users       bool                   `json:"users,omitempty"`
+	Admins      bool                   `json:"admins,omitempty"`
+	Message     string                 `json:"message,omitempty"`
+	Context     map[string]interface{} `json:"context,omitempty"`
+	Category    string                 `json:"category,omitempty"`
+	Dismissable bool                   `json:"dismissable,omitempty"`
+	ResourceURI string                 `json:"resource_uri,omitempty"`
+}
+
+// NotificationParams enumerates the parameters for the notification create and
+// update operations. The user and the context are always sent, so they can be
+// cleared: an empty user shows the notification globally.
+type NotificationParams struct {
+	Message     string `url:"message"`
+	Context     string `url:"context"`
+	Category    string `url:"category,omitempty"`
+	Ident       string `url:"ident,omitempty"`
+	User        string `url:"user"`
+	Users       bool   `url:"users"`
+	Admins      bool   `url:"admins"`
+	Dismissable bool   `url:"dismissable"`
+}
+
+// Notifications implements the MAAS notifications endpoint, which is not covered by gomaasclient.
+type Notifications struct {
+	ApiClient client.ApiClient
+}
+
+func (n *Notifications) client() client.ApiClient {
+	return n.ApiClient.GetSubObject("notifications")
+}
+
+// Get the notifications list.
+func (n *Notifications) Get() (notifications []Notification, err error) {
+	err = n.client().Get("", url.Values{}, func(data []byte) error {
+		return json.Unmarshal(data, &notifications)
+	})
+	return
+}
+
+// Create a notification.
+func (n *Notifications) Create(params *NotificationParams) (notification *Notification, err error) {
+	qsp, err := query.Values(params)
+	if err != nil {
+		return
+	}
+	notification = new(Notification)
+	err = n.client().Post("", qsp, func(data []byte) error {
+		return json.Unmarshal(data, notification)
+	})
+	return
+}
+
+// GetByID returns the notification with the given ID.
+func (n *Notifications) GetByID(id int) (notification *Notification, err error) {
+	notification = new(Notification)
+	err = n.client().GetSubObject(strconv.Itoa(id)).Get("", url.Values{}, func(data []byte) error {
+		return json.Unmarshal(data, notification)
+	})
+	return
+}
+
+// Update the notification with the given ID.
+func (n *Notifications) Update(id int, params *NotificationParams) (notification *Notification, err error) {
+	qsp, err := query.Values(params)
+	if err != nil {
+		return
+	}
+	notification = new(Notification)
+	err = n.client().GetSubObject(strconv.Itoa(id)).Put(qsp, func(data []byte) error {
+		return json.Unmarshal(data, notification)
+	})
+	return
+}
+
+// Delete the notification with the given ID.
+func (n *Notifications) Delete(id int) error {
+	return n.client().GetSubObject(strconv.Itoa(id)).Delete()
+}
@@ -0,0 +1,108 @@
+package maas
+
+import (
+	"encoding/json"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/maas/gomaasclient/client"
+)
+
+// PackageRepository represents a MAAS package repository.
+type PackageRepository struct {
+	ID                 int      `json:"id"`
+	Name               string   `json:"name"`
+	URL                string   `json:"url"`
+	Distributions      []string `json:"distributions"`
+	Components         []string `json:"components"`
+	Arches             []string `json:"arches"`
+	DisabledPockets    []string `json:"disabled_pockets"`
+	DisabledComponents []string `json:"disabled_components"`
+	DisableSources     bool     `json:"disable_sources"`
+	Key                string   `json:"key"`
+	Enabled            bool     `json:"enabled"`
+	ResourceURI        string   `json:"resource_uri"`
+}
+
+// PackageRepositoryParams enumerates the parameters used to create or update
+// a package repository. The nil lists are left unchanged.
+type PackageRepositoryParams struct {
+	Name               string
+	URL                string
+	Distributions      []string
+	Components         []string
+	Arches             []string
+	DisabledPockets    []string
+	DisabledComponents []string
+	DisableSources     bool
+	Key                string
+	Enabled            bool
+}
+
+func (p *PackageRepositoryParams) values() url.Values {
+	qsp := url.Values{}
+	qsp.Set("name", p.Name)
+	qsp.Set("url", p.URL)
+	setListValue := func(key string, values []string) {
+		if values != nil {
+			qsp.Set(key, strings.Join(values, ","))
+		}
+	}
+	setListValue("distributions", p.Distributions)
+	setListValue("components", p.Components)
+	setListValue("arches", p.Arches)
+	setListValue("disabled_pockets", p.DisabledPockets)
+	setListValue("disabled_components", p.DisabledComponents)
+	qsp.Set("disable_sources", strconv.FormatBool(p.DisableSources))
+	if p.Key != "" {
+		qsp.Set("key", p.Key)
+	}
+	qsp.Set("enabled", strconv.FormatBool(p.Enabled))
+	return qsp
+}
+
+// PackageRepositories implements the MAAS package repository operations
+// which are not covered by gomaasclient.
+type PackageRepositories struct {
+	ApiClient client.ApiClient
+}
+
+func (p *PackageRepositories) client() client.ApiClient {
+	return p.ApiClient.GetSubObject("package-repositories")
+}
+
+func (p *PackageRepositories) packageRepositoryClient(id int) client.ApiClient {
+	return p.client().GetSubObject(strconv.Itoa(id))
+}
+
+// Get all the package repositories.
+func (p *PackageRepositories) Get() (packageRepositories []PackageRepository, err error) {
+	err = p.client().Get("", url.Values{}, func(data []byte) error {
+		return json.Unmarshal(data, &packageRepositories)
+	})
+	return
+}
+
+// Create a package repository.
+func (p *PackageRepositories) Create(params *PackageRepositoryParams) (packageRepository *PackageRepository, err error) {
+	packageRepository = new(PackageRepository)
+	err = p.client().Post("", params.values(), func(data []byte) error {
+		return json.Unmarshal(data, packageRepository)
+	})
+	return
+}
+
+// Update the package repository with the given ID.
+func (p *PackageRepositories) Update(id int, params *PackageRepositoryParams) (packageRepository *PackageRepository, err error) {
+	packageRepository = new(PackageRepository)
+	err = p.packageRepositoryClient(id).Put(params.values(), func(data []byte) error {
+		return json.Unmarshal(data, packageRepository)
+	})
+	return
+}
+
+// Delete the package repository with the given ID.
+func (p *PackageRepositories) Delete(id int) error {
+	return p.packageRepositoryClient(id).Delete()
+}
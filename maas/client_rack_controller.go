@@ -0,0 +1,27 @@
+package maas
+
+import (
+	"encoding/json"
+	"net/url"
+
+	"github.com/maas/gomaasclient/client"
+	"github.com/maas/gomaasclient/entity"
+)
+
+// RackController implements the MAAS rack controller endpoint, which is not covered by gomaasclient.
+type RackController struct {
+	ApiClient client.ApiClient
+}
+
+func (r *RackController) client(systemID string) client.ApiClient {
+	return r.ApiClient.GetSubObject("rackcontrollers").GetSubObject(systemID)
+}
+
+// Get rack controller details.
+func (r *RackController) Get(systemID string) (rackController *entity.RackController, err error) {
+	rackController = new(entity.RackController)
+	err = r.client(systemID).Get("", url.Values{}, func(data []byte) error {
+		return json.Unmarshal(data, rackController)
+	})
+	return
+}
@@ -0,0 +1,30 @@
+package maas
+
+import (
+	"encoding/json"
+	"net/url"
+
+	"github.com/maas/gomaasclient/client"
+	"github.com/maas/gomaasclient/entity"
+)
+
+// RackControllers implements the MAAS rack controllers endpoint, which is not covered by gomaasclient.
+type RackControllers struct {
+	ApiClient client.ApiClient
+}
+
+func (r *RackControllers) client() client.ApiClient {
+	return r.ApiClient.GetSubObject("rackcontrollers")
+}
+
+// Get the rack controllers with the given hostname, or all of them if the hostname is empty.
+func (r *RackControllers) Get(hostname string) (rackControllers []entity.RackController, err error) {
+	qsp := url.Values{}
+	if hostname != "" {
+		qsp.Set("hostname", hostname)
+	}
+	err = r.client().Get("", qsp, func(data []byte) error {
+		return json.Unmarshal(data, &rackControllers)
+	})
+	return
+}
@@ -0,0 +1,30 @@
+package maas
+
+import (
+	"encoding/json"
+	"net/url"
+
+	"github.com/maas/gomaasclient/client"
+	"github.com/maas/gomaasclient/entity"
+)
+
+// RegionControllers implements the MAAS region controllers endpoint, which is not covered by gomaasclient.
+type RegionControllers struct {
+	ApiClient client.ApiClient
+}
+
+func (r *RegionControllers) client() client.ApiClient {
+	return r.ApiClient.GetSubObject("regioncontrollers")
+}
+
+// Get the region controllers with the given hostname, or all of them if the hostname is empty.
+func (r *RegionControllers) Get(hostname string) (regionControllers []entity.Machine, err error) {
+	qsp := url.Values{}
+	if hostname != "" {
+		qsp.Set("hostname", hostname)
+	}
+	err = r.client().Get("", qsp, func(data []byte) error {
+		return json.Unmarshal(data, &regionControllers)
+	})
+	return
+}
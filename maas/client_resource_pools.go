@@ -0,0 +1,73 @@
+package maas
+
+import (
+	"encoding/json"
+	"net/url"
+	"strconv"
+
+	"github.com/google/go-querystring/query"
+	"github.com/maas/gomaasclient/client"
+	"github.com/maas/gomaasclient/entity"
+)
+
+// ResourcePoolParams enumerates the parameters used to create or update a
+// resource pool.
+type ResourcePoolParams struct {
+	Name        string `url:"name"`
+	Description string `url:"description"`
+}
+
+// ResourcePools implements the MAAS resource pools endpoint, which is not
+// covered by gomaasclient.
+type ResourcePools struct {
+	ApiClient client.ApiClient
+}
+
+func (r *ResourcePools) client() client.ApiClient {
+	return r.ApiClient.GetSubObject("resourcepools")
+}
+
+func (r *ResourcePools) Get() (resourcePools []entity.ResourcePool, err error) {
+	err = r.client().Get("", url.Values{}, func(data []byte) error {
+		return json.Unmarshal(data, &resourcePools)
+	})
+	return
+}
+
+// Create a resource pool.
+func (r *ResourcePools) Create(params *ResourcePoolParams) (resourcePool *entity.ResourcePool, err error) {
+	qsp, err := query.Values(params)
+	if err != nil {
+		return
+	}
+	resourcePool = new(entity.ResourcePool)
+	err = r.client().Post("", qsp, func(data []byte) error {
+		return json.Unmarshal(data, resourcePool)
+	})
+	return
+}
+
+// resourcePoolClient returns the client of the resource pool with the given
+// ID, which MAAS serves under the singular `resourcepool` endpoint.
+func (r *ResourcePools) resourcePoolClient(id int) client.ApiClient {
+	return r.ApiClient.GetSubObject("resourcepool").GetSubObject(strconv.Itoa(id))
+}
+
+// Update the resource pool with the given ID.
+func (r *ResourcePools) Update(id int, params *ResourcePoolParams) (resourcePool *entity.ResourcePool, err error) {
+	qsp, err := query.Values(params)
+	if err != nil {
+		return
+	}
+	resourcePool = new(entity.ResourcePool)
+	err = r.resourcePoolClient(id).Put(qsp, func(data []byte) error {
+		return json.Unmarshal(data, resourcePool)
+	})
+	return
+}
+
+// Delete the resource pool with the given ID. MAAS refuses to delete a pool
+// which still has machines.
+func (r *ResourcePools) Delete(id int) error {
+	return r.resourcePoolClient(id).Delete()
+}
@@ -0,0 +1,71 @@
+package maas
+
+import (
+	"encoding/json"
+	"net/url"
+	"strconv"
+
+	"github.com/maas/gomaasclient/client"
+)
+
+// SSHKey represents an SSH key of the MAAS user.
+type SSHKey struct {
+	ID          int    `json:"id,omitempty"`
+	Key         string `json:"key,omitempty"`
+	KeySource   string `json:"keysource,omitempty"`
+	ResourceURI string `json:"resource_uri,omitempty"`
+}
+
+// SSHKeys implements the MAAS SSH keys endpoint of the authenticated user,
+// which is not covered by gomaasclient.
+type SSHKeys struct {
+	ApiClient client.ApiClient
+}
+
+func (s *SSHKeys) client() client.ApiClient {
+	return s.ApiClient.GetSubObject("account").GetSubObject("prefs").GetSubObject("sshkeys")
+}
+
+// Get the SSH keys list.
+func (s *SSHKeys) Get() (sshKeys []SSHKey, err error) {
+	err = s.client().Get("", url.Values{}, func(data []byte) error {
+		return json.Unmarshal(data, &sshKeys)
+	})
+	return
+}
+
+// Create an SSH key. If the user is set, the key is added to the account of
+// that user instead of the authenticated one, which requires admin privileges.
+func (s *SSHKeys) Create(key string, user string) (sshKey *SSHKey, err error) {
+	qsp := url.Values{}
+	qsp.Set("key", key)
+	if user != "" {
+		qsp.Set("user", user)
+	}
+	sshKey = new(SSHKey)
+	err = s.client().Post("", qsp, func(data []byte) error {
+		return json.Unmarshal(data, sshKey)
+	})
+	return
+}
+
+// Get the SSH key with the given ID.
+func (s *SSHKeys) GetByID(id int) (sshKey *SSHKey, err error) {
+	sshKey = new(SSHKey)
+	err = s.client().GetSubObject(strconv.Itoa(id)).Get("", url.Values{}, func(data []byte) error {
+		return json.Unmarshal(data, sshKey)
+	})
+	return
+}
+
+// Import the SSH keys of the given key source (e.g. `lp:username`).
+func (s *SSHKeys) Import(keySource string) error {
+	qsp := url.Values{}
+	qsp.Set("keysource", keySource)
+	return s.client().Post("import", qsp, func(data []byte) error { return nil })
+}
+
+// Delete the SSH key with the given ID.
+func (s *SSHKeys) Delete(id int) error {
+	return s.client().GetSubObject(strconv.Itoa(id)).Delete()
+}
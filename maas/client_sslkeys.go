@@ -0,0 +1,59 @@
+package maas
+
+import (
+	"encoding/json"
+	"net/url"
+	"strconv"
+
+	"github.com/maas/gomaasclient/client"
+)
+
+// SSLKey represents an SSL key of the MAAS user.
+type SSLKey struct {
+	ID          int    `json:"id,omitempty"`
+	Key         string `json:"key,omitempty"`
+	ResourceURI string `json:"resource_uri,omitempty"`
+}
+
+// SSLKeys implements the MAAS SSL keys endpoint of the authenticated user,
+// which is not covered by gomaasclient.
+type SSLKeys struct {
+	ApiClient client.ApiClient
+}
+
+func (s *SSLKeys) client() client.ApiClient {
+	return s.ApiClient.GetSubObject("account").GetSubObject("prefs").GetSubObject("sslkeys")
+}
+
+// Get the SSL keys list.
+func (s *SSLKeys) Get() (sslKeys []SSLKey, err error) {
+	err = s.client().Get("", url.Values{}, func(data []byte) error {
+		return json.Unmarshal(data, &sslKeys)
+	})
+	return
+}
+
+// Create an SSL key.
+func (s *SSLKeys) Create(key string) (sslKey *SSLKey, err error) {
+	qsp := url.Values{}
+	qsp.Set("key", key)
+	sslKey = new(SSLKey)
+	err = s.client().Post("", qsp, func(data []byte) error {
+		return json.Unmarshal(data, sslKey)
+	})
+	return
+}
+
+// Get the SSL key with the given ID.
+func (s *SSLKeys) GetByID(id int) (sslKey *SSLKey, err error) {
+	sslKey = new(SSLKey)
+	err = s.client().GetSubObject(strconv.Itoa(id)).Get("", url.Values{}, func(data []byte) error {
+		return json.Unmarshal(data, sslKey)
+	})
+	return
+}
+
+// Delete the SSL key with the given ID.
+func (s *SSLKeys) Delete(id int) error {
+	return s.client().GetSubObject(strconv.Itoa(id)).Delete()
+}
@@ -0,0 +1,92 @@
+package maas
+
+import (
+	"encoding/json"
+	"net/url"
+	"strconv"
+
+	"github.com/google/go-querystring/query"
+	"github.com/maas/gomaasclient/client"
+	"github.com/maas/gomaasclient/entity"
+)
+
+// StaticRoute represents a MAAS static route between two subnets.
+type StaticRoute struct {
+	ID          int           `json:"id"`
+	Source      entity.Subnet `json:"source"`
+	Destination entity.Subnet `json:"destination"`
+	GatewayIP   string        `json:"gateway_ip"`
+	Metric      int           `json:"metric"`
+	ResourceURI string        `json:"resource_uri"`
+}
+
+// StaticRouteParams enumerates the parameters used to create or update a
+// static route.
+type StaticRouteParams struct {
+	Source      string `url:"source,omitempty"`
+	Destination string `url:"destination,omitempty"`
+	GatewayIP   string `url:"gateway_ip,omitempty"`
+	Metric      int    `url:"metric"`
+}
+
+// StaticRoutes implements the MAAS static route operations which are not
+// covered by gomaasclient.
+type StaticRoutes struct {
+	ApiClient client.ApiClient
+}
+
+func (s *StaticRoutes) client() client.ApiClient {
+	return s.ApiClient.GetSubObject("static-routes")
+}
+
+func (s *StaticRoutes) staticRouteClient(id int) client.ApiClient {
+	return s.client().GetSubObject(strconv.Itoa(id))
+}
+
+// Get all the static routes.
+func (s *StaticRoutes) Get() (staticRoutes []StaticRoute, err error) {
+	err = s.client().Get("", url.Values{}, func(data []byte) error {
+		return json.Unmarshal(data, &staticRoutes)
+	})
+	return
+}
+
+// Create a static route.
+func (s *StaticRoutes) Create(params *StaticRouteParams) (staticRoute *StaticRoute, err error) {
+	qsp, err := query.Values(params)
+	if err != nil {
+		return
+	}
+	staticRoute = new(StaticRoute)
+	err = s.client().Post("", qsp, func(data []byte) error {
+		return json.Unmarshal(data, staticRoute)
+	})
+	return
+}
+
+// Get the static route with the given ID.
+func (s *StaticRoutes) GetByID(id int) (staticRoute *StaticRoute, err error) {
+	staticRoute = new(StaticRoute)
+	err = s.staticRouteClient(id).Get("", url.Values{}, func(data []byte) error {
+		return json.Unmarshal(data, staticRoute)
+	})
+	return
+}
+
+// Update the static route with the given ID.
+func (s *StaticRoutes) Update(id int, params *StaticRouteParams) (staticRoute *StaticRoute, err error) {
+	qsp, err := query.Values(params)
+	if err != nil {
+		return
+	}
+	staticRoute = new(StaticRoute)
+	err = s.staticRouteClient(id).Put(qsp, func(data []byte) error {
+		return json.Unmarshal(data, staticRoute)
+	})
+	return
+}
+
+// Delete the static route with the given ID.
+func (s *StaticRoutes) Delete(id int) error {
+	return s.staticRouteClient(id).Delete()
+}
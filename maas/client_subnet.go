@@ -0,0 +1,53 @@
+package maas
+
+import (
+	"encoding/json"
+	"net/url"
+	"strconv"
+
+	"github.com/maas/gomaasclient/client"
+	"github.com/maas/gomaasclient/entity"
+)
+
+// Subnet implements the MAAS subnet operations which are not covered by gomaasclient.
+type Subnet struct {
+	ApiClient client.ApiClient
+}
+
+func (s *Subnet) client(id int) client.ApiClient {
+	return s.ApiClient.GetSubObject("subnets").GetSubObject(strconv.Itoa(id))
+}
+
+// SetActiveDiscovery enables or disables the active discovery of the subnet.
+// The gomaasclient subnet parameters don't include it.
+func (s *Subnet) SetActiveDiscovery(id int, activeDiscovery bool) (subnet *entity.Subnet, err error) {
+	qsp := make(url.Values)
+	qsp.Set("active_discovery", strconv.FormatBool(activeDiscovery))
+	subnet = new(entity.Subnet)
+	err = s.client(id).Put(qsp, func(data []byte) error {
+		return json.Unmarshal(data, subnet)
+	})
+	return
+}
+
+// GetDescription returns the subnet description, which the gomaasclient
+// subnet entity doesn't include.
+func (s *Subnet) GetDescription(id int) (string, error) {
+	subnet := struct {
+		Description string `json:"description"`
+	}{}
+	err := s.client(id).Get("", url.Values{}, func(data []byte) error {
+		return json.Unmarshal(data, &subnet)
+	})
+	return subnet.Description, err
+}
+
+// SetDescription sets the subnet description. The gomaasclient subnet
+// parameters omit an empty description, so it couldn't be cleared otherwise.
+func (s *Subnet) SetDescription(id int, description string) error {
+	qsp := make(url.Values)
+	qsp.Set("description", description)
+	return s.client(id).Put(qsp, func(data []byte) error {
+		return nil
+	})
+}
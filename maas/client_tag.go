@@ -0,0 +1,38 @@
+package maas
+
+import (
+	"encoding/json"
+	"net/url"
+
+	"github.com/maas/gomaasclient/client"
+	"github.com/maas/gomaasclient/entity"
+)
+
+// Tag implements the MAAS tag operations which are not covered by gomaasclient.
+type Tag struct {
+	ApiClient client.ApiClient
+}
+
+func (t *Tag) client(name string) client.ApiClient {
+	return t.ApiClient.GetSubObject("tags").GetSubObject(name)
+}
+
+// Rebuild triggers the evaluation of the tag definition against all the nodes.
+func (t *Tag) Rebuild(name string) error {
+	return t.client(name).Post("rebuild", url.Values{}, func(data []byte) error { return nil })
+}
+
+// Update the tag. Unlike the gomaasclient update, the empty definition,
+// comment and kernel options are sent, so they can be cleared.
+func (t *Tag) Update(name string, params *entity.TagParams) (tag *entity.Tag, err error) {
+	qsp := url.Values{}
+	qsp.Set("name", params.Name)
+	qsp.Set("definition", params.Definition)
+	qsp.Set("comment", params.Comment)
+	qsp.Set("kernel_opts", params.KernelOpts)
+	tag = new(entity.Tag)
+	err = t.client(name).Put(qsp, func(data []byte) error {
+		return json.Unmarshal(data, tag)
+	})
+	return
+}
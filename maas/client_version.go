@@ -0,0 +1,33 @@
+package maas
+
+import (
+	"encoding/json"
+	"net/url"
+
+	"github.com/maas/gomaasclient/client"
+)
+
+// MAASVersion represents the MAAS version information.
+type MAASVersion struct {
+	Version      string   `json:"version,omitempty"`
+	Subversion   string   `json:"subversion,omitempty"`
+	Capabilities []string `json:"capabilities,omitempty"`
+}
+
+// Version implements the MAAS version endpoint, which is not covered by gomaasclient.
+type Version struct {
+	ApiClient client.ApiClient
+}
+
+func (v *Version) client() client.ApiClient {
+	return v.ApiClient.GetSubObject("version")
+}
+
+// Get the MAAS version information.
+func (v *Version) Get() (version *MAASVersion, err error) {
+	version = new(MAASVersion)
+	err = v.client().Get("", url.Values{}, func(data []byte) error {
+		return json.Unmarshal(data, version)
+	})
+	return
+}
@@ -0,0 +1,52 @@
+package maas
+
+import (
+	"encoding/json"
+	"strconv"
+
+	"github.com/google/go-querystring/query"
+	"github.com/maas/gomaasclient/client"
+	"github.com/maas/gomaasclient/entity"
+)
+
+// VMHostParams enumerates the parameters used to create or update a VM host,
+// including the LXD ones missing from gomaasclient.
+type VMHostParams struct {
+	entity.VMHostParams
+	Project     string `url:"project,omitempty"`
+	Certificate string `url:"certificate,omitempty"`
+	Key         string `url:"key,omitempty"`
+	Password    string `url:"password,omitempty"`
+}
+
+// VMHosts implements the MAAS VM host operations whose parameters are not
+// fully covered by gomaasclient.
+type VMHosts struct {
+	ApiClient client.ApiClient
+}
+
+// Create a VM host.
+func (v *VMHosts) Create(params *VMHostParams) (vmHost *entity.VMHost, err error) {
+	qsp, err := query.Values(params)
+	if err != nil {
+		return
+	}
+	vmHost = new(entity.VMHost)
+	err = v.ApiClient.GetSubObject("pods").Post("", qsp, func(data []byte) error {
+		return json.Unmarshal(data, vmHost)
+	})
+	return
+}
+
+// Update the VM host.
+func (v *VMHosts) Update(id int, params *VMHostParams) (vmHost *entity.VMHost, err error) {
+	qsp, err := query.Values(params)
+	if err != nil {
+		return
+	}
+	vmHost = new(entity.VMHost)
+	err = v.ApiClient.GetSubObject("pods").GetSubObject(strconv.Itoa(id)).Put(qsp, func(data []byte) error {
+		return json.Unmarshal(data, vmHost)
+	})
+	return
+}
@@ -0,0 +1,163 @@
+package maas
+
+import (
+	"encoding/json"
+	"net/url"
+	"strconv"
+
+	"github.com/maas/gomaasclient/client"
+	"github.com/maas/gomaasclient/entity"
+)
+
+// StorageDevice represents a block device or a partition referenced by the
+// MAAS storage objects built on top of them (e.g. volume groups or bcaches).
+// The partitions have the `partition` type.
+type StorageDevice struct {
+	ID   int    `json:"id,omitempty"`
+	Name string `json:"name,omitempty"`
+	Type string `json:"type,omitempty"`
+	Path string `json:"path,omitempty"`
+	Size int64  `json:"size,omitempty"`
+}
+
+// VolumeGroup represents a MAAS LVM volume group. The gomaasclient volume
+// group entity doesn't type its fields.
+type VolumeGroup struct {
+	ID             int             `json:"id,omitempty"`
+	Name           string          `json:"name,omitempty"`
+	UUID           string          `json:"uuid,omitempty"`
+	SystemID       string          `json:"system_id,omitempty"`
+	Size           int64           `json:"size,omitempty"`
+	UsedSize       int64           `json:"used_size,omitempty"`
+	AvailableSize  int64           `json:"available_size,omitempty"`
+	HumanSize      string          `json:"human_size,omitempty"`
+	Devices        []StorageDevice `json:"devices,omitempty"`
+	LogicalVolumes []StorageDevice `json:"logical_volumes,omitempty"`
+}
+
+// VolumeGroupParams are the parameters used to create a volume group.
+type VolumeGroupParams struct {
+	Name         string
+	UUID         string
+	BlockDevices []int
+	Partitions   []int
+}
+
+// VolumeGroupUpdateParams are the parameters used to update a volume group.
+type VolumeGroupUpdateParams struct {
+	Name               string
+	UUID               string
+	AddBlockDevices    []int
+	RemoveBlockDevices []int
+	AddPartitions      []int
+	RemovePartitions   []int
+}
+
+// LogicalVolumeParams are the parameters used to create a logical volume.
+type LogicalVolumeParams struct {
+	Name string
+	UUID string
+	Size int64
+}
+
+// VolumeGroups implements the MAAS volume groups endpoints of a machine, which
+// are not covered by gomaasclient.
+type VolumeGroups struct {
+	ApiClient client.ApiClient
+}
+
+func (v *VolumeGroups) client(systemID string) client.ApiClient {
+	return v.ApiClient.GetSubObject("nodes").GetSubObject(systemID).GetSubObject("volume-groups")
+}
+
+func (v *VolumeGroups) volumeGroupClient(systemID string, id int) client.ApiClient {
+	return v.ApiClient.GetSubObject("nodes").GetSubObject(systemID).GetSubObject("volume-group").GetSubObject(strconv.Itoa(id))
+}
+
+// Get the volume groups list of the given machine.
+func (v *VolumeGroups) Get(systemID string) (volumeGroups []VolumeGroup, err error) {
+	err = v.client(systemID).Get("", url.Values{}, func(data []byte) error {
+		return json.Unmarshal(data, &volumeGroups)
+	})
+	return
+}
+
+// Create a volume group on the given machine.
+func (v *VolumeGroups) Create(systemID string, params *VolumeGroupParams) (volumeGroup *VolumeGroup, err error) {
+	qsp := url.Values{}
+	qsp.Set("name", params.Name)
+	if params.UUID != "" {
+		qsp.Set("uuid", params.UUID)
+	}
+	addIntValues(qsp, "block_devices", params.BlockDevices)
+	addIntValues(qsp, "partitions", params.Partitions)
+	volumeGroup = new(VolumeGroup)
+	err = v.client(systemID).Post("", qsp, func(data []byte) error {
+		return json.Unmarshal(data, volumeGroup)
+	})
+	return
+}
+
+// Get the volume group with the given ID.
+func (v *VolumeGroups) GetByID(systemID string, id int) (volumeGroup *VolumeGroup, err error) {
+	volumeGroup = new(VolumeGroup)
+	err = v.volumeGroupClient(systemID, id).Get("", url.Values{}, func(data []byte) error {
+		return json.Unmarshal(data, volumeGroup)
+	})
+	return
+}
+
+// Update the volume group with the given ID.
+func (v *VolumeGroups) Update(systemID string, id int, params *VolumeGroupUpdateParams) (volumeGroup *VolumeGroup, err error) {
+	qsp := url.Values{}
+	if params.Name != "" {
+		qsp.Set("name", params.Name)
+	}
+	if params.UUID != "" {
+		qsp.Set("uuid", params.UUID)
+	}
+	addIntValues(qsp, "add_block_devices", params.AddBlockDevices)
+	addIntValues(qsp, "remove_block_devices", params.RemoveBlockDevices)
+	addIntValues(qsp, "add_partitions", params.AddPartitions)
+	addIntValues(qsp, "remove_partitions", params.RemovePartitions)
+	volumeGroup = new(VolumeGroup)
+	err = v.volumeGroupClient(systemID, id).Put(qsp, func(data []byte) error {
+		return json.Unmarshal(data, volumeGroup)
+	})
+	return
+}
+
+// Delete the volume group with the given ID.
+func (v *VolumeGroups) Delete(systemID string, id int) error {
+	return v.volumeGroupClient(systemID, id).Delete()
+}
+
+// CreateLogicalVolume creates a logical volume in the volume group with the
+// given ID, and returns the virtual block device of the logical volume.
+func (v *VolumeGroups) CreateLogicalVolume(systemID string, id int, params *LogicalVolumeParams) (blockDevice *entity.BlockDevice, err error) {
+	qsp := url.Values{}
+	qsp.Set("name", params.Name)
+	qsp.Set("size", strconv.FormatInt(params.Size, 10))
+	if params.UUID != "" {
+		qsp.Set("uuid", params.UUID)
+	}
+	blockDevice = new(entity.BlockDevice)
+	err = v.volumeGroupClient(systemID, id).Post("create_logical_volume", qsp, func(data []byte) error {
+		return json.Unmarshal(data, blockDevice)
+	})
+	return
+}
+
+// DeleteLogicalVolume deletes the logical volume (virtual block device ID)
+// from the volume group with the given ID.
+func (v *VolumeGroups) DeleteLogicalVolume(systemID string, id int, logicalVolumeID int) error {
+	qsp := url.Values{}
+	qsp.Set("id", strconv.Itoa(logicalVolumeID))
+	return v.volumeGroupClient(systemID, id).Post("delete_logical_volume", qsp, func(data []byte) error { return nil })
+}
+
+func addIntValues(qsp url.Values, key string, values []int) {
+	for _, value := range values {
+		qsp.Add(key, strconv.Itoa(value))
+	}
+}
@@ -0,0 +1,64 @@
+package maas
+
+import (
+	"encoding/json"
+	"net/url"
+
+	"github.com/google/go-querystring/query"
+	"github.com/maas/gomaasclient/client"
+	"github.com/maas/gomaasclient/entity"
+)
+
+// ZoneParams enumerates the parameters used to create or update a zone.
+type ZoneParams struct {
+	Name        string `url:"name"`
+	Description string `url:"description"`
+}
+
+// Zones implements the MAAS zones endpoint, which is not covered by gomaasclient.
+type Zones struct {
+	ApiClient client.ApiClient
+}
+
+func (z *Zones) client() client.ApiClient {
+	return z.ApiClient.GetSubObject("zones")
+}
+
+func (z *Zones) Get() (zones []entity.Zone, err error) {
+	err = z.client().Get("", url.Values{}, func(data []byte) error {
+		return json.Unmarshal(data, &zones)
+	})
+	return
+}
+
+// Create a zone.
+func (z *Zones) Create(params *ZoneParams) (zone *entity.Zone, err error) {
+	qsp, err := query.Values(params)
+	if err != nil {
+		return
+	}
+	zone = new(entity.Zone)
+	err = z.client().Post("", qsp, func(data []byte) error {
+		return json.Unmarshal(data, zone)
+	})
+	return
+}
+
+// Update the zone with the given name.
+func (z *Zones) Update(name string, params *ZoneParams) (zone *entity.Zone, err error) {
+	qsp, err := query.Values(params)
+	if err != nil {
+		return
+	}
+	zone = new(entity.Zone)
+	err = z.client().GetSubObject(name).Put(qsp, func(data []byte) error {
+		return json.Unmarshal(data, zone)
+	})
+	return
+}
+
+// Delete the zone with the given name. MAAS moves its nodes to the default
+// zone.
+func (z *Zones) Delete(name string) error {
+	return z.client().GetSubObject(name).Delete()
+}
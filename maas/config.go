@@ -1,15 +1,235 @@
 package maas
 
 import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"time"
+
+	gomaasapi "github.com/juju/gomaasapi/v2"
+	"github.com/maas/gomaasclient/api"
 	"github.com/maas/gomaasclient/client"
 )
 
 type Config struct {
-	APIKey     string
-	APIURL     string
-	ApiVersion string
+	APIKey             string
+	APIURL             string
+	ApiVersion         string
+	HTTPTimeout        time.Duration
+	MaxIdleConnections int
+	DefaultZone        string
+	DefaultPool        string
+	DefaultDomain      string
+}
+
+// ClientConfig is the provider meta passed to every resource and data source.
+// Besides the gomaasclient client, it holds the MAAS API endpoints that are
+// not yet covered by gomaasclient, and the HTTP client used for the requests
+// outside of the MAAS API. The default zone, pool and domain are used by the
+// resources when their corresponding attribute is not set.
+type ClientConfig struct {
+	Client              *client.Client
+	HTTPClient          *http.Client
+	MAASServer          api.MAASServer
+	Zones               *Zones
+	ResourcePools       *ResourcePools
+	Machine             *Machine
+	RackController      *RackController
+	RackControllers     *RackControllers
+	RegionControllers   *RegionControllers
+	BootResources       *BootResources
+	BootSources         *BootSources
+	Version             *Version
+	IPAddresses         *IPAddresses
+	Tag                 *Tag
+	Notifications       *Notifications
+	SSHKeys             *SSHKeys
+	SSLKeys             *SSLKeys
+	Account             *Account
+	Events              *Events
+	NodeScriptResults   *NodeScriptResults
+	Subnet              *Subnet
+	VolumeGroups        *VolumeGroups
+	BcacheCacheSets     *BcacheCacheSets
+	Bcaches             *Bcaches
+	VMHosts             *VMHosts
+	StaticRoutes        *StaticRoutes
+	DHCPSnippets        *DHCPSnippets
+	PackageRepositories *PackageRepositories
+	DefaultZone         string
+	DefaultPool         string
+	DefaultDomain       string
+
+	config       *Config
+	apiClient    *client.ApiClient
+	machineCache *machineCache
+}
+
+func (c *Config) Client() (*ClientConfig, error) {
+	apiClient, err := c.getApiClient(c.getHTTPClient())
+	if err != nil {
+		return nil, err
+	}
+	return c.newClientConfig(apiClient, &machineCache{}), nil
+}
+
+// newClientConfig returns the client config making its requests with the
+// given API client, and sharing the given machines list cache.
+func (c *Config) newClientConfig(apiClient *client.ApiClient, machineCache *machineCache) *ClientConfig {
+	maasClient := getClient(apiClient)
+	enableMachineCache(maasClient, machineCache)
+	return &ClientConfig{
+		Client:              maasClient,
+		HTTPClient:          apiClient.AuthClient.HTTPClient,
+		MAASServer:          &MAASServer{ApiClient: *apiClient},
+		Zones:               &Zones{ApiClient: *apiClient},
+		ResourcePools:       &ResourcePools{ApiClient: *apiClient},
+		Machine:             &Machine{ApiClient: *apiClient, cache: machineCache},
+		RackController:      &RackController{ApiClient: *apiClient},
+		RackControllers:     &RackControllers{ApiClient: *apiClient},
+		RegionControllers:   &RegionControllers{ApiClient: *apiClient},
+		BootResources:       &BootResources{ApiClient: *apiClient},
+		BootSources:         &BootSources{ApiClient: *apiClient},
+		Version:             &Version{ApiClient: *apiClient},
+		IPAddresses:         &IPAddresses{ApiClient: *apiClient},
+		Tag:                 &Tag{ApiClient: *apiClient},
+		Notifications:       &Notifications{ApiClient: *apiClient},
+		SSHKeys:             &SSHKeys{ApiClient: *apiClient},
+		SSLKeys:             &SSLKeys{ApiClient: *apiClient},
+		Account:             &Account{ApiClient: *apiClient},
+		Events:              &Events{ApiClient: *apiClient},
+		NodeScriptResults:   &NodeScriptResults{ApiClient: *apiClient},
+		Subnet:              &Subnet{ApiClient: *apiClient},
+		VolumeGroups:        &VolumeGroups{ApiClient: *apiClient},
+		BcacheCacheSets:     &BcacheCacheSets{ApiClient: *apiClient},
+		Bcaches:             &Bcaches{ApiClient: *apiClient},
+		VMHosts:             &VMHosts{ApiClient: *apiClient},
+		StaticRoutes:        &StaticRoutes{ApiClient: *apiClient},
+		DHCPSnippets:        &DHCPSnippets{ApiClient: *apiClient},
+		PackageRepositories: &PackageRepositories{ApiClient: *apiClient},
+		DefaultZone:         c.DefaultZone,
+		DefaultPool:         c.DefaultPool,
+		DefaultDomain:       c.DefaultDomain,
+		config:              c,
+		apiClient:           apiClient,
+		machineCache:        machineCache,
+	}
+}
+
+// withContext returns a copy of the client config whose requests are bound to
+// the given context of a Terraform operation, so the operation deadline still
+// applies when it's shorter than the HTTP timeout. The copy shares the
+// machines list cache.
+func (c *ClientConfig) withContext(ctx context.Context) *ClientConfig {
+	httpClient := *c.apiClient.AuthClient.HTTPClient
+	httpClient.Transport = &contextTransport{RoundTripper: httpClient.Transport, ctx: ctx}
+	authClient := c.apiClient.AuthClient
+	authClient.HTTPClient = &httpClient
+	return c.config.newClientConfig(&client.ApiClient{AuthClient: authClient, MAASObject: gomaasapi.NewMAAS(authClient)}, c.machineCache)
+}
+
+// contextTransport derives the context of every request from the context of
+// the Terraform operation, since gomaasapi doesn't take one. The request is
+// still canceled when its own context is done (e.g. on the HTTP timeout).
+type contextTransport struct {
+	http.RoundTripper
+	ctx context.Context
+}
+
+func (t *contextTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx, cancel := context.WithCancel(t.ctx)
+	go func() {
+		select {
+		case <-req.Context().Done():
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+	resp, err := t.RoundTripper.RoundTrip(req.WithContext(ctx))
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	resp.Body = &cancelReadCloser{ReadCloser: resp.Body, cancel: cancel}
+	return resp, nil
+}
+
+// cancelReadCloser cancels the request context once the response body is
+// closed, since the body is still read after the round trip.
+type cancelReadCloser struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelReadCloser) Close() error {
+	defer b.cancel()
+	return b.ReadCloser.Close()
+}
+
+// getHTTPClient returns the HTTP client used for all the MAAS API requests. By
+// default, gomaasapi uses an HTTP client without any timeout, so a stalled MAAS
+// server would block the requests forever.
+func (c *Config) getHTTPClient() *http.Client {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DialContext = (&net.Dialer{
+		Timeout:   30 * time.Second,
+		KeepAlive: 30 * time.Second,
+	}).DialContext
+	transport.MaxIdleConns = c.MaxIdleConnections
+	transport.MaxIdleConnsPerHost = c.MaxIdleConnections
+	return &http.Client{
+		Timeout:   c.HTTPTimeout,
+		Transport: transport,
+	}
+}
+
+// getApiClient is the same as client.GetApiClient, but it uses the given HTTP
+// client.
+func (c *Config) getApiClient(httpClient *http.Client) (*client.ApiClient, error) {
+	versionedURL := gomaasapi.AddAPIVersionToURL(c.APIURL, c.ApiVersion)
+	authClient, err := gomaasapi.NewAuthenticatedClient(versionedURL, c.APIKey)
+	if err != nil {
+		return nil, err
+	}
+	authClient.HTTPClient = httpClient
+	return &client.ApiClient{AuthClient: *authClient, MAASObject: gomaasapi.NewMAAS(*authClient)}, nil
 }
 
-func (c *Config) Client() (*client.Client, error) {
-	return client.GetClient(c.APIURL, c.APIKey, c.ApiVersion)
+// getClient is the same as client.GetClient, but it's built from the given
+// API client.
+func getClient(apiClient *client.ApiClient) *client.Client {
+	return &client.Client{
+		Domain:                &client.Domain{ApiClient: *apiClient},
+		Domains:               &client.Domains{ApiClient: *apiClient},
+		DNSResource:           &client.DNSResource{ApiClient: *apiClient},
+		DNSResources:          &client.DNSResources{ApiClient: *apiClient},
+		DNSResourceRecord:     &client.DNSResourceRecord{ApiClient: *apiClient},
+		DNSResourceRecords:    &client.DNSResourceRecords{ApiClient: *apiClient},
+		Fabric:                &client.Fabric{ApiClient: *apiClient},
+		Fabrics:               &client.Fabrics{ApiClient: *apiClient},
+		VLAN:                  &client.VLAN{ApiClient: *apiClient},
+		VLANs:                 &client.VLANs{ApiClient: *apiClient},
+		Space:                 &client.Space{ApiClient: *apiClient},
+		Spaces:                &client.Spaces{ApiClient: *apiClient},
+		Machine:               &client.Machine{ApiClient: *apiClient},
+		Machines:              &client.Machines{ApiClient: *apiClient},
+		VMHost:                &client.VMHost{ApiClient: *apiClient},
+		VMHosts:               &client.VMHosts{ApiClient: *apiClient},
+		NetworkInterface:      &client.NetworkInterface{ApiClient: *apiClient},
+		NetworkInterfaces:     &client.NetworkInterfaces{ApiClient: *apiClient},
+		Subnet:                &client.Subnet{ApiClient: *apiClient},
+		Subnets:               &client.Subnets{ApiClient: *apiClient},
+		IPRange:               &client.IPRange{ApiClient: *apiClient},
+		IPRanges:              &client.IPRanges{ApiClient: *apiClient},
+		IPAddresses:           &client.IPAddresses{ApiClient: *apiClient},
+		Tag:                   &client.Tag{ApiClient: *apiClient},
+		Tags:                  &client.Tags{ApiClient: *apiClient},
+		BlockDevice:           &client.BlockDevice{ApiClient: *apiClient},
+		BlockDevices:          &client.BlockDevices{ApiClient: *apiClient},
+		BlockDevicePartition:  &client.BlockDevicePartition{ApiClient: *apiClient},
+		BlockDevicePartitions: &client.BlockDevicePartitions{ApiClient: *apiClient},
+		User:                  &client.User{ApiClient: *apiClient},
+		Users:                 &client.Users{ApiClient: *apiClient},
+	}
 }
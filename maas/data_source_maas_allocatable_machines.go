@@ -0,0 +1,127 @@
+package maas
+
+import (
+	"context"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/maas/gomaasclient/entity"
+)
+
+func dataSourceMaasAllocatableMachines() *schema.Resource {
+	return &schema.Resource{
+		Description: "Provides details about the MAAS machines that can be allocated with the given constraints. No machine is allocated by this data source.",
+		ReadContext: dataSourceAllocatableMachinesRead,
+
+		Schema: map[string]*schema.Schema{
+			"min_cpu_count": {
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Description: "The minimum number of cores of the machines.",
+			},
+			"min_memory": {
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Description: "The minimum RAM memory size (in MB) of the machines.",
+			},
+			"min_storage": {
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Description: "The minimum total storage size (in GB) of the machines.",
+			},
+			"architecture": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The architecture of the machines (e.g. `amd64` or `amd64/generic`).",
+			},
+			"zone": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The zone name of the machines.",
+			},
+			"pool": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The pool name of the machines.",
+			},
+			"tags": {
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Description: "A set of tag names that must be assigned on the machines.",
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"machines_count": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "The number of machines that can be allocated.",
+			},
+			"system_ids": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The system IDs of the machines that can be allocated.",
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+		},
+	}
+}
+
+func dataSourceAllocatableMachinesRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*ClientConfig).Client
+
+	machines, err := client.Machines.Get()
+	if err != nil {
+		return diagFromErr(err)
+	}
+	systemIDs := []string{}
+	for _, machine := range machines {
+		if machine.StatusName == "Ready" && isMachineMatchingConstraints(d, &machine) {
+			systemIDs = append(systemIDs, machine.SystemID)
+		}
+	}
+	tfState := map[string]interface{}{
+		"id":             "allocatable_machines",
+		"machines_count": len(systemIDs),
+		"system_ids":     systemIDs,
+	}
+	if err := setTerraformState(d, tfState); err != nil {
+		return diagFromErr(err)
+	}
+
+	return nil
+}
+
+func isMachineMatchingConstraints(d *schema.ResourceData, machine *entity.Machine) bool {
+	if machine.CPUCount < d.Get("min_cpu_count").(int) {
+		return false
+	}
+	if machine.Memory < d.Get("min_memory").(int) {
+		return false
+	}
+	if machine.Storage < float64(d.Get("min_storage").(int)*1000) {
+		return false
+	}
+	if arch := d.Get("architecture").(string); arch != "" && machine.Architecture != arch && !strings.HasPrefix(machine.Architecture, arch+"/") {
+		return false
+	}
+	if zone := d.Get("zone").(string); zone != "" && machine.Zone.Name != zone {
+		return false
+	}
+	if pool := d.Get("pool").(string); pool != "" && machine.Pool.Name != pool {
+		return false
+	}
+	machineTags := map[string]bool{}
+	for _, t := range machine.TagNames {
+		machineTags[t] = true
+	}
+	for _, t := range convertToStringSlice(d.Get("tags").(*schema.Set).List()) {
+		if !machineTags[t] {
+			return false
+		}
+	}
+	return true
+}
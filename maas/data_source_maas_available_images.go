@@ -0,0 +1,176 @@
+package maas
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// simplestreamsIndex is the index of a simplestreams mirror, listing its
+// products files.
+type simplestreamsIndex struct {
+	Index map[string]struct {
+		DataType string `json:"datatype"`
+		Path     string `json:"path"`
+	} `json:"index"`
+}
+
+// simplestreamsProducts is a simplestreams products file.
+type simplestreamsProducts struct {
+	Products map[string]struct {
+		OS             string `json:"os"`
+		Release        string `json:"release"`
+		Arch           string `json:"arch"`
+		Subarch        string `json:"subarch"`
+		Subarches      string `json:"subarches"`
+		BootloaderType string `json:"bootloader-type"`
+	} `json:"products"`
+}
+
+func dataSourceMaasAvailableImages() *schema.Resource {
+	return &schema.Resource{
+		Description: "Provides the list of the images offered by an existing MAAS boot source, which can be selected to be imported.\n\n**NOTE:** The images are read from the simplestreams index (`streams/v1/index.json`) of the boot source URL, so it must be reachable from where Terraform runs.",
+		ReadContext: dataSourceAvailableImagesRead,
+
+		Schema: map[string]*schema.Schema{
+			"boot_source": {
+				Type:        schema.TypeInt,
+				Required:    true,
+				Description: "The ID of the boot source.",
+			},
+			"images": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The list of the available images. Parameters defined below.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"os": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The image operating system (e.g. `ubuntu`).",
+						},
+						"release": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The image release (e.g. `jammy`).",
+						},
+						"arch": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The image architecture (e.g. `amd64`).",
+						},
+						"subarches": {
+							Type:        schema.TypeList,
+							Computed:    true,
+							Description: "The sub-architectures supported by the image.",
+							Elem: &schema.Schema{
+								Type: schema.TypeString,
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceAvailableImagesRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	clientConfig := m.(*ClientConfig)
+
+	bootSource, err := clientConfig.BootSources.Get(d.Get("boot_source").(int))
+	if err != nil {
+		return diagFromErr(err)
+	}
+	images, err := getBootSourceImages(ctx, clientConfig.HTTPClient, bootSource.URL)
+	if err != nil {
+		return diagFromErr(err)
+	}
+	tfState := map[string]interface{}{
+		"id":     fmt.Sprintf("%v", bootSource.ID),
+		"images": images,
+	}
+	if err := setTerraformState(d, tfState); err != nil {
+		return diagFromErr(err)
+	}
+
+	return nil
+}
+
+// getBootSourceImages returns the images of the simplestreams mirror at the
+// given URL, one for each os, release and architecture. The bootloaders are
+// skipped, since they can't be selected.
+func getBootSourceImages(ctx context.Context, httpClient *http.Client, sourceURL string) ([]map[string]interface{}, error) {
+	sourceURL = strings.TrimSuffix(sourceURL, "/") + "/"
+	index := simplestreamsIndex{}
+	if err := getSimplestreamsFile(ctx, httpClient, sourceURL+"streams/v1/index.json", &index); err != nil {
+		return nil, err
+	}
+	subarches := map[string]map[string]bool{}
+	images := map[string]map[string]interface{}{}
+	for _, entry := range index.Index {
+		if entry.DataType != "image-downloads" {
+			continue
+		}
+		products := simplestreamsProducts{}
+		if err := getSimplestreamsFile(ctx, httpClient, sourceURL+entry.Path, &products); err != nil {
+			return nil, err
+		}
+		for _, p := range products.Products {
+			if p.BootloaderType != "" {
+				continue
+			}
+			key := fmt.Sprintf("%s/%s/%s", p.OS, p.Release, p.Arch)
+			if _, ok := images[key]; !ok {
+				images[key] = map[string]interface{}{
+					"os":      p.OS,
+					"release": p.Release,
+					"arch":    p.Arch,
+				}
+				subarches[key] = map[string]bool{}
+			}
+			for _, subarch := range append(strings.Split(p.Subarches, ","), p.Subarch) {
+				if subarch != "" {
+					subarches[key][subarch] = true
+				}
+			}
+		}
+	}
+	keys := make([]string, 0, len(images))
+	for key := range images {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	result := make([]map[string]interface{}, len(keys))
+	for i, key := range keys {
+		imageSubarches := make([]string, 0, len(subarches[key]))
+		for subarch := range subarches[key] {
+			imageSubarches = append(imageSubarches, subarch)
+		}
+		sort.Strings(imageSubarches)
+		images[key]["subarches"] = imageSubarches
+		result[i] = images[key]
+	}
+	return result, nil
+}
+
+func getSimplestreamsFile(ctx context.Context, httpClient *http.Client, fileURL string, v interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fileURL, nil)
+	if err != nil {
+		return err
+	}
+	res, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("unable to get the simplestreams file (%s): %s", fileURL, res.Status)
+	}
+	return json.NewDecoder(res.Body).Decode(v)
+}
@@ -0,0 +1,102 @@
+package maas
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func dataSourceMaasBootSourceSelections() *schema.Resource {
+	return &schema.Resource{
+		Description: "Provides details about the boot resources selected to be imported from an existing MAAS boot source.",
+		ReadContext: dataSourceBootSourceSelectionsRead,
+
+		Schema: map[string]*schema.Schema{
+			"boot_source": {
+				Type:        schema.TypeInt,
+				Required:    true,
+				Description: "The ID of the boot source.",
+			},
+			"selections": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The list of the boot source selections. Parameters defined below.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Type:        schema.TypeInt,
+							Computed:    true,
+							Description: "The boot source selection ID.",
+						},
+						"os": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The selected operating system (e.g. `ubuntu`).",
+						},
+						"release": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The selected release (e.g. `jammy`).",
+						},
+						"arches": {
+							Type:        schema.TypeList,
+							Computed:    true,
+							Description: "The selected architectures.",
+							Elem: &schema.Schema{
+								Type: schema.TypeString,
+							},
+						},
+						"subarches": {
+							Type:        schema.TypeList,
+							Computed:    true,
+							Description: "The selected sub-architectures.",
+							Elem: &schema.Schema{
+								Type: schema.TypeString,
+							},
+						},
+						"labels": {
+							Type:        schema.TypeList,
+							Computed:    true,
+							Description: "The selected labels (e.g. `*`).",
+							Elem: &schema.Schema{
+								Type: schema.TypeString,
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceBootSourceSelectionsRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	bootSources := m.(*ClientConfig).BootSources
+
+	bootSourceID := d.Get("boot_source").(int)
+	bootSourceSelections, err := bootSources.GetSelections(bootSourceID)
+	if err != nil {
+		return diagFromErr(err)
+	}
+	selections := make([]map[string]interface{}, len(bootSourceSelections))
+	for i, s := range bootSourceSelections {
+		selections[i] = map[string]interface{}{
+			"id":        s.ID,
+			"os":        s.OS,
+			"release":   s.Release,
+			"arches":    s.Arches,
+			"subarches": s.Subarches,
+			"labels":    s.Labels,
+		}
+	}
+	tfState := map[string]interface{}{
+		"id":         fmt.Sprintf("%v", bootSourceID),
+		"selections": selections,
+	}
+	if err := setTerraformState(d, tfState); err != nil {
+		return diagFromErr(err)
+	}
+
+	return nil
+}
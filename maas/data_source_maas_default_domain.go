@@ -0,0 +1,54 @@
+package maas
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func dataSourceMaasDefaultDomain() *schema.Resource {
+	return &schema.Resource{
+		Description: "Provides details about the default MAAS DNS domain.",
+		ReadContext: dataSourceDefaultDomainRead,
+
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The default DNS domain name.",
+			},
+			"ttl": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "The default TTL of the DNS domain. It's `0` when the domain uses the MAAS server default TTL.",
+			},
+			"authoritative": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Boolean value indicating if the DNS domain is authoritative.",
+			},
+		},
+	}
+}
+
+func dataSourceDefaultDomainRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*ClientConfig).Client
+
+	domain, err := getDefaultDomain(client)
+	if err != nil {
+		return diagFromErr(err)
+	}
+	tfState := map[string]interface{}{
+		"id":            fmt.Sprintf("%v", domain.ID),
+		"name":          domain.Name,
+		"ttl":           domain.TTL,
+		"authoritative": domain.Authoritative,
+	}
+	if err := setTerraformState(d, tfState); err != nil {
+		return diagFromErr(err)
+	}
+
+	return nil
+}
@@ -6,7 +6,6 @@ import (
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
-	"github.com/maas/gomaasclient/client"
 )
 
 func dataSourceMaasFabric() *schema.Resource {
@@ -25,11 +24,11 @@ func dataSourceMaasFabric() *schema.Resource {
 }
 
 func dataSourceFabricRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	client := m.(*client.Client)
+	client := m.(*ClientConfig).Client
 
 	fabric, err := getFabric(client, d.Get("name").(string))
 	if err != nil {
-		return diag.FromErr(err)
+		return diagFromErr(err)
 	}
 	d.SetId(fmt.Sprintf("%v", fabric.ID))
 
@@ -0,0 +1,155 @@
+package maas
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/maas/gomaasclient/entity"
+)
+
+func dataSourceMaasInterface() *schema.Resource {
+	return &schema.Resource{
+		Description: "Provides details about a network interface of an existing MAAS machine, found by its name or MAC address.",
+		ReadContext: dataSourceInterfaceRead,
+
+		Schema: map[string]*schema.Schema{
+			"machine": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The identifier (system ID, hostname, FQDN, or MAC address) of the machine.",
+			},
+			"name": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ExactlyOneOf: []string{"name", "mac_address"},
+				Description:  "The name of the network interface. Exactly one of `name` or `mac_address` must be set.",
+			},
+			"mac_address": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Computed:         true,
+				ValidateDiagFunc: validation.ToDiagFunc(validation.IsMACAddress),
+				Description:      "The MAC address of the network interface. Exactly one of `name` or `mac_address` must be set.",
+			},
+			"type": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The network interface type (e.g. `physical`, `bond`, `bridge`, `vlan`).",
+			},
+			"vlan": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "The ID of the VLAN the network interface is connected to.",
+			},
+			"enabled": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Boolean value indicating if the network interface is enabled.",
+			},
+			"parents": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The names of the parent network interfaces.",
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"links": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The links of the network interface to subnets. Parameters defined below.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Type:        schema.TypeInt,
+							Computed:    true,
+							Description: "The link ID.",
+						},
+						"mode": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The connection mode of the network interface to the subnet.",
+						},
+						"subnet": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The CIDR of the linked subnet.",
+						},
+						"ip_address": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The IP address of the network interface on the subnet.",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceInterfaceRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*ClientConfig).Client
+
+	machine, err := getMachine(client, d.Get("machine").(string))
+	if err != nil {
+		return diagFromErr(err)
+	}
+	networkInterfaces, err := client.NetworkInterfaces.Get(machine.SystemID)
+	if err != nil {
+		return diagFromErr(err)
+	}
+	name := d.Get("name").(string)
+	macAddress := d.Get("mac_address").(string)
+	matches := []entity.NetworkInterface{}
+	for _, n := range networkInterfaces {
+		if (name != "" && n.Name == name) || (macAddress != "" && strings.EqualFold(n.MACAddress, macAddress)) {
+			matches = append(matches, n)
+		}
+	}
+	identifier := name
+	if identifier == "" {
+		identifier = macAddress
+	}
+	if len(matches) == 0 {
+		return diagFromErr(fmt.Errorf("network interface (%s) was not found on machine (%s)", identifier, machine.SystemID))
+	}
+	// Multiple network interfaces share the MAC address of their parent (e.g.
+	// VLAN interfaces and bonds)
+	if len(matches) > 1 {
+		names := make([]string, len(matches))
+		for i, n := range matches {
+			names[i] = n.Name
+		}
+		return diagFromErr(fmt.Errorf("multiple network interfaces (%s) match (%s) on machine (%s), use the name instead", strings.Join(names, ", "), identifier, machine.SystemID))
+	}
+	networkInterface := matches[0]
+	links := make([]map[string]interface{}, len(networkInterface.Links))
+	for i, link := range networkInterface.Links {
+		links[i] = map[string]interface{}{
+			"id":         link.ID,
+			"mode":       link.Mode,
+			"subnet":     link.Subnet.CIDR,
+			"ip_address": link.IPAddress,
+		}
+	}
+	tfState := map[string]interface{}{
+		"id":          fmt.Sprintf("%v", networkInterface.ID),
+		"name":        networkInterface.Name,
+		"mac_address": networkInterface.MACAddress,
+		"type":        networkInterface.Type,
+		"vlan":        networkInterface.VLAN.ID,
+		"enabled":     networkInterface.Enabled,
+		"parents":     networkInterface.Parents,
+		"links":       links,
+	}
+	if err := setTerraformState(d, tfState); err != nil {
+		return diagFromErr(err)
+	}
+
+	return nil
+}
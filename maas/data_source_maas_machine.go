@@ -0,0 +1,94 @@
+package maas
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func dataSourceMaasMachine() *schema.Resource {
+	return &schema.Resource{
+		Description: "Provides details about an existing MAAS machine, including its current power state and status.\n\n**NOTE:** Terraform reads data sources while planning, so the power state and status reflect the machine at plan time.",
+		ReadContext: dataSourceMachineRead,
+
+		Schema: map[string]*schema.Schema{
+			"machine": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The identifier (system ID, hostname, FQDN, or MAC address) of the machine.",
+			},
+			"hostname": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The machine hostname.",
+			},
+			"fqdn": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The machine FQDN.",
+			},
+			"power_state": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The machine power state (e.g. `on`, `off`, `unknown`).",
+			},
+			"power_type": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The machine power management type (e.g. `ipmi`).",
+			},
+			"status_name": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The machine status (e.g. `Ready`, `Deployed`).",
+			},
+			"zone": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The machine zone.",
+			},
+			"pool": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The machine pool.",
+			},
+			"architecture": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The machine architecture.",
+			},
+		},
+	}
+}
+
+func dataSourceMachineRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*ClientConfig).Client
+
+	machine, err := getMachine(client, d.Get("machine").(string))
+	if err != nil {
+		return diagFromErr(err)
+	}
+	// The machines list may be cached, so the machine is fetched again to get
+	// its current power state and status
+	machine, err = client.Machine.Get(machine.SystemID)
+	if err != nil {
+		return diagFromErr(err)
+	}
+	tfState := map[string]interface{}{
+		"id":           machine.SystemID,
+		"hostname":     machine.Hostname,
+		"fqdn":         machine.FQDN,
+		"power_state":  machine.PowerState,
+		"power_type":   machine.PowerType,
+		"status_name":  machine.StatusName,
+		"zone":         machine.Zone.Name,
+		"pool":         machine.Pool.Name,
+		"architecture": machine.Architecture,
+	}
+	if err := setTerraformState(d, tfState); err != nil {
+		return diagFromErr(err)
+	}
+
+	return nil
+}
@@ -0,0 +1,101 @@
+package maas
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func dataSourceMaasMachineEvents() *schema.Resource {
+	return &schema.Resource{
+		Description: "Provides the events of an existing MAAS machine (e.g. to diagnose commissioning or deployment failures).",
+		ReadContext: dataSourceMachineEventsRead,
+
+		Schema: map[string]*schema.Schema{
+			"machine": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The identifier (system ID, hostname, FQDN, or MAC address) of the machine.",
+			},
+			"limit": {
+				Type:             schema.TypeInt,
+				Optional:         true,
+				ValidateDiagFunc: validation.ToDiagFunc(validation.IntBetween(1, 1000)),
+				Description:      "The maximum number of events returned. If this is not set, the MAAS default (`100`) is used.",
+			},
+			"level": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				ValidateDiagFunc: validation.ToDiagFunc(validation.StringInSlice([]string{"AUDIT", "DEBUG", "INFO", "WARNING", "ERROR", "CRITICAL"}, false)),
+				Description:      "The minimum level of the events returned. Valid options are: `AUDIT`, `DEBUG`, `INFO`, `WARNING`, `ERROR`, `CRITICAL`. If this is not set, the MAAS default (`INFO`) is used.",
+			},
+			"events": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The list of the machine events, the newest first. Parameters defined below.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"created": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The event creation time.",
+						},
+						"level": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The event level.",
+						},
+						"type": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The event type (e.g. `Deploying`).",
+						},
+						"description": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The event description.",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceMachineEventsRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*ClientConfig).Client
+
+	machine, err := getMachine(client, d.Get("machine").(string))
+	if err != nil {
+		return diagFromErr(err)
+	}
+	params := EventsParams{
+		ID:    []string{machine.SystemID},
+		Level: d.Get("level").(string),
+		Limit: d.Get("limit").(int),
+	}
+	machineEvents, err := m.(*ClientConfig).Events.Query(&params)
+	if err != nil {
+		return diagFromErr(err)
+	}
+	events := make([]map[string]interface{}, len(machineEvents))
+	for i, e := range machineEvents {
+		events[i] = map[string]interface{}{
+			"created":     e.Created,
+			"level":       e.Level,
+			"type":        e.Type,
+			"description": e.Description,
+		}
+	}
+	tfState := map[string]interface{}{
+		"id":     machine.SystemID,
+		"events": events,
+	}
+	if err := setTerraformState(d, tfState); err != nil {
+		return diagFromErr(err)
+	}
+
+	return nil
+}
@@ -0,0 +1,80 @@
+package maas
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func dataSourceMaasNetworkInterfaceLink() *schema.Resource {
+	return &schema.Resource{
+		Description: "Provides details about the network configuration of an existing MAAS machine on a given subnet.",
+		ReadContext: dataSourceNetworkInterfaceLinkRead,
+
+		Schema: map[string]*schema.Schema{
+			"machine": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The identifier (system ID, hostname, FQDN, or MAC address) of the machine.",
+			},
+			"subnet": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The identifier (CIDR or ID) of the subnet.",
+			},
+			"network_interface": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The name of the network interface linked to the subnet.",
+			},
+			"mode": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The connection mode of the network interface to the subnet.",
+			},
+			"ip_address": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The IP address of the network interface on the subnet.",
+			},
+		},
+	}
+}
+
+func dataSourceNetworkInterfaceLinkRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*ClientConfig).Client
+
+	machine, err := getMachine(client, d.Get("machine").(string))
+	if err != nil {
+		return diagFromErr(err)
+	}
+	subnet, err := getSubnet(client, d.Get("subnet").(string))
+	if err != nil {
+		return diagFromErr(err)
+	}
+	networkInterfaces, err := client.NetworkInterfaces.Get(machine.SystemID)
+	if err != nil {
+		return diagFromErr(err)
+	}
+	for _, n := range networkInterfaces {
+		for _, link := range n.Links {
+			if link.Subnet.ID != subnet.ID {
+				continue
+			}
+			tfState := map[string]interface{}{
+				"id":                fmt.Sprintf("%v", link.ID),
+				"network_interface": n.Name,
+				"mode":              link.Mode,
+				"ip_address":        link.IPAddress,
+			}
+			if err := setTerraformState(d, tfState); err != nil {
+				return diagFromErr(err)
+			}
+			return nil
+		}
+	}
+
+	return diagFromErr(fmt.Errorf("machine (%s) has no network interface linked to subnet (%s)", machine.SystemID, subnet.CIDR))
+}
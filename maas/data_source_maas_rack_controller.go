@@ -0,0 +1,133 @@
+package maas
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/maas/gomaasclient/entity"
+)
+
+func dataSourceMaasRackController() *schema.Resource {
+	return &schema.Resource{
+		Description: "Provides details about an existing MAAS rack controller.",
+		ReadContext: dataSourceRackControllerRead,
+
+		Schema: getControllerDataSourceSchema("rack"),
+	}
+}
+
+func dataSourceRackControllerRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	hostname := d.Get("hostname").(string)
+	rackControllers, err := m.(*ClientConfig).RackControllers.Get(hostname)
+	if err != nil {
+		return diagFromErr(err)
+	}
+	controllers := make([]entity.Machine, len(rackControllers))
+	for i, r := range rackControllers {
+		controllers[i] = entity.Machine(r)
+	}
+	controller, err := getControllerByHostname("rack", hostname, controllers)
+	if err != nil {
+		return diagFromErr(err)
+	}
+	if err := setTerraformState(d, getControllerTFState(controller)); err != nil {
+		return diagFromErr(err)
+	}
+
+	return nil
+}
+
+// getControllerDataSourceSchema returns the schema shared by the rack and
+// region controller data sources.
+func getControllerDataSourceSchema(controllerType string) map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"hostname": {
+			Type:        schema.TypeString,
+			Required:    true,
+			Description: fmt.Sprintf("The %s controller hostname.", controllerType),
+		},
+		"system_id": {
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: fmt.Sprintf("The %s controller system ID.", controllerType),
+		},
+		"ip_addresses": {
+			Type:     schema.TypeList,
+			Computed: true,
+			Elem: &schema.Schema{
+				Type: schema.TypeString,
+			},
+			Description: fmt.Sprintf("The IP addresses of the %s controller.", controllerType),
+		},
+		"version": {
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: fmt.Sprintf("The MAAS version running on the %s controller.", controllerType),
+		},
+		"services": {
+			Type:        schema.TypeList,
+			Computed:    true,
+			Description: fmt.Sprintf("The status of the MAAS services running on the %s controller. Parameters defined below.", controllerType),
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"name": {
+						Type:        schema.TypeString,
+						Computed:    true,
+						Description: "The service name (e.g. `rackd`, `dhcpd`).",
+					},
+					"status": {
+						Type:        schema.TypeString,
+						Computed:    true,
+						Description: "The service status (e.g. `running`, `degraded`, `dead`, `off`).",
+					},
+					"status_info": {
+						Type:        schema.TypeString,
+						Computed:    true,
+						Description: "Additional information about the service status.",
+					},
+				},
+			},
+		},
+	}
+}
+
+// getControllerByHostname returns the only controller from the given list,
+// which is the result of a lookup by hostname.
+func getControllerByHostname(controllerType string, hostname string, controllers []entity.Machine) (*entity.Machine, error) {
+	if len(controllers) == 0 {
+		return nil, fmt.Errorf("%s controller (%s) was not found", controllerType, hostname)
+	}
+	if len(controllers) > 1 {
+		candidates := make([]string, len(controllers))
+		for i, c := range controllers {
+			candidates[i] = fmt.Sprintf("%s (%s)", c.SystemID, c.FQDN)
+		}
+		return nil, fmt.Errorf("%s controller identifier (%s) is ambiguous, it matches: %s", controllerType, hostname, strings.Join(candidates, ", "))
+	}
+	return &controllers[0], nil
+}
+
+func getControllerTFState(controller *entity.Machine) map[string]interface{} {
+	ipAddresses := make([]string, len(controller.IPAddresses))
+	for i, ip := range controller.IPAddresses {
+		ipAddresses[i] = ip.String()
+	}
+	services := make([]map[string]interface{}, len(controller.ServiceSet))
+	for i, s := range controller.ServiceSet {
+		services[i] = map[string]interface{}{
+			"name":        s.Name,
+			"status":      s.Status,
+			"status_info": s.StatusInfo,
+		}
+	}
+	return map[string]interface{}{
+		"id":           controller.SystemID,
+		"system_id":    controller.SystemID,
+		"ip_addresses": ipAddresses,
+		"version":      controller.Version,
+		"services":     services,
+	}
+}
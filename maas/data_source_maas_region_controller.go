@@ -0,0 +1,34 @@
+package maas
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func dataSourceMaasRegionController() *schema.Resource {
+	return &schema.Resource{
+		Description: "Provides details about an existing MAAS region controller.",
+		ReadContext: dataSourceRegionControllerRead,
+
+		Schema: getControllerDataSourceSchema("region"),
+	}
+}
+
+func dataSourceRegionControllerRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	hostname := d.Get("hostname").(string)
+	regionControllers, err := m.(*ClientConfig).RegionControllers.Get(hostname)
+	if err != nil {
+		return diagFromErr(err)
+	}
+	controller, err := getControllerByHostname("region", hostname, regionControllers)
+	if err != nil {
+		return diagFromErr(err)
+	}
+	if err := setTerraformState(d, getControllerTFState(controller)); err != nil {
+		return diagFromErr(err)
+	}
+
+	return nil
+}
@@ -0,0 +1,66 @@
+package maas
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func dataSourceMaasResourcePools() *schema.Resource {
+	return &schema.Resource{
+		Description: "Provides details about all the existing MAAS resource pools.",
+		ReadContext: dataSourceResourcePoolsRead,
+
+		Schema: map[string]*schema.Schema{
+			"resource_pools": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The list of MAAS resource pools.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Type:        schema.TypeInt,
+							Computed:    true,
+							Description: "The resource pool ID.",
+						},
+						"name": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The resource pool name.",
+						},
+						"description": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The resource pool description.",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceResourcePoolsRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	resourcePools, err := m.(*ClientConfig).ResourcePools.Get()
+	if err != nil {
+		return diagFromErr(err)
+	}
+	items := make([]map[string]interface{}, len(resourcePools))
+	for i, p := range resourcePools {
+		items[i] = map[string]interface{}{
+			"id":          p.ID,
+			"name":        p.Name,
+			"description": p.Description,
+		}
+	}
+	tfState := map[string]interface{}{
+		"id":             "resource_pools",
+		"resource_pools": items,
+	}
+	if err := setTerraformState(d, tfState); err != nil {
+		return diagFromErr(err)
+	}
+
+	return nil
+}
@@ -0,0 +1,62 @@
+package maas
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func dataSourceMaasSpaces() *schema.Resource {
+	return &schema.Resource{
+		Description: "Provides details about all the existing MAAS network spaces.",
+		ReadContext: dataSourceSpacesRead,
+
+		Schema: map[string]*schema.Schema{
+			"spaces": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The list of MAAS network spaces. Each space has an `id` (the space ID) and a `name` (the space name).",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Type:        schema.TypeInt,
+							Computed:    true,
+							Description: "The space ID.",
+						},
+						"name": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The space name.",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceSpacesRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*ClientConfig).Client
+
+	spaces, err := client.Spaces.Get()
+	if err != nil {
+		return diagFromErr(err)
+	}
+	items := make([]map[string]interface{}, len(spaces))
+	for i, s := range spaces {
+		items[i] = map[string]interface{}{
+			"id":   s.ID,
+			"name": s.Name,
+		}
+	}
+	tfState := map[string]interface{}{
+		"id":     "spaces",
+		"spaces": items,
+	}
+	if err := setTerraformState(d, tfState); err != nil {
+		return diagFromErr(err)
+	}
+
+	return nil
+}
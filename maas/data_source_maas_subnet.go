@@ -25,11 +25,21 @@ func dataSourceMaasSubnet() *schema.Resource {
 				Computed:    true,
 				Description: "The subnet fabric.",
 			},
+			"vlan": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "The subnet VLAN ID.",
+			},
 			"vid": {
 				Type:        schema.TypeInt,
 				Computed:    true,
 				Description: "The subnet VLAN traffic segregation ID.",
 			},
+			"space": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The subnet space.",
+			},
 			"name": {
 				Type:        schema.TypeString,
 				Computed:    true,
@@ -63,16 +73,69 @@ func dataSourceMaasSubnet() *schema.Resource {
 				},
 				Description: "List of IP addresses set as DNS servers for the subnet.",
 			},
+			"ip_ranges": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The IP ranges (reserved or dynamic) defined on the subnet. Parameters defined below.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"type": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The IP range type (`reserved` or `dynamic`).",
+						},
+						"start_ip": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The start IP of the range.",
+						},
+						"end_ip": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The end IP of the range.",
+						},
+					},
+				},
+			},
+			"statistics": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The subnet IP addresses usage statistics. Parameters defined below.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"total_addresses": {
+							Type:        schema.TypeInt,
+							Computed:    true,
+							Description: "The total number of IP addresses in the subnet.",
+						},
+						"available_addresses": {
+							Type:        schema.TypeInt,
+							Computed:    true,
+							Description: "The number of available IP addresses in the subnet.",
+						},
+						"used_addresses": {
+							Type:        schema.TypeInt,
+							Computed:    true,
+							Description: "The number of used (or reserved) IP addresses in the subnet.",
+						},
+						"usage_percentage": {
+							Type:        schema.TypeFloat,
+							Computed:    true,
+							Description: "The percentage of used IP addresses in the subnet.",
+						},
+					},
+				},
+			},
 		},
 	}
 }
 
 func dataSourceSubnetRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	client := m.(*client.Client)
+	client := m.(*ClientConfig).Client
 
 	subnet, err := getSubnet(client, d.Get("cidr").(string))
 	if err != nil {
-		return diag.FromErr(err)
+		return diagFromErr(err)
 	}
 	gatewayIp := subnet.GatewayIP.String()
 	if gatewayIp == "<nil>" {
@@ -82,20 +145,58 @@ func dataSourceSubnetRead(ctx context.Context, d *schema.ResourceData, m interfa
 	for i, ip := range subnet.DNSServers {
 		dnsServers[i] = ip.String()
 	}
+	ipRanges, err := getSubnetIPRanges(client, subnet.ID)
+	if err != nil {
+		return diagFromErr(err)
+	}
+	statistics, err := client.Subnet.GetStatistics(subnet.ID)
+	if err != nil {
+		return diagFromErr(err)
+	}
 	tfState := map[string]interface{}{
 		"id":          fmt.Sprintf("%v", subnet.ID),
 		"fabric":      subnet.VLAN.Fabric,
+		"vlan":        subnet.VLAN.ID,
 		"vid":         subnet.VLAN.VID,
+		"space":       subnet.Space,
 		"name":        subnet.Name,
 		"rdns_mode":   subnet.RDNSMode,
 		"allow_dns":   subnet.AllowDNS,
 		"allow_proxy": subnet.AllowProxy,
 		"gateway_ip":  gatewayIp,
 		"dns_servers": dnsServers,
+		"ip_ranges":   ipRanges,
+		"statistics": []map[string]interface{}{
+			{
+				"total_addresses":     statistics.TotalAddresses,
+				"available_addresses": statistics.NumAvailable,
+				"used_addresses":      statistics.NumUnavailable,
+				"usage_percentage":    statistics.Usage * 100,
+			},
+		},
 	}
 	if err := setTerraformState(d, tfState); err != nil {
-		return diag.FromErr(err)
+		return diagFromErr(err)
 	}
 
 	return nil
 }
+
+func getSubnetIPRanges(client *client.Client, subnetID int) ([]map[string]interface{}, error) {
+	ipRanges, err := client.IPRanges.Get()
+	if err != nil {
+		return nil, err
+	}
+	subnetIPRanges := []map[string]interface{}{}
+	for _, ipRange := range ipRanges {
+		if ipRange.Subnet.ID != subnetID {
+			continue
+		}
+		subnetIPRanges = append(subnetIPRanges, map[string]interface{}{
+			"type":     ipRange.Type,
+			"start_ip": ipRange.StartIP.String(),
+			"end_ip":   ipRange.EndIP.String(),
+		})
+	}
+	return subnetIPRanges, nil
+}
@@ -6,7 +6,6 @@ import (
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
-	"github.com/maas/gomaasclient/client"
 )
 
 func dataSourceMaasVlan() *schema.Resource {
@@ -25,6 +24,11 @@ func dataSourceMaasVlan() *schema.Resource {
 				Required:    true,
 				Description: "The VLAN identifier (ID or traffic segregation ID).",
 			},
+			"vid": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "The VLAN traffic segregation ID.",
+			},
 			"mtu": {
 				Type:        schema.TypeInt,
 				Computed:    true,
@@ -50,25 +54,26 @@ func dataSourceMaasVlan() *schema.Resource {
 }
 
 func dataSourceVlanRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	client := m.(*client.Client)
+	client := m.(*ClientConfig).Client
 
 	fabric, err := getFabric(client, d.Get("fabric").(string))
 	if err != nil {
-		return diag.FromErr(err)
+		return diagFromErr(err)
 	}
 	vlan, err := getVlan(client, fabric.ID, d.Get("vlan").(string))
 	if err != nil {
-		return diag.FromErr(err)
+		return diagFromErr(err)
 	}
 	tfState := map[string]interface{}{
 		"id":      fmt.Sprintf("%v", vlan.ID),
+		"vid":     vlan.VID,
 		"mtu":     vlan.MTU,
 		"dhcp_on": vlan.DHCPOn,
 		"name":    vlan.Name,
 		"space":   vlan.Space,
 	}
 	if err := setTerraformState(d, tfState); err != nil {
-		return diag.FromErr(err)
+		return diagFromErr(err)
 	}
 
 	return nil
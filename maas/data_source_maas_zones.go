@@ -0,0 +1,66 @@
+package maas
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func dataSourceMaasZones() *schema.Resource {
+	return &schema.Resource{
+		Description: "Provides details about all the existing MAAS availability zones.",
+		ReadContext: dataSourceZonesRead,
+
+		Schema: map[string]*schema.Schema{
+			"zones": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The list of MAAS availability zones.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Type:        schema.TypeInt,
+							Computed:    true,
+							Description: "The zone ID.",
+						},
+						"name": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The zone name.",
+						},
+						"description": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The zone description.",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceZonesRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	zones, err := m.(*ClientConfig).Zones.Get()
+	if err != nil {
+		return diagFromErr(err)
+	}
+	items := make([]map[string]interface{}, len(zones))
+	for i, z := range zones {
+		items[i] = map[string]interface{}{
+			"id":          z.ID,
+			"name":        z.Name,
+			"description": z.Description,
+		}
+	}
+	tfState := map[string]interface{}{
+		"id":    "zones",
+		"zones": items,
+	}
+	if err := setTerraformState(d, tfState); err != nil {
+		return diagFromErr(err)
+	}
+
+	return nil
+}
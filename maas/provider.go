@@ -3,19 +3,24 @@ package maas
 import (
 	"context"
 	"fmt"
+	"net/http"
 	"os"
+	"regexp"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
 func Provider() *schema.Provider {
-	return &schema.Provider{
+	provider := &schema.Provider{
 		Schema: map[string]*schema.Schema{
 			"api_key": {
 				Type:        schema.TypeString,
 				Optional:    true,
 				Default:     os.Getenv("MAAS_API_KEY"),
+				Sensitive:   true,
 				Description: "The MAAS API key",
 			},
 			"api_url": {
@@ -25,10 +30,40 @@ func Provider() *schema.Provider {
 				Description: "The MAAS API URL (eg: http://127.0.0.1:5240/MAAS)",
 			},
 			"api_version": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Default:          "2.0",
+				ValidateDiagFunc: validation.ToDiagFunc(validation.StringMatch(regexp.MustCompile(`^\d+\.\d+$`), "must be a version number (e.g. 2.0)")),
+				Description:      "The MAAS API version (default 2.0). The provider checks that the MAAS server supports it when it's configured.",
+			},
+			"http_timeout_seconds": {
+				Type:             schema.TypeInt,
+				Optional:         true,
+				Default:          120,
+				ValidateDiagFunc: validation.ToDiagFunc(validation.IntAtLeast(1)),
+				Description:      "The timeout in seconds of every MAAS API request (default 120). The timeouts of the Terraform operations still apply when they expire first.",
+			},
+			"max_idle_connections": {
+				Type:             schema.TypeInt,
+				Optional:         true,
+				Default:          10,
+				ValidateDiagFunc: validation.ToDiagFunc(validation.IntAtLeast(1)),
+				Description:      "The maximum number of idle connections kept open to the MAAS server (default 10). It must be at least 1.",
+			},
+			"default_zone": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The zone used by the resources when their `zone` is not set. If it's not set, the MAAS server default is used.",
+			},
+			"default_pool": {
 				Type:        schema.TypeString,
 				Optional:    true,
-				Default:     "2.0",
-				Description: "The MAAS API version (default 2.0)",
+				Description: "The resource pool used by the resources when their `pool` is not set. If it's not set, the MAAS server default is used.",
+			},
+			"default_domain": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The domain used by the resources when their `domain` is not set. If it's not set, the MAAS server default is used.",
 			},
 		},
 		ResourcesMap: map[string]*schema.Resource{
@@ -37,40 +72,132 @@ func Provider() *schema.Provider {
 			"maas_vm_host_machine":            resourceMaasVMHostMachine(),
 			"maas_machine":                    resourceMaasMachine(),
 			"maas_network_interface_physical": resourceMaasNetworkInterfacePhysical(),
+			"maas_network_interface_bond":     resourceMaasNetworkInterfaceBond(),
+			"maas_network_interface_bridge":   resourceMaasNetworkInterfaceBridge(),
+			"maas_network_interface_vlan":     resourceMaasNetworkInterfaceVLAN(),
 			"maas_network_interface_link":     resourceMaasNetworkInterfaceLink(),
 			"maas_fabric":                     resourceMaasFabric(),
 			"maas_vlan":                       resourceMaasVlan(),
 			"maas_subnet":                     resourceMaasSubnet(),
 			"maas_subnet_ip_range":            resourceMaasSubnetIPRange(),
+			"maas_reserved_ip":                resourceMaasReservedIP(),
+			"maas_static_route":               resourceMaasStaticRoute(),
+			"maas_notification":               resourceMaasNotification(),
+			"maas_sshkey_source":              resourceMaasSSHKeySource(),
+			"maas_ssh_key":                    resourceMaasSSHKey(),
+			"maas_ssl_key":                    resourceMaasSSLKey(),
+			"maas_storage_layout":             resourceMaasStorageLayout(),
 			"maas_dns_domain":                 resourceMaasDnsDomain(),
 			"maas_dns_record":                 resourceMaasDnsRecord(),
+			"maas_dns_records":                resourceMaasDnsRecords(),
+			"maas_dhcp_snippet":               resourceMaasDHCPSnippet(),
+			"maas_package_repository":         resourceMaasPackageRepository(),
 			"maas_space":                      resourceMaasSpace(),
+			"maas_bcache":                     resourceMaasBcache(),
+			"maas_bcache_cache_set":           resourceMaasBcacheCacheSet(),
 			"maas_block_device":               resourceMaasBlockDevice(),
+			"maas_config":                     resourceMaasConfig(),
+			"maas_network_discovery":          resourceMaasNetworkDiscovery(),
+			"maas_boot_source":                resourceMaasBootSource(),
+			"maas_boot_source_selection":      resourceMaasBootSourceSelection(),
+			"maas_boot_resource_import":       resourceMaasBootResourceImport(),
+			"maas_machine_network":            resourceMaasMachineNetwork(),
 			"maas_tag":                        resourceMaasTag(),
+			"maas_machine_tags":               resourceMaasMachineTags(),
+			"maas_resource_pool":              resourceMaasResourcePool(),
+			"maas_zone":                       resourceMaasZone(),
 			"maas_user":                       resourceMaasUser(),
+			"maas_user_api_key":               resourceMaasUserAPIKey(),
+			"maas_volume_group":               resourceMaasVolumeGroup(),
+			"maas_logical_volume":             resourceMaasLogicalVolume(),
+			"maas_partition":                  resourceMaasPartition(),
+			"maas_filesystem":                 resourceMaasFilesystem(),
+			"maas_special_filesystem":         resourceMaasSpecialFilesystem(),
+			"maas_machine_storage":            resourceMaasMachineStorage(),
 		},
 		DataSourcesMap: map[string]*schema.Resource{
-			"maas_fabric": dataSourceMaasFabric(),
-			"maas_vlan":   dataSourceMaasVlan(),
-			"maas_subnet": dataSourceMaasSubnet(),
+			"maas_fabric":                 dataSourceMaasFabric(),
+			"maas_vlan":                   dataSourceMaasVlan(),
+			"maas_subnet":                 dataSourceMaasSubnet(),
+			"maas_network_interface_link": dataSourceMaasNetworkInterfaceLink(),
+			"maas_spaces":                 dataSourceMaasSpaces(),
+			"maas_zones":                  dataSourceMaasZones(),
+			"maas_resource_pools":         dataSourceMaasResourcePools(),
+			"maas_allocatable_machines":   dataSourceMaasAllocatableMachines(),
+			"maas_rack_controller":        dataSourceMaasRackController(),
+			"maas_region_controller":      dataSourceMaasRegionController(),
+			"maas_machine":                dataSourceMaasMachine(),
+			"maas_boot_source_selections": dataSourceMaasBootSourceSelections(),
+			"maas_available_images":       dataSourceMaasAvailableImages(),
+			"maas_machine_events":         dataSourceMaasMachineEvents(),
+			"maas_interface":              dataSourceMaasInterface(),
+			"maas_default_domain":         dataSourceMaasDefaultDomain(),
 		},
 		ConfigureContextFunc: providerConfigure,
 	}
+	for _, r := range provider.ResourcesMap {
+		withRequestContext(r)
+	}
+	for _, r := range provider.DataSourcesMap {
+		withRequestContext(r)
+	}
+	return provider
+}
+
+// withRequestContext wraps the functions of the given resource, so that the
+// MAAS API requests they make are bound to the context of the operation.
+func withRequestContext(r *schema.Resource) {
+	meta := func(ctx context.Context, m interface{}) interface{} {
+		if clientConfig, ok := m.(*ClientConfig); ok {
+			return clientConfig.withContext(ctx)
+		}
+		return m
+	}
+	wrap := func(f func(context.Context, *schema.ResourceData, interface{}) diag.Diagnostics) func(context.Context, *schema.ResourceData, interface{}) diag.Diagnostics {
+		if f == nil {
+			return nil
+		}
+		return func(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+			return f(ctx, d, meta(ctx, m))
+		}
+	}
+	r.CreateContext = wrap(r.CreateContext)
+	r.ReadContext = wrap(r.ReadContext)
+	r.UpdateContext = wrap(r.UpdateContext)
+	r.DeleteContext = wrap(r.DeleteContext)
+	if f := r.CustomizeDiff; f != nil {
+		r.CustomizeDiff = func(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+			return f(ctx, d, meta(ctx, m))
+		}
+	}
+	if r.Importer != nil && r.Importer.StateContext != nil {
+		importer := *r.Importer
+		f := importer.StateContext
+		importer.StateContext = func(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+			return f(ctx, d, meta(ctx, m))
+		}
+		r.Importer = &importer
+	}
 }
 
 func providerConfigure(ctx context.Context, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
 	apiKey := d.Get("api_key").(string)
 	if apiKey == "" {
-		return nil, diag.FromErr(fmt.Errorf("MAAS API key cannot be empty"))
+		return nil, diagFromErr(fmt.Errorf("MAAS API key cannot be empty"))
 	}
 	apiURL := d.Get("api_url").(string)
 	if apiURL == "" {
-		return nil, diag.FromErr(fmt.Errorf("MAAS API URL cannot be empty"))
+		return nil, diagFromErr(fmt.Errorf("MAAS API URL cannot be empty"))
 	}
 	config := Config{
-		APIKey:     apiKey,
-		APIURL:     apiURL,
-		ApiVersion: d.Get("api_version").(string),
+		APIKey:             apiKey,
+		APIURL:             apiURL,
+		ApiVersion:         d.Get("api_version").(string),
+		HTTPTimeout:        time.Duration(d.Get("http_timeout_seconds").(int)) * time.Second,
+		MaxIdleConnections: d.Get("max_idle_connections").(int),
+		DefaultZone:        d.Get("default_zone").(string),
+		DefaultPool:        d.Get("default_pool").(string),
+		DefaultDomain:      d.Get("default_domain").(string),
 	}
 
 	// Warning or errors can be collected in a slice type
@@ -86,5 +213,24 @@ func providerConfigure(ctx context.Context, d *schema.ResourceData) (interface{}
 		return nil, diags
 	}
 
+	// Check that the MAAS server speaks the configured API version, so an
+	// unsupported version doesn't surface later as unrelated 404 errors
+	if _, err := c.Version.Get(); err != nil {
+		if serverErr, ok := getServerError(err); ok && serverErr.StatusCode == http.StatusNotFound {
+			diags = append(diags, diag.Diagnostic{
+				Severity: diag.Error,
+				Summary:  "Unsupported MAAS API version",
+				Detail:   fmt.Sprintf("The MAAS server (%s) does not support the API version %s.", config.APIURL, config.ApiVersion),
+			})
+			return nil, diags
+		}
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "Unable to connect to MAAS",
+			Detail:   fmt.Sprintf("Unable to get the MAAS server version: %s", err),
+		})
+		return nil, diags
+	}
+
 	return c, diags
 }
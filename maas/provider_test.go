@@ -1,9 +1,14 @@
 package maas
 
 import (
+	"context"
+	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/stretchr/testify/assert"
 )
 
 func TestProvider(t *testing.T) {
@@ -15,3 +20,150 @@ func TestProvider(t *testing.T) {
 func TestProvider_impl(t *testing.T) {
 	var _ *schema.Provider = Provider()
 }
+
+func TestProviderSensitiveAttributes(t *testing.T) {
+	testCases := []struct {
+		resource  string
+		attribute string
+	}{
+		{resource: "maas_machine", attribute: "power_parameters"},
+		{resource: "maas_vm_host", attribute: "power_pass"},
+		{resource: "maas_user", attribute: "password"},
+	}
+
+	p := Provider()
+	if !p.Schema["api_key"].Sensitive {
+		t.Errorf("provider attribute api_key is not sensitive")
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.resource+"."+testCase.attribute, func(t *testing.T) {
+			s := p.ResourcesMap[testCase.resource].Schema[testCase.attribute]
+			assert.True(t, s.Sensitive, fmt.Sprintf("%s.%s is not sensitive", testCase.resource, testCase.attribute))
+		})
+	}
+}
+
+func TestProviderWriteOnlySecretsDiff(t *testing.T) {
+	userState := map[string]string{
+		"id":       "admin",
+		"name":     "admin",
+		"email":    "admin@example.com",
+		"is_admin": "true",
+		"is_local": "true",
+	}
+	userConfig := map[string]interface{}{
+		"name":     "admin",
+		"password": "s3cr3t",
+		"email":    "admin@example.com",
+		"is_admin": true,
+	}
+	bootSourceState := map[string]string{
+		"id":               "1",
+		"url":              "http://images.maas.io/ephemeral-v3/stable/",
+		"keyring_filename": "",
+	}
+	bootSourceConfig := map[string]interface{}{
+		"url":          "http://images.maas.io/ephemeral-v3/stable/",
+		"keyring_data": "a2V5cmluZw==",
+	}
+	testCases := []struct {
+		name      string
+		resource  string
+		state     map[string]string
+		config    map[string]interface{}
+		attribute string
+		secret    string
+		forceNew  bool
+	}{
+		{
+			name:      "user is created",
+			resource:  "maas_user",
+			config:    userConfig,
+			attribute: "password",
+			secret:    "s3cr3t",
+		},
+		{
+			name:      "user password is set after import",
+			resource:  "maas_user",
+			state:     userState,
+			config:    userConfig,
+			attribute: "password",
+			secret:    "s3cr3t",
+		},
+		{
+			name:      "user password is changed",
+			resource:  "maas_user",
+			state:     mergeStringMaps(userState, map[string]string{"password": "0ld-s3cr3t"}),
+			config:    userConfig,
+			attribute: "password",
+			secret:    "s3cr3t",
+			forceNew:  true,
+		},
+		{
+			name:     "VM host is created",
+			resource: "maas_vm_host",
+			config: map[string]interface{}{
+				"type":          "virsh",
+				"power_address": "qemu+ssh://ubuntu@10.0.0.1/system",
+				"power_pass":    "s3cr3t",
+			},
+			attribute: "power_pass",
+			secret:    "s3cr3t",
+		},
+		{
+			name:      "boot source keyring data is set after import",
+			resource:  "maas_boot_source",
+			state:     bootSourceState,
+			config:    bootSourceConfig,
+			attribute: "keyring_data",
+		},
+		{
+			name:      "boot source keyring data is changed",
+			resource:  "maas_boot_source",
+			state:     mergeStringMaps(bootSourceState, map[string]string{"keyring_data": "b2xkLWtleXJpbmc="}),
+			config:    bootSourceConfig,
+			attribute: "keyring_data",
+			forceNew:  true,
+		},
+	}
+
+	p := Provider()
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			var state *terraform.InstanceState
+			if testCase.state != nil {
+				state = &terraform.InstanceState{ID: testCase.state["id"], Attributes: testCase.state}
+			}
+			r := p.ResourcesMap[testCase.resource]
+			diff, err := r.Diff(context.Background(), state, terraform.NewResourceConfigRaw(testCase.config), nil)
+			if err != nil {
+				t.Fatalf("err: %s", err)
+			}
+			attrDiff, ok := diff.Attributes[testCase.attribute]
+			if !ok {
+				t.Fatalf("%s.%s has no diff", testCase.resource, testCase.attribute)
+			}
+			assert.Equal(t, testCase.forceNew, attrDiff.RequiresNew, fmt.Sprintf("%s.%s replacement", testCase.resource, testCase.attribute))
+			if testCase.secret == "" {
+				return
+			}
+			// Terraform redacts the sensitive values in the plan output, so
+			// the secret mustn't be carried by any other attribute.
+			for k, v := range diff.Attributes {
+				if strings.Contains(v.Old, testCase.secret) || strings.Contains(v.New, testCase.secret) {
+					assert.True(t, v.Sensitive, fmt.Sprintf("%s.%s shows the secret in the diff", testCase.resource, k))
+				}
+			}
+		})
+	}
+}
+
+func mergeStringMaps(maps ...map[string]string) map[string]string {
+	merged := map[string]string{}
+	for _, m := range maps {
+		for k, v := range m {
+			merged[k] = v
+		}
+	}
+	return merged
+}
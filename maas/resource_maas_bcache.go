@@ -0,0 +1,262 @@
+package maas
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func resourceMaasBcache() *schema.Resource {
+	return &schema.Resource{
+		Description:   "Provides a resource to manage MAAS machines' bcache devices.",
+		CreateContext: resourceBcacheCreate,
+		ReadContext:   resourceBcacheRead,
+		UpdateContext: resourceBcacheUpdate,
+		DeleteContext: resourceBcacheDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: func(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+				idParts := strings.Split(d.Id(), ":")
+				if len(idParts) != 2 || idParts[0] == "" || idParts[1] == "" {
+					return nil, fmt.Errorf("unexpected format of ID (%q), expected MACHINE:BCACHE, where MACHINE is a system ID, hostname, FQDN, or MAC address, and BCACHE is an ID or name", d.Id())
+				}
+				clientConfig := m.(*ClientConfig)
+				machine, err := getMachine(clientConfig.Client, idParts[0])
+				if err != nil {
+					return nil, err
+				}
+				bcache, err := getBcache(clientConfig, machine.SystemID, idParts[1])
+				if err != nil {
+					return nil, err
+				}
+				tfState := map[string]interface{}{
+					"id":      fmt.Sprintf("%v", bcache.ID),
+					"machine": machine.SystemID,
+				}
+				if err := setTerraformState(d, tfState); err != nil {
+					return nil, err
+				}
+				return []*schema.ResourceData{d}, nil
+			},
+		},
+
+		Schema: map[string]*schema.Schema{
+			"machine": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The machine identifier (system ID, hostname, FQDN, or MAC address) that owns the bcache device.",
+			},
+			"name": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The bcache device name.",
+			},
+			"uuid": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				Description: "The bcache device UUID. This argument is computed if it's not given.",
+			},
+			"cache_set": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The identifier (ID or name) of the bcache cache set.",
+			},
+			"backing_device": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ExactlyOneOf: []string{"backing_device", "backing_partition"},
+				Description:  "The identifier (ID, name, ID path, or path) of the backing block device. Exactly one of `backing_device` or `backing_partition` must be set.",
+			},
+			"backing_partition": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ExactlyOneOf: []string{"backing_device", "backing_partition"},
+				Description:  "The identifier (ID or path) of the backing partition. Exactly one of `backing_device` or `backing_partition` must be set.",
+			},
+			"cache_mode": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "WRITETHROUGH",
+				ValidateFunc: validation.StringInSlice([]string{"WRITEBACK", "WRITETHROUGH", "WRITEAROUND"}, false),
+				Description:  "The bcache cache mode. Valid options are: `WRITEBACK`, `WRITETHROUGH`, and `WRITEAROUND`. Defaults to `WRITETHROUGH`.",
+			},
+			"size_gigabytes": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "The bcache device size (given in GB).",
+			},
+			"block_device_id": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The ID of the virtual block device of the bcache. It can be used to create other storage resources on top of the bcache device.",
+			},
+			"path": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The path of the bcache block device.",
+			},
+		},
+	}
+}
+
+func resourceBcacheCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	clientConfig := m.(*ClientConfig)
+
+	machine, err := getMachine(clientConfig.Client, d.Get("machine").(string))
+	if err != nil {
+		return diagFromErr(err)
+	}
+	params, err := getBcacheParams(clientConfig, d, machine.SystemID)
+	if err != nil {
+		return diagFromErr(err)
+	}
+	bcache, err := clientConfig.Bcaches.Create(machine.SystemID, params)
+	if err != nil {
+		return diagFromErr(err)
+	}
+	d.SetId(fmt.Sprintf("%v", bcache.ID))
+
+	return resourceBcacheRead(ctx, d, m)
+}
+
+func resourceBcacheRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	clientConfig := m.(*ClientConfig)
+
+	id, err := strconv.Atoi(d.Id())
+	if err != nil {
+		return diagFromErr(err)
+	}
+	machine, err := getMachine(clientConfig.Client, d.Get("machine").(string))
+	if err != nil {
+		return diagFromErr(err)
+	}
+	bcache, err := clientConfig.Bcaches.GetByID(machine.SystemID, id)
+	if err != nil {
+		return diagFromErr(err)
+	}
+	blockDevices, err := clientConfig.Client.BlockDevices.Get(machine.SystemID)
+	if err != nil {
+		return diagFromErr(err)
+	}
+	cacheSet := fmt.Sprintf("%v", bcache.CacheSet.ID)
+	if configuredCacheSet := d.Get("cache_set").(string); configuredCacheSet == bcache.CacheSet.Name {
+		cacheSet = configuredCacheSet
+	}
+	tfState := map[string]interface{}{
+		"name":              bcache.Name,
+		"uuid":              bcache.UUID,
+		"cache_set":         cacheSet,
+		"backing_device":    "",
+		"backing_partition": "",
+		"cache_mode":        bcache.CacheMode,
+		"size_gigabytes":    int(bcache.Size / (1024 * 1024 * 1024)),
+		"block_device_id":   fmt.Sprintf("%v", bcache.VirtualDevice.ID),
+		"path":              bcache.VirtualDevice.Path,
+	}
+	if bcache.BackingDevice.Type == "partition" {
+		tfState["backing_partition"] = getStorageDeviceTFIdentifier(blockDevices, &bcache.BackingDevice, []interface{}{d.Get("backing_partition")})
+	} else {
+		tfState["backing_device"] = getStorageDeviceTFIdentifier(blockDevices, &bcache.BackingDevice, []interface{}{d.Get("backing_device")})
+	}
+	if err := setTerraformState(d, tfState); err != nil {
+		return diagFromErr(err)
+	}
+
+	return nil
+}
+
+func resourceBcacheUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	clientConfig := m.(*ClientConfig)
+
+	id, err := strconv.Atoi(d.Id())
+	if err != nil {
+		return diagFromErr(err)
+	}
+	machine, err := getMachine(clientConfig.Client, d.Get("machine").(string))
+	if err != nil {
+		return diagFromErr(err)
+	}
+	params, err := getBcacheParams(clientConfig, d, machine.SystemID)
+	if err != nil {
+		return diagFromErr(err)
+	}
+	if _, err := clientConfig.Bcaches.Update(machine.SystemID, id, params); err != nil {
+		return diagFromErr(err)
+	}
+
+	return resourceBcacheRead(ctx, d, m)
+}
+
+func resourceBcacheDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	clientConfig := m.(*ClientConfig)
+
+	id, err := strconv.Atoi(d.Id())
+	if err != nil {
+		return diagFromErr(err)
+	}
+	machine, err := getMachine(clientConfig.Client, d.Get("machine").(string))
+	if err != nil {
+		return diagFromErr(err)
+	}
+	if err := clientConfig.Bcaches.Delete(machine.SystemID, id); err != nil {
+		return diagFromErr(err)
+	}
+
+	return nil
+}
+
+func getBcacheParams(clientConfig *ClientConfig, d *schema.ResourceData, machineID string) (*BcacheParams, error) {
+	cacheSet, err := getBcacheCacheSet(clientConfig, machineID, d.Get("cache_set").(string))
+	if err != nil {
+		return nil, err
+	}
+	blockDevices, err := clientConfig.Client.BlockDevices.Get(machineID)
+	if err != nil {
+		return nil, err
+	}
+	params := &BcacheParams{
+		Name:      d.Get("name").(string),
+		UUID:      d.Get("uuid").(string),
+		CacheSet:  cacheSet.ID,
+		CacheMode: d.Get("cache_mode").(string),
+	}
+	if backingDevice := d.Get("backing_device").(string); backingDevice != "" {
+		params.BackingDevice, err = getStorageDeviceID(blockDevices, machineID, backingDevice, false)
+	} else {
+		params.BackingPartition, err = getStorageDeviceID(blockDevices, machineID, d.Get("backing_partition").(string), true)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return params, nil
+}
+
+func findBcache(clientConfig *ClientConfig, machineID string, identifier string) (*Bcache, error) {
+	bcaches, err := clientConfig.Bcaches.Get(machineID)
+	if err != nil {
+		return nil, err
+	}
+	for _, b := range bcaches {
+		if fmt.Sprintf("%v", b.ID) == identifier || b.Name == identifier {
+			return &b, nil
+		}
+	}
+	return nil, nil
+}
+
+func getBcache(clientConfig *ClientConfig, machineID string, identifier string) (*Bcache, error) {
+	bcache, err := findBcache(clientConfig, machineID, identifier)
+	if err != nil {
+		return nil, err
+	}
+	if bcache == nil {
+		return nil, fmt.Errorf("bcache (%s) was not found on machine (%s)", identifier, machineID)
+	}
+	return bcache, nil
+}
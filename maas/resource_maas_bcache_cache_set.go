@@ -0,0 +1,210 @@
+package maas
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/maas/gomaasclient/client"
+)
+
+func resourceMaasBcacheCacheSet() *schema.Resource {
+	return &schema.Resource{
+		Description:   "Provides a resource to manage MAAS machines' bcache cache sets.",
+		CreateContext: resourceBcacheCacheSetCreate,
+		ReadContext:   resourceBcacheCacheSetRead,
+		UpdateContext: resourceBcacheCacheSetUpdate,
+		DeleteContext: resourceBcacheCacheSetDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: func(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+				idParts := strings.Split(d.Id(), ":")
+				if len(idParts) != 2 || idParts[0] == "" || idParts[1] == "" {
+					return nil, fmt.Errorf("unexpected format of ID (%q), expected MACHINE:CACHE_SET, where MACHINE is a system ID, hostname, FQDN, or MAC address, and CACHE_SET is an ID or name", d.Id())
+				}
+				clientConfig := m.(*ClientConfig)
+				machine, err := getMachine(clientConfig.Client, idParts[0])
+				if err != nil {
+					return nil, err
+				}
+				cacheSet, err := getBcacheCacheSet(clientConfig, machine.SystemID, idParts[1])
+				if err != nil {
+					return nil, err
+				}
+				tfState := map[string]interface{}{
+					"id":      fmt.Sprintf("%v", cacheSet.ID),
+					"machine": machine.SystemID,
+				}
+				if err := setTerraformState(d, tfState); err != nil {
+					return nil, err
+				}
+				return []*schema.ResourceData{d}, nil
+			},
+		},
+
+		Schema: map[string]*schema.Schema{
+			"machine": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The machine identifier (system ID, hostname, FQDN, or MAC address) that owns the cache set.",
+			},
+			"cache_device": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ExactlyOneOf: []string{"cache_device", "cache_partition"},
+				Description:  "The identifier (ID, name, ID path, or path) of the block device used as cache. Exactly one of `cache_device` or `cache_partition` must be set.",
+			},
+			"cache_partition": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ExactlyOneOf: []string{"cache_device", "cache_partition"},
+				Description:  "The identifier (ID or path) of the partition used as cache. Exactly one of `cache_device` or `cache_partition` must be set.",
+			},
+			"name": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The cache set name given by MAAS (e.g. `cache0`).",
+			},
+		},
+	}
+}
+
+func resourceBcacheCacheSetCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	clientConfig := m.(*ClientConfig)
+
+	machine, err := getMachine(clientConfig.Client, d.Get("machine").(string))
+	if err != nil {
+		return diagFromErr(err)
+	}
+	params, err := getBcacheCacheSetParams(clientConfig.Client, d, machine.SystemID)
+	if err != nil {
+		return diagFromErr(err)
+	}
+	cacheSet, err := clientConfig.BcacheCacheSets.Create(machine.SystemID, params)
+	if err != nil {
+		return diagFromErr(err)
+	}
+	d.SetId(fmt.Sprintf("%v", cacheSet.ID))
+
+	return resourceBcacheCacheSetRead(ctx, d, m)
+}
+
+func resourceBcacheCacheSetRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	clientConfig := m.(*ClientConfig)
+
+	id, err := strconv.Atoi(d.Id())
+	if err != nil {
+		return diagFromErr(err)
+	}
+	machine, err := getMachine(clientConfig.Client, d.Get("machine").(string))
+	if err != nil {
+		return diagFromErr(err)
+	}
+	cacheSet, err := clientConfig.BcacheCacheSets.GetByID(machine.SystemID, id)
+	if err != nil {
+		return diagFromErr(err)
+	}
+	blockDevices, err := clientConfig.Client.BlockDevices.Get(machine.SystemID)
+	if err != nil {
+		return diagFromErr(err)
+	}
+	tfState := map[string]interface{}{
+		"name":            cacheSet.Name,
+		"cache_device":    "",
+		"cache_partition": "",
+	}
+	if cacheSet.CacheDevice.Type == "partition" {
+		tfState["cache_partition"] = getStorageDeviceTFIdentifier(blockDevices, &cacheSet.CacheDevice, []interface{}{d.Get("cache_partition")})
+	} else {
+		tfState["cache_device"] = getStorageDeviceTFIdentifier(blockDevices, &cacheSet.CacheDevice, []interface{}{d.Get("cache_device")})
+	}
+	if err := setTerraformState(d, tfState); err != nil {
+		return diagFromErr(err)
+	}
+
+	return nil
+}
+
+func resourceBcacheCacheSetUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	clientConfig := m.(*ClientConfig)
+
+	id, err := strconv.Atoi(d.Id())
+	if err != nil {
+		return diagFromErr(err)
+	}
+	machine, err := getMachine(clientConfig.Client, d.Get("machine").(string))
+	if err != nil {
+		return diagFromErr(err)
+	}
+	params, err := getBcacheCacheSetParams(clientConfig.Client, d, machine.SystemID)
+	if err != nil {
+		return diagFromErr(err)
+	}
+	if _, err := clientConfig.BcacheCacheSets.Update(machine.SystemID, id, params); err != nil {
+		return diagFromErr(err)
+	}
+
+	return resourceBcacheCacheSetRead(ctx, d, m)
+}
+
+func resourceBcacheCacheSetDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	clientConfig := m.(*ClientConfig)
+
+	id, err := strconv.Atoi(d.Id())
+	if err != nil {
+		return diagFromErr(err)
+	}
+	machine, err := getMachine(clientConfig.Client, d.Get("machine").(string))
+	if err != nil {
+		return diagFromErr(err)
+	}
+	if err := clientConfig.BcacheCacheSets.Delete(machine.SystemID, id); err != nil {
+		return diagFromErr(err)
+	}
+
+	return nil
+}
+
+func getBcacheCacheSetParams(client *client.Client, d *schema.ResourceData, machineID string) (*BcacheCacheSetParams, error) {
+	blockDevices, err := client.BlockDevices.Get(machineID)
+	if err != nil {
+		return nil, err
+	}
+	params := &BcacheCacheSetParams{}
+	if cacheDevice := d.Get("cache_device").(string); cacheDevice != "" {
+		params.CacheDevice, err = getStorageDeviceID(blockDevices, machineID, cacheDevice, false)
+	} else {
+		params.CachePartition, err = getStorageDeviceID(blockDevices, machineID, d.Get("cache_partition").(string), true)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return params, nil
+}
+
+func findBcacheCacheSet(clientConfig *ClientConfig, machineID string, identifier string) (*BcacheCacheSet, error) {
+	cacheSets, err := clientConfig.BcacheCacheSets.Get(machineID)
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range cacheSets {
+		if fmt.Sprintf("%v", c.ID) == identifier || c.Name == identifier {
+			return &c, nil
+		}
+	}
+	return nil, nil
+}
+
+func getBcacheCacheSet(clientConfig *ClientConfig, machineID string, identifier string) (*BcacheCacheSet, error) {
+	cacheSet, err := findBcacheCacheSet(clientConfig, machineID, identifier)
+	if err != nil {
+		return nil, err
+	}
+	if cacheSet == nil {
+		return nil, fmt.Errorf("bcache cache set (%s) was not found on machine (%s)", identifier, machineID)
+	}
+	return cacheSet, nil
+}
@@ -6,8 +6,10 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/hashicorp/go-cty/cty"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	"github.com/maas/gomaasclient/client"
 	"github.com/maas/gomaasclient/entity"
 )
@@ -23,9 +25,9 @@ func resourceMaasBlockDevice() *schema.Resource {
 			StateContext: func(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
 				idParts := strings.Split(d.Id(), ":")
 				if len(idParts) != 2 || idParts[0] == "" || idParts[1] == "" {
-					return nil, fmt.Errorf("unexpected format of ID (%q), expected MACHINE:BLOCK_DEVICE", d.Id())
+					return nil, fmt.Errorf("unexpected format of ID (%q), expected MACHINE:BLOCK_DEVICE, where MACHINE is a system ID, hostname, FQDN, or MAC address, and BLOCK_DEVICE is an ID or name", d.Id())
 				}
-				client := m.(*client.Client)
+				client := m.(*ClientConfig).Client
 				machine, err := getMachine(client, idParts[0])
 				if err != nil {
 					return nil, err
@@ -53,7 +55,7 @@ func resourceMaasBlockDevice() *schema.Resource {
 				Type:        schema.TypeString,
 				Required:    true,
 				ForceNew:    true,
-				Description: "The machine identifier (system ID, hostname, or FQDN) that owns the block device.",
+				Description: "The machine identifier (system ID, hostname, FQDN, or MAC address) that owns the block device.",
 			},
 			"name": {
 				Type:        schema.TypeString,
@@ -85,8 +87,16 @@ func resourceMaasBlockDevice() *schema.Resource {
 					Schema: map[string]*schema.Schema{
 						"size_gigabytes": {
 							Type:        schema.TypeInt,
-							Required:    true,
-							Description: "The partition size (given in GB).",
+							Optional:    true,
+							Computed:    true,
+							Description: "The partition size (given in GB). Exactly one of `size` or `size_gigabytes` must be set.",
+						},
+						"size": {
+							Type:             schema.TypeString,
+							Optional:         true,
+							Computed:         true,
+							ValidateDiagFunc: validation.ToDiagFunc(validateSize),
+							Description:      "The partition size as a human-readable string (e.g. `100G`, `1.5T`, `512M`). The units are powers of 1024, and the size is rounded down to the block size. Exactly one of `size` or `size_gigabytes` must be set.",
 						},
 						"bootable": {
 							Type:        schema.TypeBool,
@@ -159,9 +169,15 @@ func resourceMaasBlockDevice() *schema.Resource {
 				Optional: true,
 				Computed: true,
 				Elem: &schema.Schema{
-					Type:        schema.TypeString,
-					Description: "A set of tag names assigned to the new block device. This argument is computed if it's not given.",
+					Type: schema.TypeString,
 				},
+				Description: "A set of tag names assigned to the block device (e.g. `ssd`). Tags added outside of Terraform are shown as drift. This argument is computed if it's not given.",
+			},
+			"wipe_on_delete": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Boolean value indicating if the file systems of the block device and its partitions are unmounted and unformatted before the block device is deleted. This is only meaningful while the machine is in the `Ready` or `Allocated` state. Defaults to `false`.",
 			},
 			"uuid": {
 				Type:        schema.TypeString,
@@ -173,25 +189,30 @@ func resourceMaasBlockDevice() *schema.Resource {
 				Computed:    true,
 				Description: "Block device path.",
 			},
+			"partition_table_type": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The partition table type (`GPT` or `MBR`) of the block device. MAAS chooses it when the first partition is created, depending on the block device size and the machine boot firmware, and it can't be set through the API.",
+			},
 		},
 	}
 }
 
 func resourceBlockDeviceCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	client := m.(*client.Client)
+	client := m.(*ClientConfig).Client
 
 	machine, err := getMachine(client, d.Get("machine").(string))
 	if err != nil {
-		return diag.FromErr(err)
+		return diagFromErr(err)
 	}
 	blockDevice, err := findBlockDevice(client, machine.SystemID, d.Get("name").(string))
 	if err != nil {
-		return diag.FromErr(err)
+		return diagFromErr(err)
 	}
 	if blockDevice == nil {
 		blockDevice, err = client.BlockDevices.Create(machine.SystemID, getBlockDeviceParams(d))
 		if err != nil {
-			return diag.FromErr(err)
+			return diagFromErr(err)
 		}
 	}
 	d.SetId(fmt.Sprintf("%v", blockDevice.ID))
@@ -200,79 +221,90 @@ func resourceBlockDeviceCreate(ctx context.Context, d *schema.ResourceData, m in
 }
 
 func resourceBlockDeviceRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	client := m.(*client.Client)
+	client := m.(*ClientConfig).Client
 
 	id, err := strconv.Atoi(d.Id())
 	if err != nil {
-		return diag.FromErr(err)
+		return diagFromErr(err)
 	}
 	machine, err := getMachine(client, d.Get("machine").(string))
 	if err != nil {
-		return diag.FromErr(err)
+		return diagFromErr(err)
 	}
 	blockDevice, err := client.BlockDevice.Get(machine.SystemID, id)
 	if err != nil {
-		return diag.FromErr(err)
+		return diagFromErr(err)
 	}
 	tfState := map[string]interface{}{
-		"partitions": getBlockDevicePartitionsTFState(blockDevice),
-		"model":      blockDevice.Model,
-		"serial":     blockDevice.Serial,
-		"id_path":    blockDevice.IDPath,
-		"tags":       blockDevice.Tags,
-		"uuid":       blockDevice.UUID,
-		"path":       blockDevice.Path,
+		"partitions":           getBlockDevicePartitionsTFState(d, blockDevice),
+		"model":                blockDevice.Model,
+		"serial":               blockDevice.Serial,
+		"id_path":              blockDevice.IDPath,
+		"tags":                 blockDevice.Tags,
+		"uuid":                 blockDevice.UUID,
+		"path":                 blockDevice.Path,
+		"partition_table_type": blockDevice.PartitionTableType,
+	}
+	// The boot device flag can only be set, so it shows a diff only if it's
+	// configured and another block device became the boot device
+	if d.Get("is_boot_device").(bool) {
+		tfState["is_boot_device"] = machine.BootDisk.ID == id
 	}
 	if err := setTerraformState(d, tfState); err != nil {
-		return diag.FromErr(err)
+		return diagFromErr(err)
 	}
 
 	return nil
 }
 
 func resourceBlockDeviceUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	client := m.(*client.Client)
+	client := m.(*ClientConfig).Client
 
 	id, err := strconv.Atoi(d.Id())
 	if err != nil {
-		return diag.FromErr(err)
+		return diagFromErr(err)
 	}
 	machine, err := getMachine(client, d.Get("machine").(string))
 	if err != nil {
-		return diag.FromErr(err)
+		return diagFromErr(err)
 	}
 	blockDevice, err := client.BlockDevice.Update(machine.SystemID, id, getBlockDeviceParams(d))
 	if err != nil {
-		return diag.FromErr(err)
+		return diagFromErr(err)
 	}
 	if err := setBlockDeviceTags(client, d, blockDevice); err != nil {
-		return diag.FromErr(err)
+		return diagFromErr(err)
 	}
 	if p, ok := d.GetOk("is_boot_device"); ok && p.(bool) {
 		if err := client.BlockDevice.SetBootDisk(machine.SystemID, id); err != nil {
-			return diag.FromErr(err)
+			return diagFromErr(err)
 		}
 	}
 	if err := updateBlockDevicePartitions(client, d, blockDevice); err != nil {
-		return diag.FromErr(err)
+		return diagFromErr(err)
 	}
 
 	return resourceBlockDeviceRead(ctx, d, m)
 }
 
 func resourceBlockDeviceDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	client := m.(*client.Client)
+	client := m.(*ClientConfig).Client
 
 	id, err := strconv.Atoi(d.Id())
 	if err != nil {
-		return diag.FromErr(err)
+		return diagFromErr(err)
 	}
 	machine, err := getMachine(client, d.Get("machine").(string))
 	if err != nil {
-		return diag.FromErr(err)
+		return diagFromErr(err)
+	}
+	if d.Get("wipe_on_delete").(bool) {
+		if err := wipeBlockDevice(client, machine.SystemID, id); err != nil {
+			return diagFromErr(err)
+		}
 	}
 	if err := client.BlockDevice.Delete(machine.SystemID, id); err != nil {
-		return diag.FromErr(err)
+		return diagFromErr(err)
 	}
 
 	return nil
@@ -295,13 +327,80 @@ func findBlockDevice(client *client.Client, machineID string, identifier string)
 		return nil, err
 	}
 	for _, b := range blockDevices {
-		if fmt.Sprintf("%v", b.ID) == identifier || b.Name == identifier || b.IDPath == identifier || b.Path == identifier {
+		if isBlockDeviceIdentifier(&b, identifier) {
 			return &b, nil
 		}
 	}
 	return nil, nil
 }
 
+// isBlockDeviceIdentifier checks if the identifier (ID, name, ID path, or
+// path) refers to the given block device.
+func isBlockDeviceIdentifier(blockDevice *entity.BlockDevice, identifier string) bool {
+	return fmt.Sprintf("%v", blockDevice.ID) == identifier || blockDevice.Name == identifier || blockDevice.IDPath == identifier || blockDevice.Path == identifier
+}
+
+// isPartitionIdentifier checks if the identifier (ID or path) refers to the
+// given partition.
+func isPartitionIdentifier(partition *entity.BlockDevicePartition, identifier string) bool {
+	return fmt.Sprintf("%v", partition.ID) == identifier || partition.Path == identifier
+}
+
+// getStorageDeviceID returns the ID of the block device, or of the partition
+// if isPartition is set, with the given identifier. It's used by the storage
+// resources built on top of block devices and partitions.
+func getStorageDeviceID(blockDevices []entity.BlockDevice, machineID string, identifier string, isPartition bool) (int, error) {
+	for _, b := range blockDevices {
+		if !isPartition && isBlockDeviceIdentifier(&b, identifier) {
+			return b.ID, nil
+		}
+		if isPartition {
+			for _, p := range b.Partitions {
+				if isPartitionIdentifier(&p, identifier) {
+					return p.ID, nil
+				}
+			}
+		}
+	}
+	if isPartition {
+		return 0, fmt.Errorf("partition (%s) was not found on machine (%s)", identifier, machineID)
+	}
+	return 0, fmt.Errorf("block device (%s) was not found on machine (%s)", identifier, machineID)
+}
+
+// getStorageDeviceTFIdentifier returns the configured identifier referring to
+// the given block device or partition, so the identifiers don't show a diff.
+// Otherwise, the block device name or the partition path is returned.
+func getStorageDeviceTFIdentifier(blockDevices []entity.BlockDevice, device *StorageDevice, configured []interface{}) string {
+	for _, b := range blockDevices {
+		if device.Type != "partition" && b.ID == device.ID {
+			for _, c := range configured {
+				if isBlockDeviceIdentifier(&b, c.(string)) {
+					return c.(string)
+				}
+			}
+			return b.Name
+		}
+		if device.Type == "partition" {
+			for _, p := range b.Partitions {
+				if p.ID != device.ID {
+					continue
+				}
+				for _, c := range configured {
+					if isPartitionIdentifier(&p, c.(string)) {
+						return c.(string)
+					}
+				}
+				return p.Path
+			}
+		}
+	}
+	if device.Type == "partition" {
+		return device.Path
+	}
+	return device.Name
+}
+
 func getBlockDevice(client *client.Client, machineID string, identifier string) (*entity.BlockDevice, error) {
 	blockDevice, err := findBlockDevice(client, machineID, identifier)
 	if err != nil {
@@ -313,35 +412,78 @@ func getBlockDevice(client *client.Client, machineID string, identifier string)
 	return blockDevice, nil
 }
 
+// setBlockDeviceTags reconciles the block device tags with the configured
+// ones, adding and removing only the tags that differ.
 func setBlockDeviceTags(client *client.Client, d *schema.ResourceData, blockDevice *entity.BlockDevice) error {
-	p, ok := d.GetOk("tags")
-	if !ok {
+	if d.GetRawConfig().GetAttr("tags").IsNull() {
 		return nil
 	}
-	tags := p.(*schema.Set).List()
-	blockDevice, err := client.BlockDevice.Get(blockDevice.SystemID, blockDevice.ID)
+	current := map[string]bool{}
+	for _, t := range blockDevice.Tags {
+		current[t] = true
+	}
+	wanted := map[string]bool{}
+	for _, t := range d.Get("tags").(*schema.Set).List() {
+		wanted[t.(string)] = true
+		if !current[t.(string)] {
+			if _, err := client.BlockDevice.AddTag(blockDevice.SystemID, blockDevice.ID, t.(string)); err != nil {
+				return err
+			}
+		}
+	}
+	for _, t := range blockDevice.Tags {
+		if !wanted[t] {
+			if _, err := client.BlockDevice.RemoveTag(blockDevice.SystemID, blockDevice.ID, t); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// wipeBlockDevice unmounts and unformats the block device and all of its
+// partitions, so no stale file system signatures are left on the disk.
+func wipeBlockDevice(client *client.Client, machineID string, id int) error {
+	blockDevice, err := client.BlockDevice.Get(machineID, id)
 	if err != nil {
 		return err
 	}
-	// Remove existing tags
-	for _, t := range blockDevice.Tags {
-		if _, err = client.BlockDevice.RemoveTag(blockDevice.SystemID, blockDevice.ID, t); err != nil {
+	for _, part := range blockDevice.Partitions {
+		if part.FileSystem.MountPoint != "" {
+			if _, err := client.BlockDevicePartition.Unmount(machineID, id, part.ID); err != nil {
+				return err
+			}
+		}
+		if part.FileSystem.FSType != "" {
+			if _, err := client.BlockDevicePartition.Unformat(machineID, id, part.ID); err != nil {
+				return err
+			}
+		}
+	}
+	if blockDevice.Filesystem.MountPoint != "" {
+		if _, err := client.BlockDevice.Unmount(machineID, id); err != nil {
 			return err
 		}
 	}
-	// Add new tags
-	for _, t := range tags {
-		if _, err = client.BlockDevice.AddTag(blockDevice.SystemID, blockDevice.ID, t.(string)); err != nil {
+	if blockDevice.Filesystem.FSType != "" {
+		if _, err := client.BlockDevice.Unformat(machineID, id); err != nil {
 			return err
 		}
 	}
 	return nil
 }
 
-func getBlockDevicePartitionsTFState(blockDevice *entity.BlockDevice) []map[string]interface{} {
+func getBlockDevicePartitionsTFState(d *schema.ResourceData, blockDevice *entity.BlockDevice) []map[string]interface{} {
 	partitions := make([]map[string]interface{}, len(blockDevice.Partitions))
 	for i, p := range blockDevice.Partitions {
+		// Keep the configured size if it matches, so it doesn't show a diff
+		// against the exact bytes MAAS returns
+		size := formatSize(int64(p.Size))
+		if configuredSize, ok := d.Get(fmt.Sprintf("partitions.%d.size", i)).(string); ok && isSameSize(configuredSize, int64(p.Size), int64(blockDevice.BlockSize)) {
+			size = configuredSize
+		}
 		part := map[string]interface{}{
+			"size":           size,
 			"size_gigabytes": int(p.Size / (1024 * 1024 * 1024)),
 			"bootable":       p.Bootable,
 			"tags":           p.Tags,
@@ -356,6 +498,29 @@ func getBlockDevicePartitionsTFState(blockDevice *entity.BlockDevice) []map[stri
 	return partitions
 }
 
+// getBlockDevicePartitionSize returns the size in bytes of the partition with
+// the given index, from either its `size` or `size_gigabytes` configuration.
+func getBlockDevicePartitionSize(d *schema.ResourceData, index int, blockSize int64) (int64, error) {
+	sizeGigabytesSet := true
+	// The partitions are computed from the state when they're not configured
+	if rawPartitions := d.GetRawConfig().GetAttr("partitions"); !rawPartitions.IsNull() && rawPartitions.IsKnown() && rawPartitions.LengthInt() > index {
+		rawPartition := rawPartitions.Index(cty.NumberIntVal(int64(index)))
+		sizeSet := !rawPartition.GetAttr("size").IsNull()
+		sizeGigabytesSet = !rawPartition.GetAttr("size_gigabytes").IsNull()
+		if sizeSet == sizeGigabytesSet {
+			return 0, fmt.Errorf("partition %d: exactly one of `size` or `size_gigabytes` must be set", index)
+		}
+	}
+	if sizeGigabytesSet {
+		return int64(d.Get(fmt.Sprintf("partitions.%d.size_gigabytes", index)).(int)) * 1024 * 1024 * 1024, nil
+	}
+	size, err := parseSize(d.Get(fmt.Sprintf("partitions.%d.size", index)).(string))
+	if err != nil {
+		return 0, err
+	}
+	return roundSize(size, blockSize), nil
+}
+
 func updateBlockDevicePartitions(client *client.Client, d *schema.ResourceData, blockDevice *entity.BlockDevice) error {
 	p, ok := d.GetOk("partitions")
 	if !ok {
@@ -370,10 +535,14 @@ func updateBlockDevicePartitions(client *client.Client, d *schema.ResourceData,
 	}
 	// Create new partitions given by the user
 	partitions := p.([]interface{})
-	for _, part := range partitions {
+	for i, part := range partitions {
 		partition := part.(map[string]interface{})
+		size, err := getBlockDevicePartitionSize(d, i, int64(blockDevice.BlockSize))
+		if err != nil {
+			return err
+		}
 		partitionParams := entity.BlockDevicePartitionParams{
-			Size:     partition["size_gigabytes"].(int) * 1024 * 1024 * 1024,
+			Size:     int(size),
 			Bootable: partition["bootable"].(bool),
 		}
 		blockDevicePartition, err := client.BlockDevicePartitions.Create(blockDevice.SystemID, blockDevice.ID, &partitionParams)
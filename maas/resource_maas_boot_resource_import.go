@@ -0,0 +1,158 @@
+package maas
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func resourceMaasBootResourceImport() *schema.Resource {
+	return &schema.Resource{
+		Description:   "Provides a resource to import a MAAS boot resource (image) and wait until it's synced, so the machines can be deployed with it.\n\n**NOTE:** The boot resource must be selected by one of the MAAS boot sources. Destroying this resource doesn't delete the boot resource.",
+		CreateContext: resourceBootResourceImportCreate,
+		ReadContext:   resourceBootResourceImportRead,
+		DeleteContext: resourceBootResourceImportDelete,
+
+		Schema: map[string]*schema.Schema{
+			"os": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The boot resource operating system (e.g. `ubuntu`).",
+			},
+			"release": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The boot resource release (e.g. `jammy`).",
+			},
+			"architecture": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Default:     "amd64/generic",
+				Description: "The boot resource architecture. Defaults to `amd64/generic`.",
+			},
+			"start_import": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				ForceNew:    true,
+				Default:     true,
+				Description: "Boolean value indicating if the boot resources import is started before waiting. When `false`, Terraform only waits for an import started by other means (e.g. MAAS periodic import). Defaults to `true`.",
+			},
+			"triggers": {
+				Type:     schema.TypeMap,
+				Optional: true,
+				ForceNew: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+				Description: "A map of arbitrary values that start the import and the wait again when they change (e.g. the attributes of the `maas_boot_source_selection` resources, so the import runs after the selections change).",
+			},
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(60 * time.Minute),
+		},
+	}
+}
+
+func resourceBootResourceImportCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	bootResources := m.(*ClientConfig).BootResources
+
+	name := fmt.Sprintf("%s/%s", d.Get("os").(string), d.Get("release").(string))
+	architecture := d.Get("architecture").(string)
+	if d.Get("start_import").(bool) {
+		if err := bootResources.Import(); err != nil {
+			return diagFromErr(err)
+		}
+	}
+	if err := waitForBootResourceSync(ctx, bootResources, name, architecture, d.Timeout(schema.TimeoutCreate)); err != nil {
+		return diagFromErr(err)
+	}
+	d.SetId(fmt.Sprintf("%s:%s", name, architecture))
+
+	return resourceBootResourceImportRead(ctx, d, m)
+}
+
+func resourceBootResourceImportRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	bootResources := m.(*ClientConfig).BootResources
+
+	name := fmt.Sprintf("%s/%s", d.Get("os").(string), d.Get("release").(string))
+	bootResource, err := findBootResource(bootResources, name, d.Get("architecture").(string))
+	if err != nil {
+		return diagFromErr(err)
+	}
+	// The boot resource was deleted, so it needs to be imported again
+	if bootResource == nil {
+		log.Printf("[DEBUG] Boot resource (%s) was not found, removing it from state\n", d.Id())
+		d.SetId("")
+	}
+
+	return nil
+}
+
+func resourceBootResourceImportDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	return nil
+}
+
+func findBootResource(bootResources *BootResources, name string, architecture string) (*BootResource, error) {
+	resources, err := bootResources.Get()
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range resources {
+		if r.Name == name && r.Architecture == architecture {
+			return bootResources.GetByID(r.ID)
+		}
+	}
+	return nil, nil
+}
+
+// waitForBootResourceSync polls the boot resources until the given one has a
+// complete resource set. On timeout, the returned error contains the last known
+// import progress.
+func waitForBootResourceSync(ctx context.Context, bootResources *BootResources, name string, architecture string, timeout time.Duration) error {
+	log.Printf("[DEBUG] Waiting for boot resource (%s %s) to be synced\n", name, architecture)
+	lastStatus := ""
+	stateConf := &resource.StateChangeConf{
+		Pending: []string{"pending"},
+		Target:  []string{"synced"},
+		Refresh: func() (interface{}, string, error) {
+			bootResource, err := findBootResource(bootResources, name, architecture)
+			if err != nil {
+				return nil, "", err
+			}
+			isImporting, err := bootResources.IsImporting()
+			if err != nil {
+				return nil, "", err
+			}
+			if bootResource == nil {
+				lastStatus = fmt.Sprintf("not found in the boot resources, import running: %t", isImporting)
+				return name, "pending", nil
+			}
+			progress := 0
+			for _, s := range bootResource.Sets {
+				if s.Complete {
+					return bootResource, "synced", nil
+				}
+				if s.Progress > progress {
+					progress = s.Progress
+				}
+			}
+			lastStatus = fmt.Sprintf("%d%% downloaded, import running: %t", progress, isImporting)
+			return bootResource, "pending", nil
+		},
+		Timeout:    timeout,
+		Delay:      10 * time.Second,
+		MinTimeout: 5 * time.Second,
+	}
+	if _, err := stateConf.WaitForStateContext(ctx); err != nil {
+		return fmt.Errorf("boot resource (%s %s) is not synced, last status: %s: %w", name, architecture, lastStatus, err)
+	}
+	return nil
+}
@@ -0,0 +1,137 @@
+package maas
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"log"
+	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func resourceMaasBootSource() *schema.Resource {
+	return &schema.Resource{
+		Description:   "Provides a resource to manage the MAAS boot sources, the simplestreams mirrors the boot images are imported from.",
+		CreateContext: resourceBootSourceCreate,
+		ReadContext:   resourceBootSourceRead,
+		UpdateContext: resourceBootSourceUpdate,
+		DeleteContext: resourceBootSourceDelete,
+		CustomizeDiff: resourceBootSourceCustomizeDiff,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"url": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The URL of the simplestreams mirror (e.g. `http://images.maas.io/ephemeral-v3/stable/`).",
+			},
+			"keyring_filename": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ExactlyOneOf: []string{"keyring_filename", "keyring_data"},
+				Description:  "The path of the GPG keyring file on the MAAS region controllers, used to verify the mirror (e.g. `/usr/share/keyrings/ubuntu-cloudimage-keyring.gpg`). It conflicts with `keyring_data`. This argument is computed if it's not set.",
+			},
+			"keyring_data": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				ExactlyOneOf:     []string{"keyring_filename", "keyring_data"},
+				ValidateDiagFunc: validation.ToDiagFunc(validation.StringIsBase64),
+				Description:      "The base64 encoded GPG keyring used to verify the mirror (e.g. `filebase64(\"keyring.gpg\")`). MAAS accepts it only when the boot source is created, so changing it replaces the boot source. MAAS never returns it, so after the boot source is imported, the configured keyring is only recorded in the state. It conflicts with `keyring_filename`.",
+			},
+		},
+	}
+}
+
+func resourceBootSourceCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	params, err := getBootSourceParams(d)
+	if err != nil {
+		return diagFromErr(err)
+	}
+	bootSource, err := m.(*ClientConfig).BootSources.Create(params)
+	if err != nil {
+		return diagFromErr(err)
+	}
+	d.SetId(fmt.Sprintf("%v", bootSource.ID))
+
+	return resourceBootSourceRead(ctx, d, m)
+}
+
+func resourceBootSourceRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	id, err := strconv.Atoi(d.Id())
+	if err != nil {
+		return diagFromErr(err)
+	}
+	bootSource, err := m.(*ClientConfig).BootSources.Get(id)
+	if err != nil {
+		if isNotFoundError(err) {
+			log.Printf("[DEBUG] Boot source (%s) was not found, removing it from state\n", d.Id())
+			d.SetId("")
+			return nil
+		}
+		return diagFromErr(err)
+	}
+	tfState := map[string]interface{}{
+		"url":              bootSource.URL,
+		"keyring_filename": bootSource.KeyringFilename,
+	}
+	if err := setTerraformState(d, tfState); err != nil {
+		return diagFromErr(err)
+	}
+
+	return nil
+}
+
+func resourceBootSourceUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	id, err := strconv.Atoi(d.Id())
+	if err != nil {
+		return diagFromErr(err)
+	}
+	params, err := getBootSourceParams(d)
+	if err != nil {
+		return diagFromErr(err)
+	}
+	if _, err := m.(*ClientConfig).BootSources.Update(id, params); err != nil {
+		return diagFromErr(err)
+	}
+
+	return resourceBootSourceRead(ctx, d, m)
+}
+
+func resourceBootSourceDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	id, err := strconv.Atoi(d.Id())
+	if err != nil {
+		return diagFromErr(err)
+	}
+	if err := m.(*ClientConfig).BootSources.Delete(id); err != nil {
+		return diagFromErr(err)
+	}
+
+	return nil
+}
+
+// resourceBootSourceCustomizeDiff replaces the boot source when its keyring
+// data changes. An imported boot source with neither keyring in the state
+// was created with keyring data that MAAS doesn't return.
+func resourceBootSourceCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	oldKeyringData, _ := d.GetChange("keyring_data")
+	oldKeyringFilename, _ := d.GetChange("keyring_filename")
+	return forceNewOnWriteOnlyChange(d, "keyring_data", oldKeyringData.(string) == "" && oldKeyringFilename.(string) == "")
+}
+
+func getBootSourceParams(d *schema.ResourceData) (*BootSourceParams, error) {
+	keyringData, err := base64.StdEncoding.DecodeString(d.Get("keyring_data").(string))
+	if err != nil {
+		return nil, err
+	}
+	return &BootSourceParams{
+		URL:             d.Get("url").(string),
+		KeyringFilename: d.Get("keyring_filename").(string),
+		KeyringData:     keyringData,
+	}, nil
+}
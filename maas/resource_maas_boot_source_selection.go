@@ -0,0 +1,196 @@
+package maas
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func resourceMaasBootSourceSelection() *schema.Resource {
+	return &schema.Resource{
+		Description:   "Provides a resource to manage the boot images selected to be imported from a MAAS boot source.",
+		CreateContext: resourceBootSourceSelectionCreate,
+		ReadContext:   resourceBootSourceSelectionRead,
+		UpdateContext: resourceBootSourceSelectionUpdate,
+		DeleteContext: resourceBootSourceSelectionDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: func(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+				idParts := strings.Split(d.Id(), ":")
+				if len(idParts) != 2 || idParts[0] == "" || idParts[1] == "" {
+					return nil, fmt.Errorf("unexpected format of ID (%q), expected BOOT_SOURCE:SELECTION, where BOOT_SOURCE is a boot source ID, and SELECTION is a selection ID or OS/RELEASE (e.g. `ubuntu/jammy`)", d.Id())
+				}
+				bootSourceID, err := strconv.Atoi(idParts[0])
+				if err != nil {
+					return nil, err
+				}
+				selection, err := getBootSourceSelection(m.(*ClientConfig), bootSourceID, idParts[1])
+				if err != nil {
+					return nil, err
+				}
+				tfState := map[string]interface{}{
+					"id":          fmt.Sprintf("%v", selection.ID),
+					"boot_source": bootSourceID,
+				}
+				if err := setTerraformState(d, tfState); err != nil {
+					return nil, err
+				}
+				return []*schema.ResourceData{d}, nil
+			},
+		},
+
+		Schema: map[string]*schema.Schema{
+			"boot_source": {
+				Type:        schema.TypeInt,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The ID of the boot source.",
+			},
+			"os": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The operating system to import (e.g. `ubuntu`).",
+			},
+			"release": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The release of the operating system to import (e.g. `jammy`).",
+			},
+			"arches": {
+				Type:     schema.TypeSet,
+				Optional: true,
+				Computed: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+				Description: "A set of the architectures to import (e.g. `amd64`). MAAS imports all of them (`*`) if it's not set. This argument is computed if it's not set.",
+			},
+			"subarches": {
+				Type:     schema.TypeSet,
+				Optional: true,
+				Computed: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+				Description: "A set of the sub-architectures to import (e.g. `generic` or `hwe-22.04`). MAAS imports all of them (`*`) if it's not set. This argument is computed if it's not set.",
+			},
+			"labels": {
+				Type:     schema.TypeSet,
+				Optional: true,
+				Computed: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+				Description: "A set of the labels to import (e.g. `release`). MAAS imports all of them (`*`) if it's not set. This argument is computed if it's not set.",
+			},
+		},
+	}
+}
+
+func resourceBootSourceSelectionCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	selection, err := m.(*ClientConfig).BootSources.CreateSelection(d.Get("boot_source").(int), getBootSourceSelectionParams(d))
+	if err != nil {
+		return diagFromErr(err)
+	}
+	d.SetId(fmt.Sprintf("%v", selection.ID))
+
+	return resourceBootSourceSelectionRead(ctx, d, m)
+}
+
+func resourceBootSourceSelectionRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	selection, err := findBootSourceSelection(m.(*ClientConfig), d.Get("boot_source").(int), d.Id())
+	if err != nil {
+		return diagFromErr(err)
+	}
+	if selection == nil {
+		log.Printf("[DEBUG] Boot source selection (%s) was not found, removing it from state\n", d.Id())
+		d.SetId("")
+		return nil
+	}
+	tfState := map[string]interface{}{
+		"os":        selection.OS,
+		"release":   selection.Release,
+		"arches":    selection.Arches,
+		"subarches": selection.Subarches,
+		"labels":    selection.Labels,
+	}
+	if err := setTerraformState(d, tfState); err != nil {
+		return diagFromErr(err)
+	}
+
+	return nil
+}
+
+func resourceBootSourceSelectionUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	id, err := strconv.Atoi(d.Id())
+	if err != nil {
+		return diagFromErr(err)
+	}
+	if _, err := m.(*ClientConfig).BootSources.UpdateSelection(d.Get("boot_source").(int), id, getBootSourceSelectionParams(d)); err != nil {
+		return diagFromErr(err)
+	}
+
+	return resourceBootSourceSelectionRead(ctx, d, m)
+}
+
+func resourceBootSourceSelectionDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	id, err := strconv.Atoi(d.Id())
+	if err != nil {
+		return diagFromErr(err)
+	}
+	if err := m.(*ClientConfig).BootSources.DeleteSelection(d.Get("boot_source").(int), id); err != nil {
+		return diagFromErr(err)
+	}
+
+	return nil
+}
+
+func getBootSourceSelectionParams(d *schema.ResourceData) *BootSourceSelectionParams {
+	params := &BootSourceSelectionParams{
+		OS:      d.Get("os").(string),
+		Release: d.Get("release").(string),
+	}
+	if p, ok := d.GetOk("arches"); ok {
+		params.Arches = convertToStringSlice(p.(*schema.Set).List())
+	}
+	if p, ok := d.GetOk("subarches"); ok {
+		params.Subarches = convertToStringSlice(p.(*schema.Set).List())
+	}
+	if p, ok := d.GetOk("labels"); ok {
+		params.Labels = convertToStringSlice(p.(*schema.Set).List())
+	}
+	return params
+}
+
+func findBootSourceSelection(clientConfig *ClientConfig, bootSourceID int, identifier string) (*BootSourceSelection, error) {
+	selections, err := clientConfig.BootSources.GetSelections(bootSourceID)
+	if err != nil {
+		if isNotFoundError(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	for _, s := range selections {
+		if fmt.Sprintf("%v", s.ID) == identifier || fmt.Sprintf("%s/%s", s.OS, s.Release) == identifier {
+			return &s, nil
+		}
+	}
+	return nil, nil
+}
+
+func getBootSourceSelection(clientConfig *ClientConfig, bootSourceID int, identifier string) (*BootSourceSelection, error) {
+	selection, err := findBootSourceSelection(clientConfig, bootSourceID, identifier)
+	if err != nil {
+		return nil, err
+	}
+	if selection == nil {
+		return nil, fmt.Errorf("boot source selection (%s) was not found", identifier)
+	}
+	return selection, nil
+}
@@ -0,0 +1,234 @@
+package maas
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/maas/gomaasclient/api"
+)
+
+type maasConfigSetting struct {
+	Type            schema.ValueType
+	Default         string
+	ComputedDefault bool
+}
+
+var (
+	// Known MAAS config settings, with their value type and documented default.
+	// Settings with a computed default are left unchanged when the resource is
+	// destroyed.
+	maasConfigSettings = map[string]maasConfigSetting{
+		"active_discovery_interval":      {Type: schema.TypeInt, Default: "10800"},
+		"boot_images_auto_import":        {Type: schema.TypeBool, Default: "true"},
+		"commissioning_distro_series":    {Type: schema.TypeString, Default: "jammy"},
+		"completed_intro":                {Type: schema.TypeBool, Default: "false"},
+		"curtin_verbose":                 {Type: schema.TypeBool, Default: "true"},
+		"default_distro_series":          {Type: schema.TypeString, Default: "jammy"},
+		"default_dns_ttl":                {Type: schema.TypeInt, Default: "30"},
+		"default_min_hwe_kernel":         {Type: schema.TypeString, Default: ""},
+		"default_osystem":                {Type: schema.TypeString, Default: "ubuntu"},
+		"default_storage_layout":         {Type: schema.TypeString, Default: "flat"},
+		"disk_erase_with_quick_erase":    {Type: schema.TypeBool, Default: "false"},
+		"disk_erase_with_secure_erase":   {Type: schema.TypeBool, Default: "false"},
+		"dns_trusted_acl":                {Type: schema.TypeString, Default: ""},
+		"dnssec_validation":              {Type: schema.TypeString, Default: "auto"},
+		"enable_analytics":               {Type: schema.TypeBool, Default: "true"},
+		"enable_disk_erasing_on_release": {Type: schema.TypeBool, Default: "false"},
+		"enable_http_proxy":              {Type: schema.TypeBool, Default: "true"},
+		"enable_third_party_drivers":     {Type: schema.TypeBool, Default: "true"},
+		"enlist_commissioning":           {Type: schema.TypeBool, Default: "true"},
+		"http_proxy":                     {Type: schema.TypeString, Default: ""},
+		"kernel_opts":                    {Type: schema.TypeString, Default: ""},
+		"maas_internal_domain":           {Type: schema.TypeString, Default: "maas-internal"},
+		"maas_name":                      {Type: schema.TypeString, ComputedDefault: true},
+		"maas_proxy_port":                {Type: schema.TypeInt, Default: "8000"},
+		"maas_syslog_port":               {Type: schema.TypeInt, Default: "5247"},
+		"network_discovery":              {Type: schema.TypeString, Default: "enabled"},
+		"ntp_external_only":              {Type: schema.TypeBool, Default: "false"},
+		"ntp_servers":                    {Type: schema.TypeString, Default: "ntp.ubuntu.com"},
+		"prefer_v4_proxy":                {Type: schema.TypeBool, Default: "false"},
+		"release_notifications":          {Type: schema.TypeBool, Default: "true"},
+		"remote_syslog":                  {Type: schema.TypeString, Default: ""},
+		"upstream_dns":                   {Type: schema.TypeString, Default: ""},
+		"use_peer_proxy":                 {Type: schema.TypeBool, Default: "false"},
+		"windows_kms_host":               {Type: schema.TypeString, Default: ""},
+	}
+)
+
+func resourceMaasConfig() *schema.Resource {
+	return &schema.Resource{
+		Description:   "Provides a resource to manage a MAAS global configuration setting.\n\n**NOTE:** MAAS config settings are singletons. Destroying this resource resets the setting to its documented default value (if it has one) instead of deleting it.",
+		CreateContext: resourceConfigCreate,
+		ReadContext:   resourceConfigRead,
+		UpdateContext: resourceConfigUpdate,
+		DeleteContext: resourceConfigDelete,
+		CustomizeDiff: resourceConfigCustomizeDiff,
+		Importer: &schema.ResourceImporter{
+			StateContext: func(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+				if _, ok := maasConfigSettings[d.Id()]; !ok {
+					return nil, fmt.Errorf("unknown MAAS config setting (%s)", d.Id())
+				}
+				if err := d.Set("key", d.Id()); err != nil {
+					return nil, err
+				}
+				return []*schema.ResourceData{d}, nil
+			},
+		},
+
+		Schema: map[string]*schema.Schema{
+			"key": {
+				Type:             schema.TypeString,
+				Required:         true,
+				ForceNew:         true,
+				ValidateDiagFunc: validation.ToDiagFunc(validation.StringInSlice(getMAASConfigSettingNames(), false)),
+				Description:      "The name of the MAAS config setting (e.g. `default_distro_series`, `ntp_servers`, `enable_http_proxy`).",
+			},
+			"value": {
+				Type:             schema.TypeString,
+				Required:         true,
+				DiffSuppressFunc: suppressMAASConfigValueDiff,
+				Description:      "The value of the MAAS config setting. Boolean settings accept `true` or `false`, and integer settings accept a number given as string.",
+			},
+		},
+	}
+}
+
+func resourceConfigCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	maasServer := m.(*ClientConfig).MAASServer
+
+	key := d.Get("key").(string)
+	value, err := formatMAASConfigValue(key, d.Get("value").(string))
+	if err != nil {
+		return diagFromErr(err)
+	}
+	if err := maasServer.Post(key, value); err != nil {
+		return diagFromErr(err)
+	}
+	d.SetId(key)
+
+	return resourceConfigRead(ctx, d, m)
+}
+
+func resourceConfigRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	maasServer := m.(*ClientConfig).MAASServer
+
+	value, err := getMAASConfigValue(maasServer, d.Id())
+	if err != nil {
+		return diagFromErr(err)
+	}
+	if err := d.Set("value", value); err != nil {
+		return diagFromErr(err)
+	}
+
+	return nil
+}
+
+func resourceConfigUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	maasServer := m.(*ClientConfig).MAASServer
+
+	value, err := formatMAASConfigValue(d.Id(), d.Get("value").(string))
+	if err != nil {
+		return diagFromErr(err)
+	}
+	if err := maasServer.Post(d.Id(), value); err != nil {
+		return diagFromErr(err)
+	}
+
+	return resourceConfigRead(ctx, d, m)
+}
+
+func resourceConfigDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	maasServer := m.(*ClientConfig).MAASServer
+
+	setting, ok := maasConfigSettings[d.Id()]
+	if !ok || setting.ComputedDefault {
+		return nil
+	}
+	if err := maasServer.Post(d.Id(), setting.Default); err != nil {
+		return diagFromErr(err)
+	}
+
+	return nil
+}
+
+func resourceConfigCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	if !d.NewValueKnown("key") || !d.NewValueKnown("value") {
+		return nil
+	}
+	_, err := formatMAASConfigValue(d.Get("key").(string), d.Get("value").(string))
+	return err
+}
+
+func suppressMAASConfigValueDiff(k, old, new string, d *schema.ResourceData) bool {
+	key := d.Get("key").(string)
+	oldValue, err := formatMAASConfigValue(key, old)
+	if err != nil {
+		return false
+	}
+	newValue, err := formatMAASConfigValue(key, new)
+	if err != nil {
+		return false
+	}
+	return oldValue == newValue
+}
+
+func getMAASConfigSettingNames() []string {
+	names := make([]string, 0, len(maasConfigSettings))
+	for name := range maasConfigSettings {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// formatMAASConfigValue validates the given value against the type of the
+// MAAS config setting, and returns the value in its canonical form.
+func formatMAASConfigValue(key string, value string) (string, error) {
+	setting, ok := maasConfigSettings[key]
+	if !ok {
+		return "", fmt.Errorf("unknown MAAS config setting (%s)", key)
+	}
+	switch setting.Type {
+	case schema.TypeBool:
+		v, err := strconv.ParseBool(value)
+		if err != nil {
+			return "", fmt.Errorf("MAAS config setting (%s) expects a boolean value, got: %s", key, value)
+		}
+		return strconv.FormatBool(v), nil
+	case schema.TypeInt:
+		v, err := strconv.Atoi(value)
+		if err != nil {
+			return "", fmt.Errorf("MAAS config setting (%s) expects an integer value, got: %s", key, value)
+		}
+		return strconv.Itoa(v), nil
+	}
+	return value, nil
+}
+
+func getMAASConfigValue(maasServer api.MAASServer, key string) (string, error) {
+	data, err := maasServer.Get(key)
+	if err != nil {
+		return "", err
+	}
+	var value interface{}
+	if err := json.Unmarshal([]byte(data), &value); err != nil {
+		return "", err
+	}
+	switch v := value.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return v, nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), nil
+	}
+	return data, nil
+}
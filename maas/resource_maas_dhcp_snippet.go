@@ -0,0 +1,214 @@
+package maas
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func resourceMaasDHCPSnippet() *schema.Resource {
+	return &schema.Resource{
+		Description:   "Provides a resource to manage MAAS DHCP snippets.",
+		CreateContext: resourceDHCPSnippetCreate,
+		ReadContext:   resourceDHCPSnippetRead,
+		UpdateContext: resourceDHCPSnippetUpdate,
+		DeleteContext: resourceDHCPSnippetDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: func(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+				dhcpSnippet, err := getDHCPSnippet(m.(*ClientConfig), d.Id())
+				if err != nil {
+					return nil, err
+				}
+				tfState := map[string]interface{}{
+					"id":   fmt.Sprintf("%v", dhcpSnippet.ID),
+					"name": dhcpSnippet.Name,
+				}
+				if err := setTerraformState(d, tfState); err != nil {
+					return nil, err
+				}
+				return []*schema.ResourceData{d}, nil
+			},
+		},
+
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The name of the DHCP snippet.",
+			},
+			"value": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The snippet of ISC DHCP configuration. MAAS checks that the DHCP configuration compiles with the snippet when it's enabled, and the validation errors are reported by the apply.",
+			},
+			"description": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "A description of the DHCP snippet.",
+			},
+			"enabled": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+				Description: "Boolean value indicating if the DHCP snippet is enabled. Defaults to `true`.",
+			},
+			"subnet": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				ConflictsWith: []string{"machine"},
+				Description:   "The identifier (ID or CIDR) of the subnet the DHCP snippet applies to. If neither `subnet` nor `machine` is set, the DHCP snippet is global.",
+			},
+			"machine": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				ConflictsWith: []string{"subnet"},
+				Description:   "The identifier (system ID, hostname, or FQDN) of the machine the DHCP snippet applies to. If neither `subnet` nor `machine` is set, the DHCP snippet is global.",
+			},
+		},
+	}
+}
+
+func resourceDHCPSnippetCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	clientConfig := m.(*ClientConfig)
+
+	params, err := getDHCPSnippetParams(clientConfig, d)
+	if err != nil {
+		return diagFromErr(err)
+	}
+	dhcpSnippet, err := clientConfig.DHCPSnippets.Create(params)
+	if err != nil {
+		return diagFromErr(err)
+	}
+	d.SetId(fmt.Sprintf("%v", dhcpSnippet.ID))
+
+	return resourceDHCPSnippetRead(ctx, d, m)
+}
+
+func resourceDHCPSnippetRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	dhcpSnippet, err := findDHCPSnippet(m.(*ClientConfig), d.Id())
+	if err != nil {
+		return diagFromErr(err)
+	}
+	if dhcpSnippet == nil {
+		log.Printf("[DEBUG] DHCP snippet (%s) was not found, removing it from state\n", d.Id())
+		d.SetId("")
+		return nil
+	}
+	subnet := ""
+	if dhcpSnippet.Subnet != nil {
+		subnet = d.Get("subnet").(string)
+		if subnet != fmt.Sprintf("%v", dhcpSnippet.Subnet.ID) && subnet != dhcpSnippet.Subnet.CIDR {
+			subnet = fmt.Sprintf("%v", dhcpSnippet.Subnet.ID)
+		}
+	}
+	machine := ""
+	if dhcpSnippet.Node != nil {
+		machine = d.Get("machine").(string)
+		if machine != dhcpSnippet.Node.SystemID && machine != dhcpSnippet.Node.Hostname {
+			machine = dhcpSnippet.Node.SystemID
+		}
+	}
+	tfState := map[string]interface{}{
+		"name":        dhcpSnippet.Name,
+		"value":       dhcpSnippet.Value,
+		"description": dhcpSnippet.Description,
+		"enabled":     dhcpSnippet.Enabled,
+		"subnet":      subnet,
+		"machine":     machine,
+	}
+	if err := setTerraformState(d, tfState); err != nil {
+		return diagFromErr(err)
+	}
+
+	return nil
+}
+
+func resourceDHCPSnippetUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	clientConfig := m.(*ClientConfig)
+
+	id, err := strconv.Atoi(d.Id())
+	if err != nil {
+		return diagFromErr(err)
+	}
+	params, err := getDHCPSnippetParams(clientConfig, d)
+	if err != nil {
+		return diagFromErr(err)
+	}
+	// MAAS refuses a DHCP snippet scoped to both a subnet and a node, so the
+	// previous scope is reset before moving the snippet between them.
+	if d.HasChanges("subnet", "machine") && !params.GlobalSnippet {
+		if _, err := clientConfig.DHCPSnippets.Update(id, &DHCPSnippetParams{Description: params.Description, Enabled: params.Enabled, GlobalSnippet: true}); err != nil {
+			return diagFromErr(err)
+		}
+	}
+	if _, err := clientConfig.DHCPSnippets.Update(id, params); err != nil {
+		return diagFromErr(err)
+	}
+
+	return resourceDHCPSnippetRead(ctx, d, m)
+}
+
+func resourceDHCPSnippetDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	id, err := strconv.Atoi(d.Id())
+	if err != nil {
+		return diagFromErr(err)
+	}
+	if err := m.(*ClientConfig).DHCPSnippets.Delete(id); err != nil {
+		return diagFromErr(err)
+	}
+
+	return nil
+}
+
+func getDHCPSnippetParams(clientConfig *ClientConfig, d *schema.ResourceData) (*DHCPSnippetParams, error) {
+	params := &DHCPSnippetParams{
+		Name:        d.Get("name").(string),
+		Value:       d.Get("value").(string),
+		Description: d.Get("description").(string),
+		Enabled:     d.Get("enabled").(bool),
+	}
+	if p, ok := d.GetOk("subnet"); ok {
+		subnet, err := getSubnet(clientConfig.Client, p.(string))
+		if err != nil {
+			return nil, err
+		}
+		params.Subnet = fmt.Sprintf("%v", subnet.ID)
+	} else if p, ok := d.GetOk("machine"); ok {
+		machine, err := getMachine(clientConfig.Client, p.(string))
+		if err != nil {
+			return nil, err
+		}
+		params.Node = machine.SystemID
+	} else {
+		params.GlobalSnippet = true
+	}
+	return params, nil
+}
+
+func findDHCPSnippet(clientConfig *ClientConfig, identifier string) (*DHCPSnippet, error) {
+	dhcpSnippets, err := clientConfig.DHCPSnippets.Get()
+	if err != nil {
+		return nil, err
+	}
+	for _, s := range dhcpSnippets {
+		if fmt.Sprintf("%v", s.ID) == identifier || s.Name == identifier {
+			return &s, nil
+		}
+	}
+	return nil, nil
+}
+
+func getDHCPSnippet(clientConfig *ClientConfig, identifier string) (*DHCPSnippet, error) {
+	dhcpSnippet, err := findDHCPSnippet(clientConfig, identifier)
+	if err != nil {
+		return nil, err
+	}
+	if dhcpSnippet == nil {
+		return nil, fmt.Errorf("DHCP snippet (%s) was not found", identifier)
+	}
+	return dhcpSnippet, nil
+}
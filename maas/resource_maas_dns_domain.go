@@ -3,6 +3,7 @@ package maas
 import (
 	"context"
 	"fmt"
+	"log"
 	"strconv"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
@@ -20,7 +21,7 @@ func resourceMaasDnsDomain() *schema.Resource {
 		DeleteContext: resourceDnsDomainDelete,
 		Importer: &schema.ResourceImporter{
 			StateContext: func(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
-				client := m.(*client.Client)
+				client := m.(*ClientConfig).Client
 				domain, err := getDomain(client, d.Id())
 				if err != nil {
 					return nil, err
@@ -48,7 +49,7 @@ func resourceMaasDnsDomain() *schema.Resource {
 			"ttl": {
 				Type:        schema.TypeInt,
 				Optional:    true,
-				Description: "The default TTL for the new DNS domain.",
+				Description: "The default TTL for the new DNS domain. If it's not set, the MAAS default TTL is used.",
 			},
 			"authoritative": {
 				Type:        schema.TypeBool,
@@ -60,18 +61,18 @@ func resourceMaasDnsDomain() *schema.Resource {
 				Type:        schema.TypeBool,
 				Optional:    true,
 				Default:     false,
-				Description: "Boolean value indicating if the new DNS domain will be set as the default in the MAAS environment. Defaults to `false`.",
+				Description: "Boolean value indicating if the new DNS domain will be set as the default in the MAAS environment. MAAS always has exactly one default domain, so setting this to `false` doesn't unset it, and another domain must be set as default instead. When another domain is set as default, this is reported as drift only if it was `true`. Defaults to `false`.",
 			},
 		},
 	}
 }
 
 func resourceDnsDomainCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	client := m.(*client.Client)
+	client := m.(*ClientConfig).Client
 
 	domain, err := client.Domains.Create(getDomainParams(d))
 	if err != nil {
-		return diag.FromErr(err)
+		return diagFromErr(err)
 	}
 	d.SetId(fmt.Sprintf("%v", domain.ID))
 
@@ -79,33 +80,48 @@ func resourceDnsDomainCreate(ctx context.Context, d *schema.ResourceData, m inte
 }
 
 func resourceDnsDomainRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	client := m.(*client.Client)
+	client := m.(*ClientConfig).Client
 
-	id, err := strconv.Atoi(d.Id())
+	domain, err := findDomain(client, d.Id())
 	if err != nil {
-		return diag.FromErr(err)
+		return diagFromErr(err)
+	}
+	if domain == nil {
+		log.Printf("[DEBUG] DNS domain (%s) was not found, removing it from state\n", d.Id())
+		d.SetId("")
+		return nil
+	}
+	tfState := map[string]interface{}{
+		"name":          domain.Name,
+		"ttl":           domain.TTL,
+		"authoritative": domain.Authoritative,
+		// Setting another domain as default unsets this one, which is drift
+		// only if this domain is configured as the default. MAAS may also
+		// report it as default when it's not configured so (e.g. before the
+		// new default domain is set during the same apply).
+		"is_default": d.Get("is_default").(bool) && domain.IsDefault,
 	}
-	if _, err := client.Domain.Get(id); err != nil {
-		return diag.FromErr(err)
+	if err := setTerraformState(d, tfState); err != nil {
+		return diagFromErr(err)
 	}
 
 	return nil
 }
 
 func resourceDnsDomainUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	client := m.(*client.Client)
+	client := m.(*ClientConfig).Client
 
 	id, err := strconv.Atoi(d.Id())
 	if err != nil {
-		return diag.FromErr(err)
+		return diagFromErr(err)
 	}
 	domain, err := client.Domain.Update(id, getDomainParams(d))
 	if err != nil {
-		return diag.FromErr(err)
+		return diagFromErr(err)
 	}
-	if d.Get("is_default").(bool) {
+	if d.Get("is_default").(bool) && !domain.IsDefault {
 		if _, err := client.Domain.SetDefault(domain.ID); err != nil {
-			return diag.FromErr(err)
+			return diagFromErr(err)
 		}
 	}
 
@@ -113,14 +129,14 @@ func resourceDnsDomainUpdate(ctx context.Context, d *schema.ResourceData, m inte
 }
 
 func resourceDnsDomainDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	client := m.(*client.Client)
+	client := m.(*ClientConfig).Client
 
 	id, err := strconv.Atoi(d.Id())
 	if err != nil {
-		return diag.FromErr(err)
+		return diagFromErr(err)
 	}
 	if err := client.Domain.Delete(id); err != nil {
-		return diag.FromErr(err)
+		return diagFromErr(err)
 	}
 
 	return nil
@@ -134,7 +150,20 @@ func getDomainParams(d *schema.ResourceData) *entity.DomainParams {
 	}
 }
 
-func getDomain(client *client.Client, identifier string) (*entity.Domain, error) {
+func getDefaultDomain(client *client.Client) (*entity.Domain, error) {
+	domains, err := client.Domains.Get()
+	if err != nil {
+		return nil, err
+	}
+	for _, d := range domains {
+		if d.IsDefault {
+			return &d, nil
+		}
+	}
+	return nil, fmt.Errorf("default domain was not found")
+}
+
+func findDomain(client *client.Client, identifier string) (*entity.Domain, error) {
 	domains, err := client.Domains.Get()
 	if err != nil {
 		return nil, err
@@ -144,5 +173,16 @@ func getDomain(client *client.Client, identifier string) (*entity.Domain, error)
 			return &d, nil
 		}
 	}
-	return nil, fmt.Errorf("domain (%s) was not found", identifier)
+	return nil, nil
+}
+
+func getDomain(client *client.Client, identifier string) (*entity.Domain, error) {
+	domain, err := findDomain(client, identifier)
+	if err != nil {
+		return nil, err
+	}
+	if domain == nil {
+		return nil, fmt.Errorf("domain (%s) was not found", identifier)
+	}
+	return domain, nil
 }
@@ -2,7 +2,12 @@ package maas
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"log"
+	"net"
+	"reflect"
+	"sort"
 	"strconv"
 	"strings"
 
@@ -26,45 +31,20 @@ func resourceMaasDnsRecord() *schema.Resource {
 		DeleteContext: resourceDnsRecordDelete,
 		Importer: &schema.ResourceImporter{
 			StateContext: func(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+				client := m.(*ClientConfig).Client
 				idParts := strings.Split(d.Id(), ":")
-				if len(idParts) != 2 || idParts[0] == "" || idParts[1] == "" {
-					return nil, fmt.Errorf("unexpected format of ID (%q), expected TYPE:IDENTIFIER", d.Id())
-				}
-				resourceType := idParts[0]
-				if _, errors := validation.StringInSlice(validDnsRecordTypes, false)(resourceType, "type"); len(errors) > 0 {
-					return nil, errors[0]
-				}
-				client := m.(*client.Client)
-				resourceIdentifier := idParts[1]
 				var tfState map[string]interface{}
-				if resourceType == "A/AAAA" {
-					dnsRecord, err := getDnsResource(client, resourceIdentifier)
-					if err != nil {
-						return nil, err
-					}
-					ips := []string{}
-					for _, ipAddress := range dnsRecord.IPAddresses {
-						ips = append(ips, ipAddress.IP.String())
-					}
-					tfState = map[string]interface{}{
-						"id":   fmt.Sprintf("%v", dnsRecord.ID),
-						"type": resourceType,
-						"data": strings.Join(ips, " "),
-						"fqdn": dnsRecord.FQDN,
-						"ttl":  dnsRecord.AddressTTL,
-					}
-				} else {
-					dnsRecord, err := getDnsResourceRecord(client, resourceIdentifier)
-					if err != nil {
-						return nil, err
-					}
-					tfState = map[string]interface{}{
-						"id":   fmt.Sprintf("%v", dnsRecord.ID),
-						"type": dnsRecord.RRType,
-						"data": dnsRecord.RRData,
-						"fqdn": dnsRecord.FQDN,
-						"ttl":  dnsRecord.TTL,
-					}
+				var err error
+				switch {
+				case len(idParts) == 1 && idParts[0] != "":
+					tfState, err = getDnsRecordTFStateByFQDN(client, idParts[0])
+				case len(idParts) == 2 && idParts[0] != "" && idParts[1] != "":
+					tfState, err = getDnsRecordTFState(client, idParts[0], idParts[1])
+				default:
+					return nil, fmt.Errorf("unexpected format of ID (%q), expected FQDN or TYPE:IDENTIFIER", d.Id())
+				}
+				if err != nil {
+					return nil, err
 				}
 				if err := setTerraformState(d, tfState); err != nil {
 					return nil, err
@@ -84,20 +64,19 @@ func resourceMaasDnsRecord() *schema.Resource {
 			"data": {
 				Type:        schema.TypeString,
 				Required:    true,
-				Description: "The data set for the new DNS record.",
+				Description: "The data set for the new DNS record. For `A/AAAA` records, this is a space separated list of IP addresses, and their order doesn't matter.",
 			},
 			"name": {
 				Type:         schema.TypeString,
 				Optional:     true,
-				RequiredWith: []string{"domain"},
 				ExactlyOneOf: []string{"name", "fqdn"},
-				Description:  "The new DNS record resource name. Used in conjunction with `domain`. It conflicts with `fqdn` argument.",
+				Description:  "The new DNS record resource name. Used in conjunction with `domain`, or the provider `default_domain` if `domain` is not set. It conflicts with `fqdn` argument.",
 			},
 			"domain": {
 				Type:         schema.TypeString,
 				Optional:     true,
 				RequiredWith: []string{"name"},
-				Description:  "The domain of the new DNS record. Used in conjunction with `name`. It conflicts with `fqdn` argument.",
+				Description:  "The domain of the new DNS record. Used in conjunction with `name`. If it's not set, the provider `default_domain` is used, or the MAAS default domain if that's not set either. It conflicts with `fqdn` argument.",
 			},
 			"fqdn": {
 				Type:         schema.TypeString,
@@ -106,28 +85,34 @@ func resourceMaasDnsRecord() *schema.Resource {
 				Description:  "The fully qualified domain name of the new DNS record. This contains the name and the domain of the new DNS record. It conflicts with `name` and `domain` arguments.",
 			},
 			"ttl": {
+				Type:             schema.TypeInt,
+				Optional:         true,
+				DiffSuppressFunc: suppressDnsRecordTTLDiff,
+				Description:      "The TTL of the new DNS record. If this is not set, the TTL of the domain is inherited, and setting it to the same value as the domain TTL doesn't cause a diff.",
+			},
+			"domain_ttl": {
 				Type:        schema.TypeInt,
-				Optional:    true,
-				Description: "The TTL of the new DNS record.",
+				Computed:    true,
+				Description: "The default TTL of the DNS record domain, used when `ttl` is not set.",
 			},
 		},
 	}
 }
 
 func resourceDnsRecordCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	client := m.(*client.Client)
+	client := m.(*ClientConfig).Client
 
 	var resourceID int
 	if d.Get("type").(string) == "A/AAAA" {
-		dnsRecord, err := client.DNSResources.Create(getDnsResourceParams(d))
+		dnsRecord, err := client.DNSResources.Create(getDnsResourceParams(d, m.(*ClientConfig)))
 		if err != nil {
-			return diag.FromErr(err)
+			return diagFromErr(err)
 		}
 		resourceID = dnsRecord.ID
 	} else {
-		dnsRecord, err := client.DNSResourceRecords.Create(getDnsResourceRecordParams(d))
+		dnsRecord, err := client.DNSResourceRecords.Create(getDnsResourceRecordParams(d, m.(*ClientConfig)))
 		if err != nil {
-			return diag.FromErr(err)
+			return diagFromErr(err)
 		}
 		resourceID = dnsRecord.ID
 	}
@@ -137,39 +122,67 @@ func resourceDnsRecordCreate(ctx context.Context, d *schema.ResourceData, m inte
 }
 
 func resourceDnsRecordRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	client := m.(*client.Client)
+	client := m.(*ClientConfig).Client
 
-	id, err := strconv.Atoi(d.Id())
-	if err != nil {
-		return diag.FromErr(err)
-	}
+	var data, fqdn string
+	var ttl int
 	if d.Get("type").(string) == "A/AAAA" {
-		if _, err := client.DNSResource.Get(id); err != nil {
-			return diag.FromErr(err)
+		dnsResource, err := findDnsResource(client, d.Id())
+		if err != nil {
+			return diagFromErr(err)
 		}
+		if dnsResource == nil {
+			log.Printf("[DEBUG] DNS resource (%s) was not found, removing it from state\n", d.Id())
+			d.SetId("")
+			return nil
+		}
+		data = d.Get("data").(string)
+		if ips := getDnsResourceIPAddresses(dnsResource); !isSameIPAddressList(data, ips) {
+			data = ips
+		}
+		fqdn, ttl = dnsResource.FQDN, dnsResource.AddressTTL
 	} else {
-		if _, err := client.DNSResourceRecord.Get(id); err != nil {
-			return diag.FromErr(err)
+		dnsResourceRecord, err := findDnsResourceRecord(client, d.Id())
+		if err != nil {
+			return diagFromErr(err)
 		}
+		if dnsResourceRecord == nil {
+			log.Printf("[DEBUG] DNS resource record (%s) was not found, removing it from state\n", d.Id())
+			d.SetId("")
+			return nil
+		}
+		data, fqdn, ttl = dnsResourceRecord.RRData, dnsResourceRecord.FQDN, dnsResourceRecord.TTL
+	}
+	domainTTL, err := getDnsRecordDomainTTL(m.(*ClientConfig), fqdn)
+	if err != nil {
+		return diagFromErr(err)
+	}
+	tfState := map[string]interface{}{
+		"data":       data,
+		"ttl":        ttl,
+		"domain_ttl": domainTTL,
+	}
+	if err := setTerraformState(d, tfState); err != nil {
+		return diagFromErr(err)
 	}
 
 	return nil
 }
 
 func resourceDnsRecordUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	client := m.(*client.Client)
+	client := m.(*ClientConfig).Client
 
 	id, err := strconv.Atoi(d.Id())
 	if err != nil {
-		return diag.FromErr(err)
+		return diagFromErr(err)
 	}
 	if d.Get("type").(string) == "A/AAAA" {
-		if _, err := client.DNSResource.Update(id, getDnsResourceParams(d)); err != nil {
-			return diag.FromErr(err)
+		if _, err := client.DNSResource.Update(id, getDnsResourceParams(d, m.(*ClientConfig))); err != nil {
+			return diagFromErr(err)
 		}
 	} else {
-		if _, err := client.DNSResourceRecord.Update(id, getDnsResourceRecordParams(d)); err != nil {
-			return diag.FromErr(err)
+		if _, err := client.DNSResourceRecord.Update(id, getDnsResourceRecordParams(d, m.(*ClientConfig))); err != nil {
+			return diagFromErr(err)
 		}
 	}
 
@@ -177,56 +190,216 @@ func resourceDnsRecordUpdate(ctx context.Context, d *schema.ResourceData, m inte
 }
 
 func resourceDnsRecordDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	client := m.(*client.Client)
+	client := m.(*ClientConfig).Client
 
 	id, err := strconv.Atoi(d.Id())
 	if err != nil {
-		return diag.FromErr(err)
+		return diagFromErr(err)
 	}
 	if d.Get("type").(string) == "A/AAAA" {
 		dnsResource, err := client.DNSResource.Get(id)
 		if err != nil {
-			return diag.FromErr(err)
+			return diagFromErr(err)
 		}
 		if err := client.DNSResource.Delete(id); err != nil {
-			return diag.FromErr(err)
+			return diagFromErr(err)
 		}
 		for _, ipAddress := range dnsResource.IPAddresses {
 			if err := client.IPAddresses.Release(&entity.IPAddressesParams{IP: ipAddress.IP.String()}); err != nil {
-				return diag.FromErr(err)
+				return diagFromErr(err)
 			}
 		}
 	} else {
 		if err := client.DNSResourceRecord.Delete(id); err != nil {
-			return diag.FromErr(err)
+			return diagFromErr(err)
 		}
 	}
 
 	return nil
 }
 
-func getDnsResourceParams(d *schema.ResourceData) *entity.DNSResourceParams {
+// suppressDnsRecordTTLDiff suppresses the TTL diff when the effective TTL is
+// the same. MAAS returns a null TTL when the record inherits the domain TTL,
+// so an unset TTL is the same as a TTL equal to the domain one.
+func suppressDnsRecordTTLDiff(k, old, new string, d *schema.ResourceData) bool {
+	return isSameEffectiveTTL(old, new, d.Get("domain_ttl").(int))
+}
+
+// isSameEffectiveTTL checks if the given TTLs are the same once the unset
+// ones inherit the domain TTL. The domain TTL is unknown (0) until the record
+// is read for the first time.
+func isSameEffectiveTTL(old string, new string, domainTTL int) bool {
+	if old == new {
+		return true
+	}
+	if domainTTL == 0 {
+		return false
+	}
+	effectiveTTL := func(ttl string) int {
+		if v, err := strconv.Atoi(ttl); err == nil && v != 0 {
+			return v
+		}
+		return domainTTL
+	}
+	return effectiveTTL(old) == effectiveTTL(new)
+}
+
+// getDnsRecordDomainTTL returns the TTL inherited by the DNS records with the
+// given FQDN. It's the TTL of their domain, or the MAAS default DNS TTL if the
+// domain doesn't have one.
+func getDnsRecordDomainTTL(clientConfig *ClientConfig, fqdn string) (int, error) {
+	domains, err := clientConfig.Client.Domains.Get()
+	if err != nil {
+		return 0, err
+	}
+	var domain *entity.Domain
+	for i, dom := range domains {
+		if (fqdn == dom.Name || strings.HasSuffix(fqdn, "."+dom.Name)) && (domain == nil || len(dom.Name) > len(domain.Name)) {
+			domain = &domains[i]
+		}
+	}
+	if domain != nil && domain.TTL != 0 {
+		return domain.TTL, nil
+	}
+	value, err := clientConfig.MAASServer.Get("default_dns_ttl")
+	if err != nil {
+		return 0, err
+	}
+	var ttl int
+	if err := json.Unmarshal([]byte(value), &ttl); err != nil {
+		return 0, err
+	}
+	return ttl, nil
+}
+
+// getDnsRecordDomain returns the domain of the DNS record given by its name,
+// or the provider default domain. The DNS records given by their FQDN have no
+// domain.
+func getDnsRecordDomain(d *schema.ResourceData, clientConfig *ClientConfig) string {
+	if d.Get("name").(string) == "" {
+		return ""
+	}
+	return getStringOrDefault(d, "domain", clientConfig.DefaultDomain)
+}
+
+func getDnsResourceParams(d *schema.ResourceData, clientConfig *ClientConfig) *entity.DNSResourceParams {
 	return &entity.DNSResourceParams{
 		IPAddresses: d.Get("data").(string),
 		Name:        d.Get("name").(string),
-		Domain:      d.Get("domain").(string),
+		Domain:      getDnsRecordDomain(d, clientConfig),
 		FQDN:        d.Get("fqdn").(string),
 		AddressTTL:  d.Get("ttl").(int),
 	}
 }
 
-func getDnsResourceRecordParams(d *schema.ResourceData) *entity.DNSResourceRecordParams {
+func getDnsResourceRecordParams(d *schema.ResourceData, clientConfig *ClientConfig) *entity.DNSResourceRecordParams {
 	return &entity.DNSResourceRecordParams{
 		RRType: d.Get("type").(string),
 		RRData: d.Get("data").(string),
 		Name:   d.Get("name").(string),
-		Domain: d.Get("domain").(string),
+		Domain: getDnsRecordDomain(d, clientConfig),
 		FQDN:   d.Get("fqdn").(string),
 		TTL:    d.Get("ttl").(int),
 	}
 }
 
-func getDnsResourceRecord(client *client.Client, identifier string) (*entity.DNSResourceRecord, error) {
+func getDnsRecordTFState(client *client.Client, resourceType string, identifier string) (map[string]interface{}, error) {
+	if _, errors := validation.StringInSlice(validDnsRecordTypes, false)(resourceType, "type"); len(errors) > 0 {
+		return nil, errors[0]
+	}
+	if resourceType == "A/AAAA" {
+		dnsResource, err := getDnsResource(client, identifier)
+		if err != nil {
+			return nil, err
+		}
+		return getDnsResourceTFState(dnsResource), nil
+	}
+	dnsResourceRecord, err := getDnsResourceRecord(client, identifier)
+	if err != nil {
+		return nil, err
+	}
+	return getDnsResourceRecordTFState(dnsResourceRecord), nil
+}
+
+// getDnsRecordTFStateByFQDN finds the DNS record to import by its FQDN. An
+// address record takes precedence, and a data record is used only if it's
+// the only one with the given FQDN.
+func getDnsRecordTFStateByFQDN(client *client.Client, fqdn string) (map[string]interface{}, error) {
+	dnsResources, err := client.DNSResources.Get()
+	if err != nil {
+		return nil, err
+	}
+	for _, d := range dnsResources {
+		if d.FQDN == fqdn && len(d.IPAddresses) > 0 {
+			return getDnsResourceTFState(&d), nil
+		}
+	}
+	dnsResourceRecords, err := client.DNSResourceRecords.Get()
+	if err != nil {
+		return nil, err
+	}
+	var dnsResourceRecord *entity.DNSResourceRecord
+	for i, d := range dnsResourceRecords {
+		if d.FQDN != fqdn {
+			continue
+		}
+		if dnsResourceRecord != nil {
+			return nil, fmt.Errorf("multiple DNS records with FQDN (%s) were found, use TYPE:ID to import one of them", fqdn)
+		}
+		dnsResourceRecord = &dnsResourceRecords[i]
+	}
+	if dnsResourceRecord == nil {
+		return nil, fmt.Errorf("DNS record (%s) was not found", fqdn)
+	}
+	return getDnsResourceRecordTFState(dnsResourceRecord), nil
+}
+
+func getDnsResourceTFState(dnsResource *entity.DNSResource) map[string]interface{} {
+	return map[string]interface{}{
+		"id":   fmt.Sprintf("%v", dnsResource.ID),
+		"type": "A/AAAA",
+		"data": getDnsResourceIPAddresses(dnsResource),
+		"fqdn": dnsResource.FQDN,
+		"ttl":  dnsResource.AddressTTL,
+	}
+}
+
+func getDnsResourceRecordTFState(dnsResourceRecord *entity.DNSResourceRecord) map[string]interface{} {
+	return map[string]interface{}{
+		"id":   fmt.Sprintf("%v", dnsResourceRecord.ID),
+		"type": dnsResourceRecord.RRType,
+		"data": dnsResourceRecord.RRData,
+		"fqdn": dnsResourceRecord.FQDN,
+		"ttl":  dnsResourceRecord.TTL,
+	}
+}
+
+func getDnsResourceIPAddresses(dnsResource *entity.DNSResource) string {
+	ips := []string{}
+	for _, ipAddress := range dnsResource.IPAddresses {
+		ips = append(ips, ipAddress.IP.String())
+	}
+	return strings.Join(ips, " ")
+}
+
+// isSameIPAddressList checks if the given space separated lists have the same
+// IP addresses. MAAS doesn't keep the order of the A/AAAA record addresses.
+func isSameIPAddressList(a string, b string) bool {
+	normalize := func(list string) []string {
+		ips := []string{}
+		for _, ip := range strings.Fields(list) {
+			if parsed := net.ParseIP(ip); parsed != nil {
+				ip = parsed.String()
+			}
+			ips = append(ips, ip)
+		}
+		sort.Strings(ips)
+		return ips
+	}
+	return reflect.DeepEqual(normalize(a), normalize(b))
+}
+
+func findDnsResourceRecord(client *client.Client, identifier string) (*entity.DNSResourceRecord, error) {
 	dnsResourceRecords, err := client.DNSResourceRecords.Get()
 	if err != nil {
 		return nil, err
@@ -236,10 +409,21 @@ func getDnsResourceRecord(client *client.Client, identifier string) (*entity.DNS
 			return &d, nil
 		}
 	}
-	return nil, fmt.Errorf("DNS resource record (%s) was not found", identifier)
+	return nil, nil
 }
 
-func getDnsResource(client *client.Client, identifier string) (*entity.DNSResource, error) {
+func getDnsResourceRecord(client *client.Client, identifier string) (*entity.DNSResourceRecord, error) {
+	dnsResourceRecord, err := findDnsResourceRecord(client, identifier)
+	if err != nil {
+		return nil, err
+	}
+	if dnsResourceRecord == nil {
+		return nil, fmt.Errorf("DNS resource record (%s) was not found", identifier)
+	}
+	return dnsResourceRecord, nil
+}
+
+func findDnsResource(client *client.Client, identifier string) (*entity.DNSResource, error) {
 	dnsResources, err := client.DNSResources.Get()
 	if err != nil {
 		return nil, err
@@ -249,5 +433,16 @@ func getDnsResource(client *client.Client, identifier string) (*entity.DNSResour
 			return &d, nil
 		}
 	}
-	return nil, fmt.Errorf("DNS resource (%s) was not found", identifier)
+	return nil, nil
+}
+
+func getDnsResource(client *client.Client, identifier string) (*entity.DNSResource, error) {
+	dnsResource, err := findDnsResource(client, identifier)
+	if err != nil {
+		return nil, err
+	}
+	if dnsResource == nil {
+		return nil, fmt.Errorf("DNS resource (%s) was not found", identifier)
+	}
+	return dnsResource, nil
 }
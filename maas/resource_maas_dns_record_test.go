@@ -0,0 +1,108 @@
+package maas
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsSameEffectiveTTL(t *testing.T) {
+	testCases := []struct {
+		name      string
+		old       string
+		new       string
+		domainTTL int
+		out       bool
+	}{
+		{
+			name:      "inherited TTL and explicit TTL equal to the domain TTL",
+			old:       "0",
+			new:       "3600",
+			domainTTL: 3600,
+			out:       true,
+		},
+		{
+			name:      "explicit TTL equal to the domain TTL and unset TTL",
+			old:       "3600",
+			new:       "",
+			domainTTL: 3600,
+			out:       true,
+		},
+		{
+			name:      "inherited TTL and explicit TTL different from the domain TTL",
+			old:       "0",
+			new:       "600",
+			domainTTL: 3600,
+			out:       false,
+		},
+		{
+			name:      "different explicit TTLs",
+			old:       "300",
+			new:       "600",
+			domainTTL: 3600,
+			out:       false,
+		},
+		{
+			name:      "unknown domain TTL",
+			old:       "0",
+			new:       "3600",
+			domainTTL: 0,
+			out:       false,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			out := isSameEffectiveTTL(testCase.old, testCase.new, testCase.domainTTL)
+			assert.Equal(t, testCase.out, out, fmt.Sprintf("isSameEffectiveTTL(%s, %s, %d) => %t, want %t", testCase.old, testCase.new, testCase.domainTTL, out, testCase.out))
+		})
+	}
+}
+
+func TestIsSameIPAddressList(t *testing.T) {
+	testCases := []struct {
+		name string
+		a    string
+		b    string
+		out  bool
+	}{
+		{
+			name: "same order",
+			a:    "10.0.0.1 10.0.0.2",
+			b:    "10.0.0.1 10.0.0.2",
+			out:  true,
+		},
+		{
+			name: "different order",
+			a:    "10.0.0.2 10.0.0.1",
+			b:    "10.0.0.1 10.0.0.2",
+			out:  true,
+		},
+		{
+			name: "equivalent IPv6 addresses",
+			a:    "2001:db8:0:0::1",
+			b:    "2001:db8::1",
+			out:  true,
+		},
+		{
+			name: "different addresses",
+			a:    "10.0.0.1 10.0.0.2",
+			b:    "10.0.0.1 10.0.0.3",
+			out:  false,
+		},
+		{
+			name: "missing address",
+			a:    "10.0.0.1 10.0.0.2",
+			b:    "10.0.0.1",
+			out:  false,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			out := isSameIPAddressList(testCase.a, testCase.b)
+			assert.Equal(t, testCase.out, out, fmt.Sprintf("isSameIPAddressList(%s, %s) => %t, want %t", testCase.a, testCase.b, out, testCase.out))
+		})
+	}
+}
@@ -0,0 +1,306 @@
+package maas
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/maas/gomaasclient/client"
+	"github.com/maas/gomaasclient/entity"
+)
+
+// domainDnsRecord is a DNS record found in a MAAS domain. The `A/AAAA`
+// records are MAAS DNS resources, and the other ones are DNS resource records.
+type domainDnsRecord struct {
+	ID         int
+	TTL        int
+	IsResource bool
+}
+
+// domainDnsRecordChange is a DNS record of the configuration, along with its
+// matching record found in the MAAS domain, if any.
+type domainDnsRecordChange struct {
+	record map[string]interface{}
+	found  *domainDnsRecord
+}
+
+func resourceMaasDnsRecords() *schema.Resource {
+	return &schema.Resource{
+		Description:   "Provides a resource to manage a set of MAAS DNS records of a domain in batch. Only the records which changed are created, updated, or deleted.\n\n**NOTE:** The records are identified by their name, type and data. The records of the domain which are not managed by this resource are left unchanged.",
+		CreateContext: resourceDnsRecordsCreate,
+		ReadContext:   resourceDnsRecordsRead,
+		UpdateContext: resourceDnsRecordsUpdate,
+		DeleteContext: resourceDnsRecordsDelete,
+
+		Schema: map[string]*schema.Schema{
+			"domain": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The name of the domain of the DNS records.",
+			},
+			"records": {
+				Type:        schema.TypeSet,
+				Required:    true,
+				Description: "The set of DNS records. Parameters defined below.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": {
+							Type:        schema.TypeString,
+							Required:    true,
+							Description: "The DNS record name, relative to the domain. Use `@` for the domain itself.",
+						},
+						"type": {
+							Type:             schema.TypeString,
+							Required:         true,
+							ValidateDiagFunc: validation.ToDiagFunc(validation.StringInSlice(validDnsRecordTypes, false)),
+							Description:      "The DNS record type. Valid options are: `A/AAAA`, `CNAME`, `MX`, `NS`, `SRV`, `SSHFP`, `TXT`.",
+						},
+						"data": {
+							Type:        schema.TypeString,
+							Required:    true,
+							Description: "The DNS record data. For the `A/AAAA` records, this is a space separated list of IP addresses.",
+						},
+						"ttl": {
+							Type:        schema.TypeInt,
+							Optional:    true,
+							Description: "The TTL of the DNS record. If this is not set, the domain TTL is used.",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func resourceDnsRecordsCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*ClientConfig).Client
+
+	domain, err := getDomain(client, d.Get("domain").(string))
+	if err != nil {
+		return diagFromErr(err)
+	}
+	d.SetId(domain.Name)
+
+	return resourceDnsRecordsUpdate(ctx, d, m)
+}
+
+func resourceDnsRecordsRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*ClientConfig).Client
+
+	existing, err := getDomainDnsRecords(client, d.Id())
+	if err != nil {
+		return diagFromErr(err)
+	}
+	records := []map[string]interface{}{}
+	for _, r := range d.Get("records").(*schema.Set).List() {
+		record := r.(map[string]interface{})
+		found, ok := existing[getDnsRecordKey(record)]
+		if !ok {
+			continue
+		}
+		if record["ttl"].(int) != 0 {
+			record["ttl"] = found.TTL
+		}
+		records = append(records, record)
+	}
+	if err := d.Set("records", records); err != nil {
+		return diagFromErr(err)
+	}
+
+	return nil
+}
+
+func resourceDnsRecordsUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*ClientConfig).Client
+
+	existing, err := getDomainDnsRecords(client, d.Id())
+	if err != nil {
+		return diagFromErr(err)
+	}
+	oldRecords, newRecords := d.GetChange("records")
+	deleted, updated, created := getDomainDnsRecordsChanges(oldRecords.(*schema.Set).List(), newRecords.(*schema.Set).List(), existing)
+	// The removed records are deleted first, so a changed `A/AAAA` record
+	// doesn't end up as a second DNS resource with the same name.
+	for _, c := range deleted {
+		if err := deleteDomainDnsRecord(client, c.found); err != nil {
+			return diagFromErr(err)
+		}
+	}
+	for _, c := range updated {
+		if err := updateDomainDnsRecordTTL(client, d.Id(), c.record, c.found); err != nil {
+			return diagFromErr(err)
+		}
+	}
+	for _, c := range created {
+		if err := createDomainDnsRecord(client, d.Id(), c.record); err != nil {
+			return diagFromErr(err)
+		}
+	}
+
+	return resourceDnsRecordsRead(ctx, d, m)
+}
+
+func resourceDnsRecordsDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*ClientConfig).Client
+
+	existing, err := getDomainDnsRecords(client, d.Id())
+	if err != nil {
+		return diagFromErr(err)
+	}
+	for _, r := range d.Get("records").(*schema.Set).List() {
+		if found, ok := existing[getDnsRecordKey(r.(map[string]interface{}))]; ok {
+			if err := deleteDomainDnsRecord(client, found); err != nil {
+				return diagFromErr(err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// getDomainDnsRecordsChanges compares the old and new configured DNS records
+// with the existing ones, and returns the records to be deleted, the records
+// whose TTL has to be updated, and the records to be created.
+func getDomainDnsRecordsChanges(oldRecords []interface{}, newRecords []interface{}, existing map[string]*domainDnsRecord) (deleted, updated, created []*domainDnsRecordChange) {
+	wanted := map[string]bool{}
+	for _, r := range newRecords {
+		record := r.(map[string]interface{})
+		key := getDnsRecordKey(record)
+		wanted[key] = true
+		found, ok := existing[key]
+		if !ok {
+			created = append(created, &domainDnsRecordChange{record: record})
+			continue
+		}
+		if ttl := record["ttl"].(int); ttl != 0 && ttl != found.TTL {
+			updated = append(updated, &domainDnsRecordChange{record: record, found: found})
+		}
+	}
+	for _, r := range oldRecords {
+		record := r.(map[string]interface{})
+		key := getDnsRecordKey(record)
+		if found, ok := existing[key]; ok && !wanted[key] {
+			deleted = append(deleted, &domainDnsRecordChange{record: record, found: found})
+		}
+	}
+	return
+}
+
+// getDnsRecordKey returns the key identifying a DNS record by its name, type
+// and data. The IP addresses of the `A/AAAA` records are sorted, so their
+// order doesn't matter.
+func getDnsRecordKey(record map[string]interface{}) string {
+	data := record["data"].(string)
+	if record["type"].(string) == "A/AAAA" {
+		ips := strings.Fields(data)
+		sort.Strings(ips)
+		data = strings.Join(ips, " ")
+	}
+	return fmt.Sprintf("%s %s %s", record["name"].(string), record["type"].(string), data)
+}
+
+// getDomainDnsRecords returns the DNS records of the given domain, indexed by
+// their key. Only two API requests are made, regardless of the records count.
+func getDomainDnsRecords(client *client.Client, domain string) (map[string]*domainDnsRecord, error) {
+	records := map[string]*domainDnsRecord{}
+	dnsResourceRecords, err := client.DNSResourceRecords.Get()
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range dnsResourceRecords {
+		name, ok := getDnsRecordName(r.FQDN, domain)
+		if !ok {
+			continue
+		}
+		key := getDnsRecordKey(map[string]interface{}{"name": name, "type": r.RRType, "data": r.RRData})
+		records[key] = &domainDnsRecord{ID: r.ID, TTL: r.TTL}
+	}
+	dnsResources, err := client.DNSResources.Get()
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range dnsResources {
+		name, ok := getDnsRecordName(r.FQDN, domain)
+		if !ok || len(r.IPAddresses) == 0 {
+			continue
+		}
+		ips := []string{}
+		for _, ipAddress := range r.IPAddresses {
+			ips = append(ips, ipAddress.IP.String())
+		}
+		key := getDnsRecordKey(map[string]interface{}{"name": name, "type": "A/AAAA", "data": strings.Join(ips, " ")})
+		records[key] = &domainDnsRecord{ID: r.ID, TTL: r.AddressTTL, IsResource: true}
+	}
+	return records, nil
+}
+
+func getDnsRecordName(fqdn string, domain string) (string, bool) {
+	if fqdn == domain {
+		return "@", true
+	}
+	if !strings.HasSuffix(fqdn, "."+domain) {
+		return "", false
+	}
+	return strings.TrimSuffix(fqdn, "."+domain), true
+}
+
+func createDomainDnsRecord(client *client.Client, domain string, record map[string]interface{}) error {
+	if record["type"].(string) == "A/AAAA" {
+		_, err := client.DNSResources.Create(getDomainDnsResourceParams(domain, record))
+		return err
+	}
+	_, err := client.DNSResourceRecords.Create(getDomainDnsResourceRecordParams(domain, record))
+	return err
+}
+
+func updateDomainDnsRecordTTL(client *client.Client, domain string, record map[string]interface{}, found *domainDnsRecord) error {
+	if found.IsResource {
+		_, err := client.DNSResource.Update(found.ID, getDomainDnsResourceParams(domain, record))
+		return err
+	}
+	_, err := client.DNSResourceRecord.Update(found.ID, getDomainDnsResourceRecordParams(domain, record))
+	return err
+}
+
+func deleteDomainDnsRecord(client *client.Client, found *domainDnsRecord) error {
+	if !found.IsResource {
+		return client.DNSResourceRecord.Delete(found.ID)
+	}
+	dnsResource, err := client.DNSResource.Get(found.ID)
+	if err != nil {
+		return err
+	}
+	if err := client.DNSResource.Delete(found.ID); err != nil {
+		return err
+	}
+	for _, ipAddress := range dnsResource.IPAddresses {
+		if err := client.IPAddresses.Release(&entity.IPAddressesParams{IP: ipAddress.IP.String()}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func getDomainDnsResourceParams(domain string, record map[string]interface{}) *entity.DNSResourceParams {
+	return &entity.DNSResourceParams{
+		IPAddresses: record["data"].(string),
+		Name:        record["name"].(string),
+		Domain:      domain,
+		AddressTTL:  record["ttl"].(int),
+	}
+}
+
+func getDomainDnsResourceRecordParams(domain string, record map[string]interface{}) *entity.DNSResourceRecordParams {
+	return &entity.DNSResourceRecordParams{
+		RRType: record["type"].(string),
+		RRData: record["data"].(string),
+		Name:   record["name"].(string),
+		Domain: domain,
+		TTL:    record["ttl"].(int),
+	}
+}
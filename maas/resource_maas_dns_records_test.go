@@ -0,0 +1,64 @@
+package maas
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetDomainDnsRecordsChanges(t *testing.T) {
+	record := func(name string, recordType string, data string, ttl int) map[string]interface{} {
+		return map[string]interface{}{"name": name, "type": recordType, "data": data, "ttl": ttl}
+	}
+	existing := map[string]*domainDnsRecord{
+		"www A/AAAA 10.0.0.1 10.0.0.2": {ID: 1, TTL: 3600, IsResource: true},
+		"mail CNAME www":               {ID: 2, TTL: 3600},
+	}
+	testCases := []struct {
+		name       string
+		oldRecords []interface{}
+		newRecords []interface{}
+		deleted    []*domainDnsRecordChange
+		updated    []*domainDnsRecordChange
+		created    []*domainDnsRecordChange
+	}{
+		{
+			name:       "changed A/AAAA record data",
+			oldRecords: []interface{}{record("www", "A/AAAA", "10.0.0.2 10.0.0.1", 0)},
+			newRecords: []interface{}{record("www", "A/AAAA", "10.0.0.3", 0)},
+			deleted:    []*domainDnsRecordChange{{record: record("www", "A/AAAA", "10.0.0.2 10.0.0.1", 0), found: existing["www A/AAAA 10.0.0.1 10.0.0.2"]}},
+			created:    []*domainDnsRecordChange{{record: record("www", "A/AAAA", "10.0.0.3", 0)}},
+		},
+		{
+			name:       "reordered A/AAAA record data",
+			oldRecords: []interface{}{record("www", "A/AAAA", "10.0.0.1 10.0.0.2", 0)},
+			newRecords: []interface{}{record("www", "A/AAAA", "10.0.0.2 10.0.0.1", 0)},
+		},
+		{
+			name:       "changed TTL",
+			oldRecords: []interface{}{record("mail", "CNAME", "www", 3600)},
+			newRecords: []interface{}{record("mail", "CNAME", "www", 600)},
+			updated:    []*domainDnsRecordChange{{record: record("mail", "CNAME", "www", 600), found: existing["mail CNAME www"]}},
+		},
+		{
+			name:       "removed record",
+			oldRecords: []interface{}{record("mail", "CNAME", "www", 0)},
+			newRecords: []interface{}{},
+			deleted:    []*domainDnsRecordChange{{record: record("mail", "CNAME", "www", 0), found: existing["mail CNAME www"]}},
+		},
+		{
+			name:       "removed record already deleted",
+			oldRecords: []interface{}{record("ftp", "CNAME", "www", 0)},
+			newRecords: []interface{}{},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			deleted, updated, created := getDomainDnsRecordsChanges(testCase.oldRecords, testCase.newRecords, existing)
+			assert.Equal(t, testCase.deleted, deleted, fmt.Sprintf("deleted records of %v -> %v", testCase.oldRecords, testCase.newRecords))
+			assert.Equal(t, testCase.updated, updated, fmt.Sprintf("updated records of %v -> %v", testCase.oldRecords, testCase.newRecords))
+			assert.Equal(t, testCase.created, created, fmt.Sprintf("created records of %v -> %v", testCase.oldRecords, testCase.newRecords))
+		})
+	}
+}
@@ -3,6 +3,7 @@ package maas
 import (
 	"context"
 	"fmt"
+	"log"
 	"strconv"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
@@ -20,7 +21,7 @@ func resourceMaasFabric() *schema.Resource {
 		DeleteContext: resourceFabricDelete,
 		Importer: &schema.ResourceImporter{
 			StateContext: func(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
-				client := m.(*client.Client)
+				client := m.(*ClientConfig).Client
 				fabric, err := getFabric(client, d.Id())
 				if err != nil {
 					return nil, err
@@ -39,16 +40,22 @@ func resourceMaasFabric() *schema.Resource {
 				Required:    true,
 				Description: "The fabric name.",
 			},
+			"class_type": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				Description: "The fabric class type (e.g. `10g`), used to describe the kind of network. This argument is computed if it's not set.",
+			},
 		},
 	}
 }
 
 func resourceFabricCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	client := m.(*client.Client)
+	client := m.(*ClientConfig).Client
 
 	fabric, err := client.Fabrics.Create(getFabricParams(d))
 	if err != nil {
-		return diag.FromErr(err)
+		return diagFromErr(err)
 	}
 	d.SetId(fmt.Sprintf("%v", fabric.ID))
 
@@ -56,42 +63,51 @@ func resourceFabricCreate(ctx context.Context, d *schema.ResourceData, m interfa
 }
 
 func resourceFabricRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	client := m.(*client.Client)
+	client := m.(*ClientConfig).Client
 
-	id, err := strconv.Atoi(d.Id())
+	fabric, err := findFabric(client, d.Id())
 	if err != nil {
-		return diag.FromErr(err)
+		return diagFromErr(err)
+	}
+	if fabric == nil {
+		log.Printf("[DEBUG] Fabric (%s) was not found, removing it from state\n", d.Id())
+		d.SetId("")
+		return nil
+	}
+	tfState := map[string]interface{}{
+		"name":       fabric.Name,
+		"class_type": fabric.ClassType,
 	}
-	if _, err := client.Fabric.Get(id); err != nil {
-		return diag.FromErr(err)
+	if err := setTerraformState(d, tfState); err != nil {
+		return diagFromErr(err)
 	}
 
 	return nil
 }
 
 func resourceFabricUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	client := m.(*client.Client)
+	client := m.(*ClientConfig).Client
 
 	id, err := strconv.Atoi(d.Id())
 	if err != nil {
-		return diag.FromErr(err)
+		return diagFromErr(err)
 	}
 	if _, err := client.Fabric.Update(id, getFabricParams(d)); err != nil {
-		return diag.FromErr(err)
+		return diagFromErr(err)
 	}
 
 	return resourceFabricRead(ctx, d, m)
 }
 
 func resourceFabricDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	client := m.(*client.Client)
+	client := m.(*ClientConfig).Client
 
 	id, err := strconv.Atoi(d.Id())
 	if err != nil {
-		return diag.FromErr(err)
+		return diagFromErr(err)
 	}
 	if err := client.Fabric.Delete(id); err != nil {
-		return diag.FromErr(err)
+		return diagFromErr(err)
 	}
 
 	return nil
@@ -99,7 +115,8 @@ func resourceFabricDelete(ctx context.Context, d *schema.ResourceData, m interfa
 
 func getFabricParams(d *schema.ResourceData) *entity.FabricParams {
 	return &entity.FabricParams{
-		Name: d.Get("name").(string),
+		Name:      d.Get("name").(string),
+		ClassType: d.Get("class_type").(string),
 	}
 }
 
@@ -0,0 +1,307 @@
+package maas
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/maas/gomaasclient/client"
+	"github.com/maas/gomaasclient/entity"
+)
+
+func resourceMaasFilesystem() *schema.Resource {
+	return &schema.Resource{
+		Description:   "Provides a resource to format and mount an existing block device or partition of a MAAS machine. The file system is unmounted and removed when the resource is destroyed.",
+		CreateContext: resourceFilesystemCreate,
+		ReadContext:   resourceFilesystemRead,
+		UpdateContext: resourceFilesystemUpdate,
+		DeleteContext: resourceFilesystemDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: func(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+				idParts := strings.Split(d.Id(), ":")
+				if len(idParts) < 2 || len(idParts) > 3 || idParts[0] == "" || idParts[1] == "" || (len(idParts) == 3 && idParts[2] == "") {
+					return nil, fmt.Errorf("unexpected format of ID (%q), expected MACHINE:BLOCK_DEVICE or MACHINE:BLOCK_DEVICE:PARTITION, where MACHINE is a system ID, hostname, FQDN, or MAC address, BLOCK_DEVICE is an ID or name, and PARTITION is an ID or path", d.Id())
+				}
+				client := m.(*ClientConfig).Client
+				machine, err := getMachine(client, idParts[0])
+				if err != nil {
+					return nil, err
+				}
+				tfState := map[string]interface{}{
+					"machine":      machine.SystemID,
+					"block_device": idParts[1],
+				}
+				if len(idParts) == 3 {
+					tfState["partition"] = idParts[2]
+				}
+				if err := setTerraformState(d, tfState); err != nil {
+					return nil, err
+				}
+				device, err := getFilesystemDevice(client, d, machine.SystemID)
+				if err != nil {
+					return nil, err
+				}
+				if device.FileSystem.FSType == "" {
+					return nil, fmt.Errorf("%s is not formatted", device)
+				}
+				d.SetId(device.ID())
+				return []*schema.ResourceData{d}, nil
+			},
+		},
+
+		Schema: map[string]*schema.Schema{
+			"machine": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The machine identifier (system ID, hostname, FQDN, or MAC address) that owns the formatted device.",
+			},
+			"block_device": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The identifier (ID, name, ID path, or path) of the block device to format, or of the block device of the partition to format.",
+			},
+			"partition": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Description: "The identifier (ID or path) of the partition to format. If this is not set, the block device is formatted.",
+			},
+			"fs_type": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The file system type (e.g. `ext4`, `xfs`, `swap`, or `fat32`). A device already formatted with another file system type is reformatted.",
+			},
+			"mount_point": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The mount point used (e.g. `/srv`, or `none` for `swap`). If this is not set, the file system is not mounted.",
+			},
+			"mount_options": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				RequiredWith: []string{"mount_point"},
+				Description:  "The options used for the file system mount.",
+			},
+			"uuid": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The file system UUID.",
+			},
+		},
+	}
+}
+
+func resourceFilesystemCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*ClientConfig).Client
+
+	machine, err := getMachine(client, d.Get("machine").(string))
+	if err != nil {
+		return diagFromErr(err)
+	}
+	device, err := getFilesystemDevice(client, d, machine.SystemID)
+	if err != nil {
+		return diagFromErr(err)
+	}
+	fsType := d.Get("fs_type").(string)
+	if device.FileSystem.FSType != fsType {
+		if err := device.unmountAndUnformat(client); err != nil {
+			return diagFromErr(err)
+		}
+		if err := device.format(client, fsType); err != nil {
+			return diagFromErr(err)
+		}
+		device.FileSystem = entity.PartitionFileSystem{FSType: fsType}
+	}
+	d.SetId(device.ID())
+
+	if err := setFilesystemMount(client, d, device); err != nil {
+		return diagFromErr(err)
+	}
+
+	return resourceFilesystemRead(ctx, d, m)
+}
+
+func resourceFilesystemRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*ClientConfig).Client
+
+	machine, err := getMachine(client, d.Get("machine").(string))
+	if err != nil {
+		return diagFromErr(err)
+	}
+	device, err := getFilesystemDevice(client, d, machine.SystemID)
+	if err != nil {
+		return diagFromErr(err)
+	}
+	// The device was unformatted, so it needs to be formatted again
+	if device.FileSystem.FSType == "" {
+		log.Printf("[DEBUG] File system of %s was not found, removing it from state\n", device)
+		d.SetId("")
+		return nil
+	}
+	tfState := map[string]interface{}{
+		"fs_type":       device.FileSystem.FSType,
+		"mount_point":   device.FileSystem.MountPoint,
+		"mount_options": device.FileSystem.MountOptions,
+		"uuid":          device.FileSystem.UUID,
+	}
+	if err := setTerraformState(d, tfState); err != nil {
+		return diagFromErr(err)
+	}
+
+	return nil
+}
+
+func resourceFilesystemUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*ClientConfig).Client
+
+	machine, err := getMachine(client, d.Get("machine").(string))
+	if err != nil {
+		return diagFromErr(err)
+	}
+	device, err := getFilesystemDevice(client, d, machine.SystemID)
+	if err != nil {
+		return diagFromErr(err)
+	}
+	if err := setFilesystemMount(client, d, device); err != nil {
+		return diagFromErr(err)
+	}
+
+	return resourceFilesystemRead(ctx, d, m)
+}
+
+func resourceFilesystemDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*ClientConfig).Client
+
+	machine, err := getMachine(client, d.Get("machine").(string))
+	if err != nil {
+		return diagFromErr(err)
+	}
+	device, err := getFilesystemDevice(client, d, machine.SystemID)
+	if err != nil {
+		return diagFromErr(err)
+	}
+	if err := device.unmountAndUnformat(client); err != nil {
+		return diagFromErr(err)
+	}
+
+	return nil
+}
+
+// filesystemDevice is the block device or the partition formatted by the
+// filesystem resource. The partition ID is zero for a block device.
+type filesystemDevice struct {
+	SystemID      string
+	BlockDeviceID int
+	PartitionID   int
+	FileSystem    entity.PartitionFileSystem
+}
+
+func (f *filesystemDevice) String() string {
+	if f.PartitionID != 0 {
+		return fmt.Sprintf("partition (%d) of machine (%s)", f.PartitionID, f.SystemID)
+	}
+	return fmt.Sprintf("block device (%d) of machine (%s)", f.BlockDeviceID, f.SystemID)
+}
+
+// ID returns the resource ID, which is the ID of the formatted device.
+func (f *filesystemDevice) ID() string {
+	if f.PartitionID != 0 {
+		return fmt.Sprintf("%v", f.PartitionID)
+	}
+	return fmt.Sprintf("%v", f.BlockDeviceID)
+}
+
+func (f *filesystemDevice) format(client *client.Client, fsType string) (err error) {
+	if f.PartitionID != 0 {
+		_, err = client.BlockDevicePartition.Format(f.SystemID, f.BlockDeviceID, f.PartitionID, fsType, "")
+	} else {
+		_, err = client.BlockDevice.Format(f.SystemID, f.BlockDeviceID, fsType)
+	}
+	return
+}
+
+func (f *filesystemDevice) mount(client *client.Client, mountPoint string, mountOptions string) (err error) {
+	if f.PartitionID != 0 {
+		_, err = client.BlockDevicePartition.Mount(f.SystemID, f.BlockDeviceID, f.PartitionID, mountPoint, mountOptions)
+	} else {
+		_, err = client.BlockDevice.Mount(f.SystemID, f.BlockDeviceID, mountPoint, mountOptions)
+	}
+	return
+}
+
+func (f *filesystemDevice) unmount(client *client.Client) (err error) {
+	if f.PartitionID != 0 {
+		_, err = client.BlockDevicePartition.Unmount(f.SystemID, f.BlockDeviceID, f.PartitionID)
+	} else {
+		_, err = client.BlockDevice.Unmount(f.SystemID, f.BlockDeviceID)
+	}
+	return
+}
+
+// unmountAndUnformat removes the current file system of the device, if any.
+func (f *filesystemDevice) unmountAndUnformat(client *client.Client) (err error) {
+	if f.FileSystem.MountPoint != "" {
+		if err := f.unmount(client); err != nil {
+			return err
+		}
+	}
+	if f.FileSystem.FSType == "" {
+		return nil
+	}
+	if f.PartitionID != 0 {
+		_, err = client.BlockDevicePartition.Unformat(f.SystemID, f.BlockDeviceID, f.PartitionID)
+	} else {
+		_, err = client.BlockDevice.Unformat(f.SystemID, f.BlockDeviceID)
+	}
+	return
+}
+
+func getFilesystemDevice(client *client.Client, d *schema.ResourceData, machineID string) (*filesystemDevice, error) {
+	blockDevice, err := getBlockDevice(client, machineID, d.Get("block_device").(string))
+	if err != nil {
+		return nil, err
+	}
+	device := &filesystemDevice{
+		SystemID:      machineID,
+		BlockDeviceID: blockDevice.ID,
+		FileSystem:    blockDevice.Filesystem,
+	}
+	partition := d.Get("partition").(string)
+	if partition == "" {
+		return device, nil
+	}
+	for _, p := range blockDevice.Partitions {
+		if isPartitionIdentifier(&p, partition) {
+			device.PartitionID = p.ID
+			device.FileSystem = p.FileSystem
+			return device, nil
+		}
+	}
+	return nil, fmt.Errorf("partition (%s) was not found on block device (%s)", partition, blockDevice.Name)
+}
+
+// setFilesystemMount unmounts the file system if needed, and mounts it as
+// configured.
+func setFilesystemMount(client *client.Client, d *schema.ResourceData, device *filesystemDevice) error {
+	mountPoint := d.Get("mount_point").(string)
+	mountOptions := d.Get("mount_options").(string)
+	fs := device.FileSystem
+	if fs.MountPoint == mountPoint && fs.MountOptions == mountOptions {
+		return nil
+	}
+	if fs.MountPoint != "" {
+		if err := device.unmount(client); err != nil {
+			return err
+		}
+	}
+	if mountPoint == "" {
+		return nil
+	}
+	return device.mount(client, mountPoint, mountOptions)
+}
@@ -2,7 +2,11 @@ package maas
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"log"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
@@ -17,10 +21,12 @@ func resourceMaasInstance() *schema.Resource {
 		Description:   "Provides a resource to deploy and release machines already configured in MAAS, based on the specified parameters. If no parameters are given, a random machine will be allocated and deployed using the defaults.\n\n**NOTE:** The MAAS provider currently provides both standalone resources and in-line resources for network interfaces. You cannot use in-line network interfaces in conjunction with any standalone network interfaces resources. Doing so will cause conflicts and will overwrite network configs.",
 		CreateContext: resourceInstanceCreate,
 		ReadContext:   resourceInstanceRead,
+		UpdateContext: resourceInstanceUpdate,
 		DeleteContext: resourceInstanceDelete,
+		CustomizeDiff: resourceInstanceCustomizeDiff,
 		Importer: &schema.ResourceImporter{
 			StateContext: func(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
-				client := m.(*client.Client)
+				client := m.(*ClientConfig).Client
 				machine, err := getMachine(client, d.Id())
 				if err != nil {
 					return nil, err
@@ -28,6 +34,9 @@ func resourceMaasInstance() *schema.Resource {
 				if machine.StatusName != "Deployed" {
 					return nil, fmt.Errorf("machine '%s' needs to be already deployed to be imported as maas_instance resource", machine.Hostname)
 				}
+				if err := d.Set("redeploy_on_user_data_change", true); err != nil {
+					return nil, err
+				}
 				d.SetId(machine.SystemID)
 				return []*schema.ResourceData{d}, nil
 			},
@@ -62,12 +71,12 @@ func resourceMaasInstance() *schema.Resource {
 						"zone": {
 							Type:        schema.TypeString,
 							Optional:    true,
-							Description: "The zone name of the MAAS machine to be allocated.",
+							Description: "The zone name of the MAAS machine to be allocated. If it's not set, the provider `default_zone` is used.",
 						},
 						"pool": {
 							Type:        schema.TypeString,
 							Optional:    true,
-							Description: "The pool name of the MAAS machine to be allocated.",
+							Description: "The pool name of the MAAS machine to be allocated. If it's not set, the provider `default_pool` is used.",
 						},
 						"tags": {
 							Type:        schema.TypeSet,
@@ -77,37 +86,105 @@ func resourceMaasInstance() *schema.Resource {
 								Type: schema.TypeString,
 							},
 						},
+						"not_tags": {
+							Type:        schema.TypeSet,
+							Optional:    true,
+							Description: "A set of tag names that must not be assigned on the MAAS machine to be allocated.",
+							Elem: &schema.Schema{
+								Type: schema.TypeString,
+							},
+						},
+						"arch": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "The architecture of the MAAS machine to be allocated (e.g. `amd64`, `arm64/generic`).",
+						},
+						"storage": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "The storage constraint of the MAAS machine to be allocated, as a comma separated list of disk sizes (in GB) with optional labels and tags (e.g. `root:50(ssd),100`).",
+						},
 					},
 				},
 			},
 			"deploy_params": {
 				Type:        schema.TypeSet,
 				Optional:    true,
-				ForceNew:    true,
 				MaxItems:    1,
-				Description: "Nested argument with the config used to deploy the allocated machine. Defined below.",
+				Description: "Nested argument with the config used to deploy the allocated machine. Changing it replaces the instance, except for the `user_data` changes when `redeploy_on_user_data_change` is disabled. Defined below.",
 				Elem: &schema.Resource{
 					Schema: map[string]*schema.Schema{
 						"distro_series": {
 							Type:        schema.TypeString,
 							Optional:    true,
-							Description: "The distro series used to deploy the allocated MAAS machine. If it's not given, the MAAS server default value is used.",
+							Description: "The distro series used to deploy the allocated MAAS machine (e.g. `jammy`). A non-Ubuntu OS is selected with the `osystem/series` format (e.g. `centos/centos70`). If it's not given, the MAAS server default value is used.",
 						},
 						"hwe_kernel": {
 							Type:        schema.TypeString,
 							Optional:    true,
-							Description: "Hardware enablement kernel to use with the image. Only used when deploying Ubuntu.",
+							Description: "Hardware enablement kernel to use with the image (e.g. `hwe-22.04`). The HWE kernels are specific to Ubuntu, so it can only be set with an Ubuntu `distro_series`. It's validated against the kernels of the `distro_series` boot resources.",
 						},
 						"user_data": {
 							Type:        schema.TypeString,
 							Optional:    true,
-							Description: "Cloud-init user data script that gets run on the machine once it has deployed. A good practice is to set this with `file(\"/tmp/user-data.txt\")`, where `/tmp/user-data.txt` is a cloud-init script.",
+							Description: "Cloud-init user data script that gets run on the machine once it has deployed. A good practice is to set this with `file(\"/tmp/user-data.txt\")`, where `/tmp/user-data.txt` is a cloud-init script. It can be given as plain text, or already base64 encoded (e.g. with `filebase64(...)`). Large rendered templates can be compressed with `base64gzip(...)`, since cloud-init decompresses gzipped user data.",
 						},
 						"enable_hw_sync": {
 							Type:        schema.TypeBool,
 							Optional:    true,
 							Description: "Periodically sync hardware",
 						},
+						"install_kvm": {
+							Type:        schema.TypeBool,
+							Optional:    true,
+							Description: "Install KVM (libvirt) on the deployed machine and register it as a `virsh` VM host in MAAS. It can't be used together with `register_vmhost`. Defaults to `false`.",
+						},
+						"register_vmhost": {
+							Type:        schema.TypeBool,
+							Optional:    true,
+							Description: "Install LXD on the deployed machine and register it as a `lxd` VM host in MAAS. It can't be used together with `install_kvm`. Defaults to `false`.",
+						},
+						"ephemeral_deploy": {
+							Type:        schema.TypeBool,
+							Optional:    true,
+							Description: "Deploy the machine in memory, without installing the OS to the disks. It can't be used together with `install_kvm` or `register_vmhost`. Defaults to `false`.",
+						},
+					},
+				},
+			},
+			"redeploy_on_user_data_change": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+				Description: "Whether to replace the instance, allocating and deploying a machine again, when the `user_data` of the `deploy_params` is changed. If this is disabled, the new user data is only saved in the state, since cloud-init runs it only when the machine is deployed. Defaults to `true`.",
+			},
+			"release_params": {
+				Type:        schema.TypeSet,
+				Optional:    true,
+				MaxItems:    1,
+				Description: "Nested argument with the config used to release the machine, when the resource is destroyed. Defined below.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"erase": {
+							Type:        schema.TypeBool,
+							Optional:    true,
+							Description: "Erase the machine disks when the machine is released. Without `secure_erase` or `quick_erase`, the disks are fully overwritten with zeros. Defaults to `false`.",
+						},
+						"secure_erase": {
+							Type:        schema.TypeBool,
+							Optional:    true,
+							Description: "Use the drive's secure erase feature, if available. Only used when `erase` is enabled. Defaults to `false`.",
+						},
+						"quick_erase": {
+							Type:        schema.TypeBool,
+							Optional:    true,
+							Description: "Wipe only 2MiB at the start and at the end of the drive to make data recovery inconvenient and unlikely to happen by accident. Only used when `erase` is enabled. If `secure_erase` is enabled too, it is tried first, and the quick erase is used only if the secure erase is not available. Defaults to `false`.",
+						},
+						"force": {
+							Type:        schema.TypeBool,
+							Optional:    true,
+							Description: "Force the release of the machine, even if it's in a state that MAAS would otherwise refuse to release (e.g. a failed deployment of a VM host with VMs). If the machine is a VM host, the VM host and all its VMs are deleted too. Use it with caution. Defaults to `false`.",
+						},
 					},
 				},
 			},
@@ -147,6 +224,21 @@ func resourceMaasInstance() *schema.Resource {
 				Computed:    true,
 				Description: "The deployed MAAS machine hostname.",
 			},
+			"osystem": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The operating system of the deployed MAAS machine.",
+			},
+			"distro_series": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The distro series of the deployed MAAS machine.",
+			},
+			"hwe_kernel": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The kernel of the deployed MAAS machine.",
+			},
 			"zone": {
 				Type:        schema.TypeString,
 				Computed:    true,
@@ -183,20 +275,30 @@ func resourceMaasInstance() *schema.Resource {
 					Type: schema.TypeString,
 				},
 			},
+			"owner_data": {
+				Type:        schema.TypeMap,
+				Optional:    true,
+				Computed:    true,
+				Description: "A map with the owner data (workload annotations) of the deployed MAAS machine. Only the keys added to or removed from this map are changed. MAAS keeps the owner data only while the machine is allocated or deployed, so changing it fails otherwise, and MAAS clears it when the machine is released. This is computed if it's not set.",
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
 		},
 		Timeouts: &schema.ResourceTimeout{
 			Create: schema.DefaultTimeout(20 * time.Minute),
+			Delete: schema.DefaultTimeout(20 * time.Minute),
 		},
 	}
 }
 
 func resourceInstanceCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	client := m.(*client.Client)
+	client := m.(*ClientConfig).Client
 
 	// Allocate MAAS machine
-	machine, err := client.Machines.Allocate(getMachinesAllocateParams(d))
+	machine, err := allocateMachine(ctx, client, getMachinesAllocateParams(d, m.(*ClientConfig)))
 	if err != nil {
-		return diag.FromErr(err)
+		return diagFromErr(err)
 	}
 
 	// Save system id
@@ -205,19 +307,27 @@ func resourceInstanceCreate(ctx context.Context, d *schema.ResourceData, m inter
 	// Configure network interfaces
 	err = configureInstanceNetworkInterfaces(client, d, machine)
 	if err != nil {
-		return diag.FromErr(err)
+		return diagFromErr(err)
 	}
 
 	// Deploy MAAS machine
-	machine, err = client.Machine.Deploy(machine.SystemID, getMachineDeployParams(d))
+	err = retryMachineConflict(ctx, client, machine.SystemID, isMachineStatus("Deploying", "Deployed"), func() error {
+		_, err := m.(*ClientConfig).Machine.Deploy(machine.SystemID, getMachineDeployParams(d))
+		return err
+	})
 	if err != nil {
-		return diag.FromErr(err)
+		return diagFromErr(err)
 	}
 
 	// Wait for MAAS machine to be deployed
-	_, err = waitForMachineStatus(ctx, client, machine.SystemID, []string{"Deploying"}, []string{"Deployed"})
+	machine, err = waitForMachineStatus(ctx, client, machine.SystemID, []string{"Deploying"}, []string{"Deployed"}, d.Timeout(schema.TimeoutCreate))
 	if err != nil {
-		return diag.FromErr(err)
+		return diagFromErr(err)
+	}
+
+	// Set the owner data
+	if err := setInstanceOwnerData(d, m.(*ClientConfig), machine); err != nil {
+		return diagFromErr(err)
 	}
 
 	// Read MAAS machine info
@@ -225,12 +335,12 @@ func resourceInstanceCreate(ctx context.Context, d *schema.ResourceData, m inter
 }
 
 func resourceInstanceRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	client := m.(*client.Client)
+	client := m.(*ClientConfig).Client
 
 	// Get MAAS machine
 	machine, err := client.Machine.Get(d.Id())
 	if err != nil {
-		return diag.FromErr(err)
+		return diagFromErr(err)
 	}
 	// Set Terraform state
 	ipAddresses := make([]string, len(machine.IPAddresses))
@@ -238,68 +348,303 @@ func resourceInstanceRead(ctx context.Context, d *schema.ResourceData, m interfa
 		ipAddresses[i] = ip.String()
 	}
 	tfState := map[string]interface{}{
-		"fqdn":         machine.FQDN,
-		"hostname":     machine.Hostname,
-		"zone":         machine.Zone.Name,
-		"pool":         machine.Pool.Name,
-		"tags":         machine.TagNames,
-		"cpu_count":    machine.CPUCount,
-		"memory":       machine.Memory,
-		"ip_addresses": ipAddresses,
+		"fqdn":          machine.FQDN,
+		"hostname":      machine.Hostname,
+		"osystem":       machine.OSystem,
+		"distro_series": machine.DistroSeries,
+		"hwe_kernel":    machine.HWEKernel,
+		"zone":          machine.Zone.Name,
+		"pool":          machine.Pool.Name,
+		"tags":          machine.TagNames,
+		"cpu_count":     machine.CPUCount,
+		"memory":        machine.Memory,
+		"ip_addresses":  ipAddresses,
+		"owner_data":    getMachineOwnerData(machine),
 	}
 	if err := setTerraformState(d, tfState); err != nil {
-		return diag.FromErr(err)
+		return diagFromErr(err)
 	}
 
 	return nil
 }
 
+func resourceInstanceUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*ClientConfig).Client
+
+	// The release params are used only when the resource is destroyed, and
+	// the user data changes without a redeploy are only saved in the state
+	if d.HasChange("owner_data") {
+		machine, err := client.Machine.Get(d.Id())
+		if err != nil {
+			return diagFromErr(err)
+		}
+		if err := setInstanceOwnerData(d, m.(*ClientConfig), machine); err != nil {
+			return diagFromErr(err)
+		}
+	}
+
+	return resourceInstanceRead(ctx, d, m)
+}
+
 func resourceInstanceDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	client := m.(*client.Client)
+	client := m.(*ClientConfig).Client
 
 	// Release MAAS machine
-	err := client.Machines.Release([]string{d.Id()}, "Released by Terraform")
+	machine, err := client.Machine.Get(d.Id())
+	if err != nil {
+		return diagFromErr(err)
+	}
+	if err := checkMachineNotLocked(machine); err != nil {
+		return diagFromErr(err)
+	}
+	err = retryMachineConflict(ctx, client, d.Id(), isMachineStatus("Releasing", "Disk erasing", "Ready"), func() error {
+		_, err := m.(*ClientConfig).Machine.Release(d.Id(), getMachineReleaseParams(d))
+		return err
+	})
 	if err != nil {
-		return diag.FromErr(err)
+		return diagFromErr(err)
 	}
 
 	// Wait MAAS machine to be released
-	_, err = waitForMachineStatus(ctx, client, d.Id(), []string{"Releasing"}, []string{"Ready"})
+	_, err = waitForMachineStatus(ctx, client, d.Id(), []string{"Releasing", "Disk erasing"}, []string{"Ready"}, d.Timeout(schema.TimeoutDelete))
 	if err != nil {
-		return diag.FromErr(err)
+		return diagFromErr(err)
 	}
 
 	return nil
 }
 
-func getMachinesAllocateParams(d *schema.ResourceData) *entity.MachineAllocateParams {
+func resourceInstanceCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	if !d.HasChange("deploy_params") {
+		return nil
+	}
+	if !d.NewValueKnown("deploy_params") {
+		return d.ForceNew("deploy_params")
+	}
+	o, n := d.GetChange("deploy_params")
+	if d.Get("redeploy_on_user_data_change").(bool) || !isUserDataOnlyChange(o.(*schema.Set).List(), n.(*schema.Set).List()) {
+		if err := d.ForceNew("deploy_params"); err != nil {
+			return err
+		}
+	}
+	p := d.Get("deploy_params").(*schema.Set).List()
+	if len(p) == 0 {
+		return nil
+	}
+	deployParams := p[0].(map[string]interface{})
+	if deployParams["install_kvm"].(bool) && deployParams["register_vmhost"].(bool) {
+		return fmt.Errorf("only one of install_kvm or register_vmhost can be enabled")
+	}
+	if deployParams["ephemeral_deploy"].(bool) && (deployParams["install_kvm"].(bool) || deployParams["register_vmhost"].(bool)) {
+		return fmt.Errorf("ephemeral_deploy can't be enabled together with install_kvm or register_vmhost")
+	}
+	hweKernel := deployParams["hwe_kernel"].(string)
+	if hweKernel == "" {
+		return nil
+	}
+	distroSeries := deployParams["distro_series"].(string)
+	if distroSeries == "" {
+		value, err := m.(*ClientConfig).MAASServer.Get("default_distro_series")
+		if err != nil {
+			return err
+		}
+		if err := json.Unmarshal([]byte(value), &distroSeries); err != nil {
+			return err
+		}
+	}
+	// The HWE kernels are specific to Ubuntu, so the other OSes can't use them
+	if osName := strings.SplitN(distroSeries, "/", 2)[0]; strings.Contains(distroSeries, "/") && osName != "ubuntu" {
+		return fmt.Errorf("hwe_kernel (%s) can only be used with the Ubuntu distro series, the distro series (%s) is not an Ubuntu one", hweKernel, distroSeries)
+	}
+	kernels, err := getDistroSeriesKernels(m.(*ClientConfig).BootResources, distroSeries)
+	if err != nil {
+		return err
+	}
+	if len(kernels) == 0 {
+		return fmt.Errorf("hwe_kernel (%s) can only be used with the Ubuntu distro series, and no Ubuntu boot resources of the distro series (%s) are imported", hweKernel, distroSeries)
+	}
+	for _, kernel := range kernels {
+		if kernel == hweKernel {
+			return nil
+		}
+	}
+	return fmt.Errorf("hwe_kernel (%s) is not available for the distro series (%s), valid options are: %s", hweKernel, distroSeries, strings.Join(kernels, ", "))
+}
+
+// isUserDataOnlyChange returns true if the only change between the old and
+// the new deploy params is the user data.
+func isUserDataOnlyChange(oldParams []interface{}, newParams []interface{}) bool {
+	if len(oldParams) == 0 || len(newParams) == 0 {
+		return false
+	}
+	o := oldParams[0].(map[string]interface{})
+	n := newParams[0].(map[string]interface{})
+	for k, v := range n {
+		if k != "user_data" && o[k] != v {
+			return false
+		}
+	}
+	return true
+}
+
+// getDistroSeriesKernels returns the kernels (e.g. `ga-22.04`, `hwe-22.04`)
+// of the imported boot resources of the given distro series. The Ubuntu boot
+// resources have one sub-architecture per kernel.
+func getDistroSeriesKernels(bootResources *BootResources, distroSeries string) ([]string, error) {
+	if !strings.Contains(distroSeries, "/") {
+		distroSeries = "ubuntu/" + distroSeries
+	}
+	resources, err := bootResources.Get()
+	if err != nil {
+		return nil, err
+	}
+	found := map[string]bool{}
+	kernels := []string{}
+	for _, r := range resources {
+		archParts := strings.SplitN(r.Architecture, "/", 2)
+		if r.Name != distroSeries || len(archParts) != 2 || archParts[1] == "generic" || found[archParts[1]] {
+			continue
+		}
+		found[archParts[1]] = true
+		kernels = append(kernels, archParts[1])
+	}
+	sort.Strings(kernels)
+	return kernels, nil
+}
+
+// getMachinesAllocateParams returns the machine allocation constraints. The
+// provider default zone and pool are used when they're not set.
+func getMachinesAllocateParams(d *schema.ResourceData, clientConfig *ClientConfig) *entity.MachineAllocateParams {
+	params := &entity.MachineAllocateParams{
+		Zone: clientConfig.DefaultZone,
+		Pool: clientConfig.DefaultPool,
+	}
 	p, ok := d.GetOk("allocate_params")
 	if !ok {
-		return &entity.MachineAllocateParams{}
+		return params
 	}
 	allocateParams := p.(*schema.Set).List()[0].(map[string]interface{})
-	return &entity.MachineAllocateParams{
-		CPUCount: allocateParams["min_cpu_count"].(int),
-		Mem:      allocateParams["min_memory"].(int),
-		Name:     allocateParams["hostname"].(string),
-		Zone:     allocateParams["zone"].(string),
-		Pool:     allocateParams["pool"].(string),
-		Tags:     convertToStringSlice(allocateParams["tags"].(*schema.Set).List()),
+	params.CPUCount = allocateParams["min_cpu_count"].(int)
+	params.Mem = allocateParams["min_memory"].(int)
+	params.Name = allocateParams["hostname"].(string)
+	params.Arch = allocateParams["arch"].(string)
+	params.Tags = convertToStringSlice(allocateParams["tags"].(*schema.Set).List())
+	params.NotTags = convertToStringSlice(allocateParams["not_tags"].(*schema.Set).List())
+	if zone := allocateParams["zone"].(string); zone != "" {
+		params.Zone = zone
+	}
+	if pool := allocateParams["pool"].(string); pool != "" {
+		params.Pool = pool
+	}
+	if storage := allocateParams["storage"].(string); storage != "" {
+		params.Storage = []string{storage}
+	}
+	return params
+}
+
+// allocateMachine allocates a machine matching the given constraints. MAAS
+// returns a conflict error when no machine is available, which may also
+// happen when a concurrent allocation takes the matching machine first, so
+// the allocation is retried a few times before giving up.
+func allocateMachine(ctx context.Context, client *client.Client, params *entity.MachineAllocateParams) (*entity.Machine, error) {
+	const maxAttempts = 3
+	for attempt := 1; ; attempt++ {
+		machine, err := client.Machines.Allocate(params)
+		if err == nil {
+			return machine, nil
+		}
+		if !isConflictError(err) || attempt == maxAttempts {
+			return nil, err
+		}
+		log.Printf("[DEBUG] No machine available to allocate (attempt %d of %d): %s\n", attempt, maxAttempts, err)
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(10 * time.Second):
+		}
 	}
 }
 
-func getMachineDeployParams(d *schema.ResourceData) *entity.MachineDeployParams {
+func getMachineDeployParams(d *schema.ResourceData) *MachineDeployParams {
 	p, ok := d.GetOk("deploy_params")
 	if !ok {
-		return &entity.MachineDeployParams{}
+		return &MachineDeployParams{}
 	}
 	deployParams := p.(*schema.Set).List()[0].(map[string]interface{})
-	return &entity.MachineDeployParams{
-		DistroSeries: deployParams["distro_series"].(string),
-		EnableHwSync: deployParams["enable_hw_sync"].(bool),
-		HWEKernel:    deployParams["hwe_kernel"].(string),
-		UserData:     base64Encode([]byte(deployParams["user_data"].(string))),
+	return &MachineDeployParams{
+		MachineDeployParams: entity.MachineDeployParams{
+			DistroSeries:   deployParams["distro_series"].(string),
+			EnableHwSync:   deployParams["enable_hw_sync"].(bool),
+			HWEKernel:      deployParams["hwe_kernel"].(string),
+			InstallKVM:     deployParams["install_kvm"].(bool),
+			RegisterVMHost: deployParams["register_vmhost"].(bool),
+			UserData:       base64Encode([]byte(deployParams["user_data"].(string))),
+		},
+		EphemeralDeploy: deployParams["ephemeral_deploy"].(bool),
+	}
+}
+
+func getMachineReleaseParams(d *schema.ResourceData) *MachineReleaseParams {
+	params := &MachineReleaseParams{
+		Comment: "Released by Terraform",
+	}
+	p, ok := d.GetOk("release_params")
+	if !ok {
+		return params
+	}
+	releaseParams := p.(*schema.Set).List()[0].(map[string]interface{})
+	params.Erase = releaseParams["erase"].(bool)
+	params.SecureErase = releaseParams["secure_erase"].(bool)
+	params.QuickErase = releaseParams["quick_erase"].(bool)
+	params.Force = releaseParams["force"].(bool)
+	return params
+}
+
+// setInstanceOwnerData applies the changes of the configured owner data to
+// the machine. MAAS keeps the owner data only while the machine is allocated
+// or deployed, so the changes fail otherwise.
+func setInstanceOwnerData(d *schema.ResourceData, clientConfig *ClientConfig, machine *entity.Machine) error {
+	if !d.HasChange("owner_data") || d.GetRawConfig().GetAttr("owner_data").IsNull() {
+		return nil
+	}
+	if !isMachineStatus("Allocated", "Deploying", "Deployed")(machine) {
+		return fmt.Errorf("owner data of machine (%s) can't be set while its status is %q, MAAS keeps the owner data only while the machine is allocated or deployed", machine.SystemID, machine.StatusName)
+	}
+	o, n := d.GetChange("owner_data")
+	changes := getOwnerDataChanges(o.(map[string]interface{}), n.(map[string]interface{}))
+	if len(changes) == 0 {
+		return nil
+	}
+	_, err := clientConfig.Machine.SetOwnerData(machine.SystemID, changes)
+	return err
+}
+
+// getOwnerDataChanges returns the owner data keys added or changed from the
+// old to the new map, and the removed keys with an empty value, which makes
+// MAAS delete them.
+func getOwnerDataChanges(old map[string]interface{}, new map[string]interface{}) map[string]string {
+	changes := map[string]string{}
+	for k, v := range new {
+		if oldValue, ok := old[k]; !ok || oldValue != v {
+			changes[k] = v.(string)
+		}
+	}
+	for k := range old {
+		if _, ok := new[k]; !ok {
+			changes[k] = ""
+		}
+	}
+	return changes
+}
+
+func getMachineOwnerData(machine *entity.Machine) map[string]string {
+	ownerData := map[string]string{}
+	if data, ok := machine.OwnerData.(map[string]interface{}); ok {
+		for k, v := range data {
+			ownerData[k] = fmt.Sprintf("%v", v)
+		}
 	}
+	return ownerData
 }
 
 func configureInstanceNetworkInterfaces(client *client.Client, d *schema.ResourceData, machine *entity.Machine) error {
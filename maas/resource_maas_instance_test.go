@@ -0,0 +1,98 @@
+package maas
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetOwnerDataChanges(t *testing.T) {
+	testCases := []struct {
+		name string
+		old  map[string]interface{}
+		new  map[string]interface{}
+		out  map[string]string
+	}{
+		{
+			name: "keys added",
+			old:  map[string]interface{}{},
+			new:  map[string]interface{}{"app": "web", "version": "1.0"},
+			out:  map[string]string{"app": "web", "version": "1.0"},
+		},
+		{
+			name: "key updated",
+			old:  map[string]interface{}{"app": "web", "version": "1.0"},
+			new:  map[string]interface{}{"app": "web", "version": "1.1"},
+			out:  map[string]string{"version": "1.1"},
+		},
+		{
+			name: "key removed",
+			old:  map[string]interface{}{"app": "web", "version": "1.0"},
+			new:  map[string]interface{}{"app": "web"},
+			out:  map[string]string{"version": ""},
+		},
+		{
+			name: "no changes",
+			old:  map[string]interface{}{"app": "web"},
+			new:  map[string]interface{}{"app": "web"},
+			out:  map[string]string{},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			out := getOwnerDataChanges(testCase.old, testCase.new)
+			assert.Equal(t, testCase.out, out, fmt.Sprintf("getOwnerDataChanges(%v, %v) => %v, want %v", testCase.old, testCase.new, out, testCase.out))
+		})
+	}
+}
+
+func TestIsUserDataOnlyChange(t *testing.T) {
+	deployParams := func(distroSeries string, userData string) []interface{} {
+		return []interface{}{map[string]interface{}{
+			"distro_series":  distroSeries,
+			"hwe_kernel":     "",
+			"user_data":      userData,
+			"enable_hw_sync": false,
+		}}
+	}
+	testCases := []struct {
+		name string
+		old  []interface{}
+		new  []interface{}
+		out  bool
+	}{
+		{
+			name: "user data changed",
+			old:  deployParams("jammy", "#cloud-config"),
+			new:  deployParams("jammy", "#cloud-config\npackages: [nginx]"),
+			out:  true,
+		},
+		{
+			name: "distro series and user data changed",
+			old:  deployParams("focal", "#cloud-config"),
+			new:  deployParams("jammy", "#cloud-config\npackages: [nginx]"),
+			out:  false,
+		},
+		{
+			name: "deploy params added",
+			old:  []interface{}{},
+			new:  deployParams("jammy", "#cloud-config"),
+			out:  false,
+		},
+		{
+			name: "deploy params removed",
+			old:  deployParams("jammy", "#cloud-config"),
+			new:  []interface{}{},
+			out:  false,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			out := isUserDataOnlyChange(testCase.old, testCase.new)
+			assert.Equal(t, testCase.out, out, fmt.Sprintf("isUserDataOnlyChange(%v, %v) => %v, want %v", testCase.old, testCase.new, out, testCase.out))
+		})
+	}
+}
@@ -0,0 +1,289 @@
+package maas
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/maas/gomaasclient/client"
+	"github.com/maas/gomaasclient/entity"
+)
+
+func resourceMaasLogicalVolume() *schema.Resource {
+	return &schema.Resource{
+		Description:   "Provides a resource to manage the LVM logical volumes of MAAS machines' volume groups.",
+		CreateContext: resourceLogicalVolumeCreate,
+		ReadContext:   resourceLogicalVolumeRead,
+		UpdateContext: resourceLogicalVolumeUpdate,
+		DeleteContext: resourceLogicalVolumeDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: func(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+				idParts := strings.Split(d.Id(), ":")
+				if len(idParts) != 3 || idParts[0] == "" || idParts[1] == "" || idParts[2] == "" {
+					return nil, fmt.Errorf("unexpected format of ID (%q), expected MACHINE:VOLUME_GROUP:LOGICAL_VOLUME, where MACHINE is a system ID, hostname, FQDN, or MAC address, VOLUME_GROUP is an ID or name, and LOGICAL_VOLUME is an ID or name", d.Id())
+				}
+				clientConfig := m.(*ClientConfig)
+				machine, err := getMachine(clientConfig.Client, idParts[0])
+				if err != nil {
+					return nil, err
+				}
+				volumeGroup, err := getVolumeGroup(clientConfig, machine.SystemID, idParts[1])
+				if err != nil {
+					return nil, err
+				}
+				var logicalVolume *StorageDevice
+				for _, lv := range volumeGroup.LogicalVolumes {
+					if fmt.Sprintf("%v", lv.ID) == idParts[2] || lv.Name == idParts[2] || lv.Name == getLogicalVolumeFullName(volumeGroup.Name, idParts[2]) {
+						logicalVolume = &lv
+						break
+					}
+				}
+				if logicalVolume == nil {
+					return nil, fmt.Errorf("logical volume (%s) was not found in volume group (%s)", idParts[2], volumeGroup.Name)
+				}
+				tfState := map[string]interface{}{
+					"id":           fmt.Sprintf("%v", logicalVolume.ID),
+					"machine":      machine.SystemID,
+					"volume_group": idParts[1],
+				}
+				if err := setTerraformState(d, tfState); err != nil {
+					return nil, err
+				}
+				return []*schema.ResourceData{d}, nil
+			},
+		},
+
+		Schema: map[string]*schema.Schema{
+			"machine": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The machine identifier (system ID, hostname, FQDN, or MAC address) that owns the logical volume.",
+			},
+			"volume_group": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The volume group identifier (ID or name) of the logical volume.",
+			},
+			"name": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The logical volume name. MAAS prefixes the name of the logical volume block device with the volume group name (e.g. `vg0-lv0`).",
+			},
+			"size_gigabytes": {
+				Type:         schema.TypeInt,
+				Required:     true,
+				ValidateFunc: validation.IntAtLeast(1),
+				Description:  "The logical volume size (given in GB). MAAS allows resizing the logical volume only while the machine is in the `Ready` or `Allocated` state, and within the space available in the volume group.",
+			},
+			"uuid": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				Description: "The logical volume UUID. This argument is computed if it's not given.",
+			},
+			"fs_type": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The file system type (e.g. `ext4`). If this is not set, the logical volume is unformatted.",
+			},
+			"mount_point": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				RequiredWith: []string{"fs_type"},
+				Description:  "The mount point used. If this is not set, the logical volume is not mounted. This is used only if the logical volume is formatted.",
+			},
+			"mount_options": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				RequiredWith: []string{"mount_point"},
+				Description:  "The options used for the logical volume mount.",
+			},
+			"block_device_id": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The ID of the virtual block device of the logical volume. It can be used to create other storage resources on top of the logical volume.",
+			},
+			"path": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The path of the logical volume block device.",
+			},
+		},
+	}
+}
+
+func resourceLogicalVolumeCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	clientConfig := m.(*ClientConfig)
+
+	machine, err := getMachine(clientConfig.Client, d.Get("machine").(string))
+	if err != nil {
+		return diagFromErr(err)
+	}
+	volumeGroup, err := getVolumeGroup(clientConfig, machine.SystemID, d.Get("volume_group").(string))
+	if err != nil {
+		return diagFromErr(err)
+	}
+	params := &LogicalVolumeParams{
+		Name: d.Get("name").(string),
+		UUID: d.Get("uuid").(string),
+		Size: int64(d.Get("size_gigabytes").(int)) * 1024 * 1024 * 1024,
+	}
+	blockDevice, err := clientConfig.VolumeGroups.CreateLogicalVolume(machine.SystemID, volumeGroup.ID, params)
+	if err != nil {
+		return diagFromErr(err)
+	}
+	d.SetId(fmt.Sprintf("%v", blockDevice.ID))
+
+	if err := setLogicalVolumeFileSystem(clientConfig.Client, d, blockDevice); err != nil {
+		return diagFromErr(err)
+	}
+
+	return resourceLogicalVolumeRead(ctx, d, m)
+}
+
+func resourceLogicalVolumeRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	clientConfig := m.(*ClientConfig)
+
+	id, err := strconv.Atoi(d.Id())
+	if err != nil {
+		return diagFromErr(err)
+	}
+	machine, err := getMachine(clientConfig.Client, d.Get("machine").(string))
+	if err != nil {
+		return diagFromErr(err)
+	}
+	volumeGroup, err := getVolumeGroup(clientConfig, machine.SystemID, d.Get("volume_group").(string))
+	if err != nil {
+		return diagFromErr(err)
+	}
+	blockDevice, err := clientConfig.Client.BlockDevice.Get(machine.SystemID, id)
+	if err != nil {
+		return diagFromErr(err)
+	}
+	// Keep the configured name if MAAS only prefixed it with the volume group name
+	name := blockDevice.Name
+	if configuredName := d.Get("name").(string); blockDevice.Name == getLogicalVolumeFullName(volumeGroup.Name, configuredName) {
+		name = configuredName
+	}
+	tfState := map[string]interface{}{
+		"name":            name,
+		"size_gigabytes":  int(blockDevice.Size / (1024 * 1024 * 1024)),
+		"uuid":            blockDevice.UUID,
+		"fs_type":         blockDevice.Filesystem.FSType,
+		"mount_point":     blockDevice.Filesystem.MountPoint,
+		"mount_options":   blockDevice.Filesystem.MountOptions,
+		"block_device_id": fmt.Sprintf("%v", blockDevice.ID),
+		"path":            blockDevice.Path,
+	}
+	if err := setTerraformState(d, tfState); err != nil {
+		return diagFromErr(err)
+	}
+
+	return nil
+}
+
+func resourceLogicalVolumeUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	clientConfig := m.(*ClientConfig)
+
+	id, err := strconv.Atoi(d.Id())
+	if err != nil {
+		return diagFromErr(err)
+	}
+	machine, err := getMachine(clientConfig.Client, d.Get("machine").(string))
+	if err != nil {
+		return diagFromErr(err)
+	}
+	blockDevice, err := clientConfig.Client.BlockDevice.Get(machine.SystemID, id)
+	if err != nil {
+		return diagFromErr(err)
+	}
+	if d.HasChanges("name", "size_gigabytes", "uuid") {
+		params := &entity.BlockDeviceParams{
+			Size: d.Get("size_gigabytes").(int) * 1024 * 1024 * 1024,
+			UUID: d.Get("uuid").(string),
+		}
+		if d.HasChange("name") {
+			params.Name = d.Get("name").(string)
+		}
+		blockDevice, err = clientConfig.Client.BlockDevice.Update(machine.SystemID, id, params)
+		if err != nil {
+			return diagFromErr(err)
+		}
+	}
+	if d.HasChanges("fs_type", "mount_point", "mount_options") {
+		if err := setLogicalVolumeFileSystem(clientConfig.Client, d, blockDevice); err != nil {
+			return diagFromErr(err)
+		}
+	}
+
+	return resourceLogicalVolumeRead(ctx, d, m)
+}
+
+func resourceLogicalVolumeDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	clientConfig := m.(*ClientConfig)
+
+	id, err := strconv.Atoi(d.Id())
+	if err != nil {
+		return diagFromErr(err)
+	}
+	machine, err := getMachine(clientConfig.Client, d.Get("machine").(string))
+	if err != nil {
+		return diagFromErr(err)
+	}
+	volumeGroup, err := getVolumeGroup(clientConfig, machine.SystemID, d.Get("volume_group").(string))
+	if err != nil {
+		return diagFromErr(err)
+	}
+	if err := clientConfig.VolumeGroups.DeleteLogicalVolume(machine.SystemID, volumeGroup.ID, id); err != nil {
+		return diagFromErr(err)
+	}
+
+	return nil
+}
+
+// getLogicalVolumeFullName returns the name MAAS gives to the block device of
+// the logical volume, which is prefixed with the volume group name.
+func getLogicalVolumeFullName(volumeGroupName string, name string) string {
+	return fmt.Sprintf("%s-%s", volumeGroupName, name)
+}
+
+// setLogicalVolumeFileSystem unmounts and unformats the logical volume if
+// needed, and formats and mounts it as configured.
+func setLogicalVolumeFileSystem(client *client.Client, d *schema.ResourceData, blockDevice *entity.BlockDevice) error {
+	fsType := d.Get("fs_type").(string)
+	mountPoint := d.Get("mount_point").(string)
+	mountOptions := d.Get("mount_options").(string)
+	fs := blockDevice.Filesystem
+	reformat := fs.FSType != fsType
+	if fs.MountPoint != "" && (reformat || fs.MountPoint != mountPoint || fs.MountOptions != mountOptions) {
+		if _, err := client.BlockDevice.Unmount(blockDevice.SystemID, blockDevice.ID); err != nil {
+			return err
+		}
+		fs.MountPoint = ""
+	}
+	if fs.FSType != "" && reformat {
+		if _, err := client.BlockDevice.Unformat(blockDevice.SystemID, blockDevice.ID); err != nil {
+			return err
+		}
+	}
+	if fsType == "" {
+		return nil
+	}
+	if reformat {
+		if _, err := client.BlockDevice.Format(blockDevice.SystemID, blockDevice.ID, fsType); err != nil {
+			return err
+		}
+	}
+	if mountPoint != "" && fs.MountPoint == "" {
+		if _, err := client.BlockDevice.Mount(blockDevice.SystemID, blockDevice.ID, mountPoint, mountOptions); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"net"
+	"strings"
 	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
@@ -21,9 +23,10 @@ func resourceMaasMachine() *schema.Resource {
 		ReadContext:   resourceMachineRead,
 		UpdateContext: resourceMachineUpdate,
 		DeleteContext: resourceMachineDelete,
+		CustomizeDiff: resourceMachineCustomizeDiff,
 		Importer: &schema.ResourceImporter{
 			StateContext: func(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
-				client := m.(*client.Client)
+				client := m.(*ClientConfig).Client
 				machine, err := getMachine(client, d.Id())
 				if err != nil {
 					return nil, err
@@ -38,6 +41,7 @@ func resourceMaasMachine() *schema.Resource {
 					"power_parameters": powerParams,
 					"pxe_mac_address":  machine.BootInterface.MACAddress,
 					"architecture":     machine.Architecture,
+					"on_destroy":       "delete",
 				}
 				if err := setTerraformState(d, tfState); err != nil {
 					return nil, err
@@ -95,43 +99,155 @@ func resourceMaasMachine() *schema.Resource {
 				Type:        schema.TypeString,
 				Optional:    true,
 				Computed:    true,
-				Description: "The domain of the machine. This is computed if it's not set.",
+				Description: "The domain of the machine. If it's not set, the provider `default_domain` is used. This is computed if it's not set.",
 			},
 			"zone": {
 				Type:        schema.TypeString,
 				Optional:    true,
 				Computed:    true,
-				Description: "The zone of the machine. This is computed if it's not set.",
+				Description: "The zone of the machine. If it's not set, the provider `default_zone` is used. This is computed if it's not set.",
 			},
 			"pool": {
 				Type:        schema.TypeString,
 				Optional:    true,
 				Computed:    true,
-				Description: "The resource pool of the machine. This is computed if it's not set.",
+				Description: "The resource pool of the machine. If it's not set, the provider `default_pool` is used. This is computed if it's not set.",
+			},
+			"tags": {
+				Type:     schema.TypeSet,
+				Optional: true,
+				Computed: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+				Description: "A set of tag names assigned to the machine. The automatic tags (the ones with a definition) are managed by MAAS, and they are ignored. This is computed if it's not set.",
+			},
+			"locked": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Computed:    true,
+				Description: "Boolean value indicating if the machine is locked, so it can't be changed or released. MAAS only locks the deployed machines. A machine locked in the state can't be destroyed, it must be unlocked first by setting this to `false`, and otherwise the machine is unlocked before it's destroyed. This is computed if it's not set.",
+			},
+			"desired_power_state": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				ValidateDiagFunc: validation.ToDiagFunc(validation.StringInSlice([]string{"on", "off"}, false)),
+				Description:      "The power state the machine is kept in. Valid options are: `on`, `off`. If this is not set, the machine power state is not managed.",
+			},
+			"power_state": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The current power state of the machine (e.g. `on`, `off`, `unknown`).",
+			},
+			"default_gateway": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Computed:         true,
+				ValidateDiagFunc: validation.ToDiagFunc(validation.IsIPAddress),
+				Description:      "The default gateway IP address of the machine. It must be the gateway IP of a subnet linked to one of the machine network interfaces, and it can be changed only while the machine network configuration is editable (e.g. when the machine is `Ready` or `Allocated`). Don't use it along with the `default_gateway` of the network interface links of the machine. This is computed if it's not set.",
+			},
+			"on_destroy": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Default:          "delete",
+				ValidateDiagFunc: validation.ToDiagFunc(validation.StringInSlice([]string{"delete", "release"}, false)),
+				Description:      "What is done with the machine when the resource is destroyed. Valid options are: `delete` (the machine is removed from MAAS), and `release` (the machine is kept in MAAS, and it's released if it's allocated or deployed). Defaults to `delete`.",
+			},
+			"commissioning_params": {
+				Type:        schema.TypeSet,
+				Optional:    true,
+				MaxItems:    1,
+				Description: "Nested argument with the config used to commission the machine, when the resource is created. Changing it doesn't commission the machine again. If it's not set, the MAAS server default config is used. Defined below.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"commissioning_scripts": {
+							Type:        schema.TypeList,
+							Optional:    true,
+							Description: "A list of commissioning script names and tags to run, in addition to the builtin ones. Set this to `[\"none\"]` to run only the builtin commissioning scripts.",
+							Elem: &schema.Schema{
+								Type: schema.TypeString,
+							},
+						},
+						"testing_scripts": {
+							Type:        schema.TypeList,
+							Optional:    true,
+							Description: "A list of testing script names and tags to run after commissioning. Set this to `[\"none\"]` to skip testing. If it's not set, the MAAS server default testing scripts are run.",
+							Elem: &schema.Schema{
+								Type: schema.TypeString,
+							},
+						},
+						"enable_ssh": {
+							Type:        schema.TypeBool,
+							Optional:    true,
+							Description: "Keep the machine powered on after commissioning, so it can be accessed via SSH. Defaults to `false`.",
+						},
+						"skip_bmc_config": {
+							Type:        schema.TypeBool,
+							Optional:    true,
+							Description: "Skip the BMC configuration scripts. Defaults to `false`.",
+						},
+						"skip_networking": {
+							Type:        schema.TypeBool,
+							Optional:    true,
+							Description: "Keep the existing network interfaces configuration of the machine. Defaults to `false`.",
+						},
+						"skip_storage": {
+							Type:        schema.TypeBool,
+							Optional:    true,
+							Description: "Keep the existing storage configuration of the machine. Defaults to `false`.",
+						},
+					},
+				},
 			},
 		},
 		Timeouts: &schema.ResourceTimeout{
 			Create: schema.DefaultTimeout(20 * time.Minute),
+			Update: schema.DefaultTimeout(10 * time.Minute),
+			Delete: schema.DefaultTimeout(20 * time.Minute),
 		},
 	}
 }
 
 func resourceMachineCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	client := m.(*client.Client)
+	client := m.(*ClientConfig).Client
 
 	// Create MAAS machine
-	machine, err := client.Machines.Create(getMachineParams(d), getMachinePowerParams(d))
+	machineParams := getMachineParams(d, m.(*ClientConfig))
+	powerParams := getMachinePowerParams(d)
+	commissionParams, err := getMachineCommissionParams(d)
 	if err != nil {
-		return diag.FromErr(err)
+		return diagFromErr(err)
+	}
+	if commissionParams != nil {
+		// The machine is commissioned below, with the given config. gomaasclient
+		// omits the `commission` parameter when it's false, so it's sent along
+		// with the power parameters.
+		machineParams.Commission = false
+		powerParams["commission"] = "false"
+	}
+	machine, err := client.Machines.Create(machineParams, powerParams)
+	if err != nil {
+		return diagFromErr(err)
 	}
 
 	// Save Id
 	d.SetId(machine.SystemID)
 
+	// Commission the machine
+	if commissionParams != nil {
+		err := retryMachineConflict(ctx, client, machine.SystemID, isMachineStatus("Commissioning", "Testing"), func() error {
+			_, err := client.Machine.Commission(machine.SystemID, commissionParams)
+			return err
+		})
+		if err != nil {
+			return diagFromErr(err)
+		}
+	}
+
 	// Wait for machine to be ready
-	_, err = waitForMachineStatus(ctx, client, machine.SystemID, []string{"Commissioning", "Testing"}, []string{"Ready"})
+	_, err = waitForMachineStatus(ctx, client, machine.SystemID, []string{"New", "Commissioning", "Testing"}, []string{"Ready"}, d.Timeout(schema.TimeoutCreate))
 	if err != nil {
-		return diag.FromErr(err)
+		return diagFromErr(getMachineScriptsError(m.(*ClientConfig), machine.SystemID, err))
 	}
 
 	// Return updated machine
@@ -139,56 +255,315 @@ func resourceMachineCreate(ctx context.Context, d *schema.ResourceData, m interf
 }
 
 func resourceMachineRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	client := m.(*client.Client)
+	client := m.(*ClientConfig).Client
 
 	// Get machine
 	machine, err := client.Machine.Get(d.Id())
 	if err != nil {
-		return diag.FromErr(err)
+		return diagFromErr(err)
+	}
+
+	tags, err := getMachineManualTags(client, machine)
+	if err != nil {
+		return diagFromErr(err)
 	}
 
 	// Set Terraform state
 	tfState := map[string]interface{}{
-		"architecture":   machine.Architecture,
-		"min_hwe_kernel": machine.MinHWEKernel,
-		"hostname":       machine.Hostname,
-		"domain":         machine.Domain.Name,
-		"zone":           machine.Zone.Name,
-		"pool":           machine.Pool.Name,
+		"tags":            tags,
+		"architecture":    machine.Architecture,
+		"min_hwe_kernel":  machine.MinHWEKernel,
+		"hostname":        machine.Hostname,
+		"domain":          machine.Domain.Name,
+		"zone":            machine.Zone.Name,
+		"pool":            machine.Pool.Name,
+		"locked":          machine.Locked,
+		"power_state":     machine.PowerState,
+		"default_gateway": getMachineDefaultGateway(machine, net.ParseIP(d.Get("default_gateway").(string))),
 	}
 	if err := setTerraformState(d, tfState); err != nil {
-		return diag.FromErr(err)
+		return diagFromErr(err)
 	}
 
 	return nil
 }
 
 func resourceMachineUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	client := m.(*client.Client)
+	client := m.(*ClientConfig).Client
 
 	// Update machine
 	machine, err := client.Machine.Get(d.Id())
 	if err != nil {
-		return diag.FromErr(err)
+		return diagFromErr(err)
+	}
+	if _, err := client.Machine.Update(machine.SystemID, getMachineParams(d, m.(*ClientConfig)), getMachinePowerParams(d)); err != nil {
+		return diagFromErr(err)
+	}
+	if !d.GetRawConfig().GetAttr("tags").IsNull() {
+		if err := setMachineTags(client, machine, convertToStringSlice(d.Get("tags").(*schema.Set).List())); err != nil {
+			return diagFromErr(err)
+		}
+	}
+	if !d.GetRawConfig().GetAttr("default_gateway").IsNull() {
+		if err := setMachineDefaultGateway(client, machine, d.Get("default_gateway").(string)); err != nil {
+			return diagFromErr(err)
+		}
+	}
+	if !d.GetRawConfig().GetAttr("locked").IsNull() {
+		if err := setMachineLocked(ctx, m.(*ClientConfig), machine, d.Get("locked").(bool)); err != nil {
+			return diagFromErr(err)
+		}
 	}
-	if _, err := client.Machine.Update(machine.SystemID, getMachineParams(d), getMachinePowerParams(d)); err != nil {
-		return diag.FromErr(err)
+	var diags diag.Diagnostics
+	if powerState := d.Get("desired_power_state").(string); powerState != "" {
+		timeout := d.Timeout(schema.TimeoutUpdate)
+		if d.IsNewResource() {
+			timeout = d.Timeout(schema.TimeoutCreate)
+		}
+		diags, err = setMachinePowerState(ctx, m.(*ClientConfig), machine.SystemID, powerState, timeout)
+		if err != nil {
+			return diagFromErr(err)
+		}
 	}
 
-	return resourceMachineRead(ctx, d, m)
+	return append(diags, resourceMachineRead(ctx, d, m)...)
+}
+
+func resourceMachineCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	// Plan an update when the machine was powered on or off out of band. The
+	// machines whose power type doesn't report the power state are skipped,
+	// and the new power state is read back from MAAS after the update.
+	powerState := d.Get("desired_power_state").(string)
+	currentState := d.Get("power_state").(string)
+	if d.Id() == "" || powerState == "" || currentState == powerState || currentState == "unknown" {
+		return nil
+	}
+	return d.SetNewComputed("power_state")
 }
 
 func resourceMachineDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	client := m.(*client.Client)
+	client := m.(*ClientConfig).Client
 
 	// Delete machine
+	machine, err := client.Machine.Get(d.Id())
+	if err != nil {
+		return diagFromErr(err)
+	}
+	// The machine is unlocked only if it's not meant to be locked
+	if d.Get("locked").(bool) {
+		if err := checkMachineNotLocked(machine); err != nil {
+			return diagFromErr(err)
+		}
+	} else if err := setMachineLocked(ctx, m.(*ClientConfig), machine, false); err != nil {
+		return diagFromErr(err)
+	}
+	if d.Get("on_destroy").(string) == "release" {
+		return releaseMachineOnDestroy(ctx, d, m, machine)
+	}
 	if err := client.Machine.Delete(d.Id()); err != nil {
-		return diag.FromErr(err)
+		return diagFromErr(err)
+	}
+
+	return nil
+}
+
+// releaseMachineOnDestroy releases the machine if it's in use, and keeps it
+// in MAAS.
+func releaseMachineOnDestroy(ctx context.Context, d *schema.ResourceData, m interface{}, machine *entity.Machine) diag.Diagnostics {
+	client := m.(*ClientConfig).Client
+
+	if !isMachineStatus("Allocated", "Deploying", "Deployed", "Failed deployment")(machine) {
+		return nil
+	}
+	err := retryMachineConflict(ctx, client, machine.SystemID, isMachineStatus("Releasing", "Disk erasing", "Ready"), func() error {
+		_, err := m.(*ClientConfig).Machine.Release(machine.SystemID, &MachineReleaseParams{})
+		return err
+	})
+	if err != nil {
+		return diagFromErr(err)
+	}
+	_, err = waitForMachineStatus(ctx, client, machine.SystemID, []string{"Releasing", "Disk erasing"}, []string{"Ready"}, d.Timeout(schema.TimeoutDelete))
+	if err != nil {
+		return diagFromErr(err)
+	}
+
+	return nil
+}
+
+// getMachineManualTags returns the machine tags, except the automatic ones
+// (tags with a definition), which are managed by MAAS.
+func getMachineManualTags(client *client.Client, machine *entity.Machine) ([]string, error) {
+	tags, err := client.Tags.Get()
+	if err != nil {
+		return nil, err
+	}
+	autoTags := map[string]bool{}
+	for _, t := range tags {
+		if t.Definition != "" {
+			autoTags[t.Name] = true
+		}
+	}
+	manualTags := []string{}
+	for _, t := range machine.TagNames {
+		if !autoTags[t] {
+			manualTags = append(manualTags, t)
+		}
 	}
+	return manualTags, nil
+}
 
+// setMachineTags adds and removes the machine manual tags, so that they match
+// the given tags.
+func setMachineTags(client *client.Client, machine *entity.Machine, tags []string) error {
+	currentTags, err := getMachineManualTags(client, machine)
+	if err != nil {
+		return err
+	}
+	current := map[string]bool{}
+	for _, t := range currentTags {
+		current[t] = true
+	}
+	wanted := map[string]bool{}
+	for _, t := range tags {
+		wanted[t] = true
+		if !current[t] {
+			if err := client.Tag.AddMachines(t, []string{machine.SystemID}); err != nil {
+				return err
+			}
+		}
+	}
+	for _, t := range currentTags {
+		if !wanted[t] {
+			if err := client.Tag.RemoveMachines(t, []string{machine.SystemID}); err != nil {
+				return err
+			}
+		}
+	}
 	return nil
 }
 
+// setMachineLocked locks or unlocks the machine, if it's not already in the
+// wanted state. MAAS only locks the deployed machines.
+func setMachineLocked(ctx context.Context, clientConfig *ClientConfig, machine *entity.Machine, locked bool) error {
+	if machine.Locked == locked {
+		return nil
+	}
+	if locked && !isMachineStatus("Deployed", "Deploying")(machine) {
+		return fmt.Errorf("machine (%s) can't be locked while its status is %q, MAAS only locks the deployed machines", machine.SystemID, machine.StatusName)
+	}
+	isLocked := func(machine *entity.Machine) bool {
+		return machine.Locked == locked
+	}
+	return retryMachineConflict(ctx, clientConfig.Client, machine.SystemID, isLocked, func() error {
+		if locked {
+			_, err := clientConfig.Client.Machine.Lock(machine.SystemID, "Locked by Terraform")
+			return err
+		}
+		_, err := clientConfig.Machine.Unlock(machine.SystemID, "Unlocked by Terraform")
+		return err
+	})
+}
+
+// getMachineDefaultGateway returns the machine default gateway of the same IP
+// family as the given IP address, or the IPv4 one if it's not set.
+func getMachineDefaultGateway(machine *entity.Machine, ip net.IP) string {
+	gatewayIP := machine.DefaultGateways.IPv4.GatewayIP
+	if ip != nil && ip.To4() == nil {
+		gatewayIP = machine.DefaultGateways.IPv6.GatewayIP
+	}
+	if gatewayIP == nil {
+		return ""
+	}
+	return gatewayIP.String()
+}
+
+// setMachineDefaultGateway sets the machine default gateway to the given IP
+// address. MAAS sets the default gateway on a network interface link, so the
+// IP address must be the gateway of one of the subnets the machine is linked to.
+func setMachineDefaultGateway(client *client.Client, machine *entity.Machine, gatewayIP string) error {
+	ip := net.ParseIP(gatewayIP)
+	if getMachineDefaultGateway(machine, ip) == ip.String() {
+		return nil
+	}
+	networkInterfaces, err := client.NetworkInterfaces.Get(machine.SystemID)
+	if err != nil {
+		return err
+	}
+	for _, n := range networkInterfaces {
+		for _, link := range n.Links {
+			if link.Subnet.GatewayIP.Equal(ip) {
+				_, err := client.NetworkInterface.SetDefaultGateway(machine.SystemID, n.ID, link.ID)
+				return err
+			}
+		}
+	}
+	return fmt.Errorf("default gateway (%s) is not the gateway IP of any subnet linked to the machine (%s) network interfaces", gatewayIP, machine.SystemID)
+}
+
+// checkMachineNotLocked returns an error if the machine is locked, since MAAS
+// rejects the release and the deletion of locked machines.
+func checkMachineNotLocked(machine *entity.Machine) error {
+	if machine.Locked {
+		return fmt.Errorf("machine (%s) is locked, set `locked = false` on its maas_machine resource (or unlock it in MAAS) before releasing or deleting it", machine.SystemID)
+	}
+	return nil
+}
+
+// setMachinePowerState powers the machine on or off, and waits until its BMC
+// reports the wanted power state. When the power type can't query the power
+// state, the wait is skipped with a warning.
+func setMachinePowerState(ctx context.Context, clientConfig *ClientConfig, systemID string, powerState string, timeout time.Duration) (diag.Diagnostics, error) {
+	currentState, err := clientConfig.Machine.QueryPowerState(systemID)
+	if err != nil {
+		return nil, err
+	}
+	if currentState == powerState {
+		return nil, nil
+	}
+	isPowered := func(machine *entity.Machine) bool {
+		return machine.PowerState == powerState
+	}
+	err = retryMachineConflict(ctx, clientConfig.Client, systemID, isPowered, func() error {
+		if powerState == "on" {
+			_, err := clientConfig.Machine.PowerOn(systemID, "Powered on by Terraform")
+			return err
+		}
+		_, err := clientConfig.Machine.PowerOff(systemID, "Powered off by Terraform")
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if currentState == "unknown" {
+		return diag.Diagnostics{
+			diag.Diagnostic{
+				Severity: diag.Warning,
+				Summary:  "Machine power state can't be checked",
+				Detail:   fmt.Sprintf("The power type of the machine (%s) doesn't report the power state, so Terraform didn't wait for it to be powered %s.", systemID, powerState),
+			},
+		}, nil
+	}
+	log.Printf("[DEBUG] Waiting for machine (%s) to be powered %s\n", systemID, powerState)
+	stateConf := &resource.StateChangeConf{
+		Pending: []string{currentState},
+		Target:  []string{powerState},
+		Refresh: func() (interface{}, string, error) {
+			state, err := clientConfig.Machine.QueryPowerState(systemID)
+			if err != nil {
+				return nil, "", err
+			}
+			return state, state, nil
+		},
+		Timeout:    timeout,
+		Delay:      5 * time.Second,
+		MinTimeout: 5 * time.Second,
+	}
+	if _, err := stateConf.WaitForStateContext(ctx); err != nil {
+		return nil, fmt.Errorf("machine (%s) was not powered %s: %w", systemID, powerState, err)
+	}
+	return nil, nil
+}
+
 func getMachinePowerParams(d *schema.ResourceData) map[string]string {
 	powerParams := d.Get("power_parameters").(map[string]interface{})
 	params := make(map[string]string, len(powerParams))
@@ -198,7 +573,7 @@ func getMachinePowerParams(d *schema.ResourceData) map[string]string {
 	return params
 }
 
-func getMachineParams(d *schema.ResourceData) *entity.MachineParams {
+func getMachineParams(d *schema.ResourceData, clientConfig *ClientConfig) *entity.MachineParams {
 	return &entity.MachineParams{
 		Commission:    true,
 		PowerType:     d.Get("power_type").(string),
@@ -206,9 +581,126 @@ func getMachineParams(d *schema.ResourceData) *entity.MachineParams {
 		Architecture:  d.Get("architecture").(string),
 		MinHWEKernel:  d.Get("min_hwe_kernel").(string),
 		Hostname:      d.Get("hostname").(string),
-		Domain:        d.Get("domain").(string),
-		Zone:          d.Get("zone").(string),
-		Pool:          d.Get("pool").(string),
+		Domain:        getStringOrDefault(d, "domain", clientConfig.DefaultDomain),
+		Zone:          getStringOrDefault(d, "zone", clientConfig.DefaultZone),
+		Pool:          getStringOrDefault(d, "pool", clientConfig.DefaultPool),
+	}
+}
+
+func getMachineCommissionParams(d *schema.ResourceData) (*entity.MachineCommissionParams, error) {
+	p, ok := d.GetOk("commissioning_params")
+	if !ok {
+		return nil, nil
+	}
+	commissionParams := p.(*schema.Set).List()[0].(map[string]interface{})
+	commissioningScripts, err := getMachineScripts(commissionParams, "commissioning_scripts")
+	if err != nil {
+		return nil, err
+	}
+	testingScripts, err := getMachineScripts(commissionParams, "testing_scripts")
+	if err != nil {
+		return nil, err
+	}
+	params := &entity.MachineCommissionParams{
+		CommissioningScripts: commissioningScripts,
+		TestingScripts:       testingScripts,
+	}
+	if commissionParams["enable_ssh"].(bool) {
+		params.EnableSSH = 1
+	}
+	if commissionParams["skip_bmc_config"].(bool) {
+		params.SkipBMCConfig = 1
+	}
+	if commissionParams["skip_networking"].(bool) {
+		params.SkipNetworking = 1
+	}
+	if commissionParams["skip_storage"].(bool) {
+		params.SkipStorage = 1
+	}
+	return params, nil
+}
+
+// getMachineScripts returns the comma separated list of the script names and
+// tags given in the commissioning config key.
+func getMachineScripts(commissionParams map[string]interface{}, key string) (string, error) {
+	scripts := convertToStringSlice(commissionParams[key])
+	for _, script := range scripts {
+		if script == "none" && len(scripts) > 1 {
+			return "", fmt.Errorf("%s: `none` can't be used along with other scripts", key)
+		}
+	}
+	return strings.Join(scripts, ","), nil
+}
+
+// getMachineScriptsError adds the failed scripts of the machine's current
+// commissioning and testing to the given error.
+func getMachineScriptsError(clientConfig *ClientConfig, systemID string, err error) error {
+	machine, getErr := clientConfig.Client.Machine.Get(systemID)
+	if getErr != nil {
+		return err
+	}
+	failedScripts := []string{}
+	for _, id := range []int{machine.CurrentCommissioningResultID, machine.CurrentTestingResultID} {
+		if id == 0 {
+			continue
+		}
+		resultSet, getErr := clientConfig.NodeScriptResults.Get(systemID, id)
+		if getErr != nil {
+			return err
+		}
+		for _, r := range resultSet.Results {
+			if strings.HasPrefix(r.StatusName, "Failed") || r.StatusName == "Timed out" {
+				failedScripts = append(failedScripts, fmt.Sprintf("%s (%s, exit status %d)", r.Name, r.StatusName, r.ExitStatus))
+			}
+		}
+	}
+	if len(failedScripts) == 0 {
+		return err
+	}
+	return fmt.Errorf("%w, failed scripts: %s", err, strings.Join(failedScripts, ", "))
+}
+
+// machineConflictTimeout is how long a machine operation is retried while MAAS
+// returns conflict errors.
+const machineConflictTimeout = 2 * time.Minute
+
+// retryMachineConflict runs the given machine operation, retrying it while
+// MAAS returns a conflict error, e.g. because of a concurrent operation or a
+// MAAS background task on the same machine. Before each retry the machine is
+// read again, and the operation is skipped when isDone reports the machine is
+// already in, or moving to, the wanted state.
+func retryMachineConflict(ctx context.Context, client *client.Client, systemID string, isDone func(*entity.Machine) bool, operation func() error) error {
+	return resource.RetryContext(ctx, machineConflictTimeout, func() *resource.RetryError {
+		err := operation()
+		if err == nil {
+			return nil
+		}
+		if !isConflictError(err) {
+			return resource.NonRetryableError(err)
+		}
+		machine, getErr := client.Machine.Get(systemID)
+		if getErr != nil {
+			return resource.NonRetryableError(getErr)
+		}
+		if isDone(machine) {
+			log.Printf("[DEBUG] Machine (%s) is already in the wanted state (%s), skipping the conflicting operation\n", systemID, machine.StatusName)
+			return nil
+		}
+		log.Printf("[DEBUG] Machine (%s) operation conflicts with its current state (%s), retrying: %s\n", systemID, machine.StatusName, err)
+		return resource.RetryableError(err)
+	})
+}
+
+// isMachineStatus returns a function checking if the machine status is one of
+// the given ones.
+func isMachineStatus(statuses ...string) func(*entity.Machine) bool {
+	return func(machine *entity.Machine) bool {
+		for _, status := range statuses {
+			if machine.StatusName == status {
+				return true
+			}
+		}
+		return false
 	}
 }
 
@@ -219,17 +711,23 @@ func getMachineStatusFunc(client *client.Client, systemId string) resource.State
 			return nil, "", err
 		}
 		log.Printf("[DEBUG] Machine (%s) status: %s\n", systemId, machine.StatusName)
+		if strings.HasPrefix(machine.StatusName, "Failed") {
+			return machine, machine.StatusName, fmt.Errorf("machine (%s) status is %q: %s", systemId, machine.StatusName, machine.StatusMessage)
+		}
 		return machine, machine.StatusName, nil
 	}
 }
 
-func waitForMachineStatus(ctx context.Context, client *client.Client, systemID string, pendingStates []string, targetStates []string) (*entity.Machine, error) {
+// waitForMachineStatus polls the machine until it reaches one of the target
+// statuses. Any of the MAAS "Failed ..." statuses (e.g. "Failed commissioning"
+// or "Failed deployment") stops the polling and is returned as an error.
+func waitForMachineStatus(ctx context.Context, client *client.Client, systemID string, pendingStates []string, targetStates []string, timeout time.Duration) (*entity.Machine, error) {
 	log.Printf("[DEBUG] Waiting for machine (%s) status to be one of %s\n", systemID, targetStates)
 	stateConf := &resource.StateChangeConf{
 		Pending:    pendingStates,
 		Target:     targetStates,
 		Refresh:    getMachineStatusFunc(client, systemID),
-		Timeout:    30 * time.Minute,
+		Timeout:    timeout,
 		Delay:      10 * time.Second,
 		MinTimeout: 3 * time.Second,
 	}
@@ -241,14 +739,40 @@ func waitForMachineStatus(ctx context.Context, client *client.Client, systemID s
 }
 
 func getMachine(client *client.Client, identifier string) (*entity.Machine, error) {
-	machines, err := client.Machines.Get()
+	machines, err := getMachinesForLookup(client)
 	if err != nil {
 		return nil, err
 	}
+	// An exact system ID match has priority over the other identifiers
 	for _, m := range machines {
-		if m.SystemID == identifier || m.Hostname == identifier || m.FQDN == identifier {
-			return &m, nil
+		if m.SystemID == identifier {
+			return client.Machine.Get(m.SystemID)
+		}
+	}
+	var matches []entity.Machine
+	for _, m := range machines {
+		if m.Hostname == identifier || m.FQDN == identifier || machineHasMACAddress(&m, identifier) {
+			matches = append(matches, m)
+		}
+	}
+	if len(matches) == 0 {
+		return nil, fmt.Errorf("machine (%s) not found", identifier)
+	}
+	if len(matches) > 1 {
+		candidates := make([]string, len(matches))
+		for i, m := range matches {
+			candidates[i] = fmt.Sprintf("%s (%s)", m.SystemID, m.FQDN)
+		}
+		return nil, fmt.Errorf("machine identifier (%s) is ambiguous, it matches: %s", identifier, strings.Join(candidates, ", "))
+	}
+	return client.Machine.Get(matches[0].SystemID)
+}
+
+func machineHasMACAddress(machine *entity.Machine, macAddress string) bool {
+	for _, n := range machine.InterfaceSet {
+		if n.MACAddress != "" && strings.EqualFold(n.MACAddress, macAddress) {
+			return true
 		}
 	}
-	return nil, fmt.Errorf("machine (%s) not found", identifier)
+	return false
 }
@@ -0,0 +1,701 @@
+package maas
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/maas/gomaasclient/client"
+	"github.com/maas/gomaasclient/entity"
+)
+
+var (
+	// The network interface types, in the order they need to be created
+	machineNetworkInterfaceTypes = []string{"physical", "bond", "bridge", "vlan"}
+)
+
+func resourceMaasMachineNetwork() *schema.Resource {
+	return &schema.Resource{
+		Description:   "Provides a resource to manage the whole network configuration of a MAAS machine.\n\n**NOTE:** This resource owns the machine network topology. The bond, bridge and VLAN interfaces which are not in the configuration are deleted, and the physical ones are disconnected. Don't use it together with the `maas_network_interface_physical` and `maas_network_interface_link` resources for the same machine.",
+		CreateContext: resourceMachineNetworkCreate,
+		ReadContext:   resourceMachineNetworkRead,
+		UpdateContext: resourceMachineNetworkUpdate,
+		DeleteContext: resourceMachineNetworkDelete,
+		CustomizeDiff: resourceMachineNetworkCustomizeDiff,
+		Importer: &schema.ResourceImporter{
+			StateContext: func(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+				machine, err := getMachine(m.(*ClientConfig).Client, d.Id())
+				if err != nil {
+					return nil, err
+				}
+				tfState := map[string]interface{}{
+					"id":         machine.SystemID,
+					"machine":    machine.SystemID,
+					"interfaces": []interface{}{},
+				}
+				if err := setTerraformState(d, tfState); err != nil {
+					return nil, err
+				}
+				return []*schema.ResourceData{d}, nil
+			},
+		},
+
+		Schema: map[string]*schema.Schema{
+			"machine": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The identifier (system ID, hostname, FQDN, or MAC address) of the machine.",
+			},
+			"interfaces": {
+				Type:        schema.TypeList,
+				Required:    true,
+				Description: "The network interfaces of the machine. Parameters defined below.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": {
+							Type:        schema.TypeString,
+							Required:    true,
+							Description: "The network interface name. MAAS names the `vlan` interfaces `<parent>.<vid>`, so this name must be used for them.",
+						},
+						"type": {
+							Type:             schema.TypeString,
+							Optional:         true,
+							Default:          "physical",
+							ValidateDiagFunc: validation.ToDiagFunc(validation.StringInSlice(machineNetworkInterfaceTypes, false)),
+							Description:      "The network interface type. Valid options are: `physical`, `bond`, `bridge`, and `vlan`. Defaults to `physical`.",
+						},
+						"mac_address": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Computed:    true,
+							Description: "The network interface MAC address. Existing physical interfaces are adopted by name or MAC address, and this is required only to create new ones. This is computed if it's not set.",
+						},
+						"parents": {
+							Type:     schema.TypeList,
+							Optional: true,
+							Elem: &schema.Schema{
+								Type: schema.TypeString,
+							},
+							Description: "The names of the parent network interfaces. Bond interfaces require at least one parent, and bridge and VLAN interfaces require exactly one.",
+						},
+						"vlan": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Computed:    true,
+							Description: "The ID of the VLAN the network interface is connected to. MAAS can't change the VLAN of a network interface linked to subnets, so all its links are removed before changing it, and the configured `links` are created again. This is required for the `vlan` interfaces, and computed if it's not set.",
+						},
+						"mtu": {
+							Type:        schema.TypeInt,
+							Optional:    true,
+							Computed:    true,
+							Description: "The network interface MTU. This is computed if it's not set.",
+						},
+						"bond_mode": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Computed:    true,
+							Description: "The bonding mode of the `bond` interfaces (e.g. `active-backup`, `802.3ad`). This is computed if it's not set.",
+						},
+						"accept_ra": {
+							Type:        schema.TypeBool,
+							Optional:    true,
+							Computed:    true,
+							Description: "Boolean value indicating if the network interface accepts IPv6 router advertisements. This is computed if it's not set.",
+						},
+						"bridge_type": {
+							Type:             schema.TypeString,
+							Optional:         true,
+							Computed:         true,
+							ValidateDiagFunc: validation.ToDiagFunc(validation.StringInSlice([]string{"standard", "ovs"}, false)),
+							Description:      "The type of the `bridge` interfaces. Valid options are: `standard` and `ovs` (Open vSwitch). This is computed if it's not set.",
+						},
+						"bridge_stp": {
+							Type:        schema.TypeBool,
+							Optional:    true,
+							Computed:    true,
+							Description: "Boolean value indicating if the spanning tree protocol is enabled on the `bridge` interfaces. This is supported only by the `standard` bridges, and computed if it's not set.",
+						},
+						"bridge_fd": {
+							Type:        schema.TypeInt,
+							Optional:    true,
+							Computed:    true,
+							Description: "The forward delay of the `bridge` interfaces, given in seconds. This is supported only by the `standard` bridges, and computed if it's not set.",
+						},
+						"links": {
+							Type:        schema.TypeList,
+							Optional:    true,
+							Description: "The subnet links of the network interface. Parameters defined below.",
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"subnet": {
+										Type:        schema.TypeString,
+										Required:    true,
+										Description: "The identifier (CIDR or ID) of the subnet to be connected.",
+									},
+									"mode": {
+										Type:             schema.TypeString,
+										Optional:         true,
+										Default:          "AUTO",
+										ValidateDiagFunc: validation.ToDiagFunc(validation.StringInSlice([]string{"AUTO", "DHCP", "STATIC", "LINK_UP"}, false)),
+										Description:      "Connection mode to subnet. Valid options are: `AUTO`, `DHCP`, `STATIC`, and `LINK_UP`. Defaults to `AUTO`.",
+									},
+									"ip_address": {
+										Type:             schema.TypeString,
+										Optional:         true,
+										Computed:         true,
+										ValidateDiagFunc: validation.ToDiagFunc(validation.IsIPAddress),
+										Description:      "Valid IP address (from the given subnet) to be configured. Only used when `mode` is set to `STATIC`. This is computed if it's not set.",
+									},
+									"default_gateway": {
+										Type:        schema.TypeBool,
+										Optional:    true,
+										Default:     false,
+										Description: "Boolean value. When enabled, the subnet gateway IP address is set as the machine default gateway. This option can only be used with the `AUTO` and `STATIC` modes. Defaults to `false`.",
+									},
+								},
+							},
+						},
+						"id": {
+							Type:        schema.TypeInt,
+							Computed:    true,
+							Description: "The network interface ID.",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func resourceMachineNetworkCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*ClientConfig).Client
+
+	machine, err := getMachine(client, d.Get("machine").(string))
+	if err != nil {
+		return diagFromErr(err)
+	}
+	d.SetId(machine.SystemID)
+
+	return resourceMachineNetworkUpdate(ctx, d, m)
+}
+
+func resourceMachineNetworkRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*ClientConfig).Client
+
+	networkInterfaces, err := client.NetworkInterfaces.Get(d.Id())
+	if err != nil {
+		if isNotFoundError(err) {
+			log.Printf("[DEBUG] Machine (%s) was not found, removing its network configuration from state\n", d.Id())
+			d.SetId("")
+			return nil
+		}
+		return diagFromErr(err)
+	}
+	byName := map[string]entity.NetworkInterface{}
+	for _, n := range networkInterfaces {
+		byName[n.Name] = n
+	}
+	interfaces := []map[string]interface{}{}
+	found := map[string]bool{}
+	for _, i := range d.Get("interfaces").([]interface{}) {
+		config := i.(map[string]interface{})
+		if n, ok := byName[config["name"].(string)]; ok {
+			interfaces = append(interfaces, getMachineNetworkInterfaceTFState(&n, config))
+			found[n.Name] = true
+		}
+	}
+	// The interfaces which are not in the configuration are shown as drift,
+	// except the disconnected physical interfaces
+	for _, n := range networkInterfaces {
+		if found[n.Name] || (n.Type == "physical" && len(n.Links) == 0) {
+			continue
+		}
+		interfaces = append(interfaces, getMachineNetworkInterfaceTFState(&n, nil))
+	}
+	if err := d.Set("interfaces", interfaces); err != nil {
+		return diagFromErr(err)
+	}
+
+	return nil
+}
+
+func resourceMachineNetworkUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*ClientConfig).Client
+
+	interfaces := d.Get("interfaces").([]interface{})
+	if err := validateMachineNetworkInterfaces(interfaces); err != nil {
+		return diagFromErr(err)
+	}
+	if err := removeMachineNetworkInterfaces(client, d.Id(), interfaces); err != nil {
+		return diagFromErr(err)
+	}
+	if err := setMachineNetworkInterfaces(client, d.Id(), interfaces, d.GetRawConfig().GetAttr("interfaces")); err != nil {
+		return diagFromErr(err)
+	}
+
+	return resourceMachineNetworkRead(ctx, d, m)
+}
+
+func resourceMachineNetworkDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*ClientConfig).Client
+
+	networkInterfaces, err := client.NetworkInterfaces.Get(d.Id())
+	if err != nil {
+		return diagFromErr(err)
+	}
+	managed := map[string]bool{}
+	for _, i := range d.Get("interfaces").([]interface{}) {
+		managed[i.(map[string]interface{})["name"].(string)] = true
+	}
+	var toDelete []entity.NetworkInterface
+	for _, n := range networkInterfaces {
+		if !managed[n.Name] {
+			continue
+		}
+		if n.Type == "physical" {
+			if _, err := client.NetworkInterface.Disconnect(d.Id(), n.ID); err != nil {
+				return diagFromErr(err)
+			}
+			continue
+		}
+		toDelete = append(toDelete, n)
+	}
+	if err := deleteMachineNetworkInterfaces(client, d.Id(), toDelete); err != nil {
+		return diagFromErr(err)
+	}
+
+	return nil
+}
+
+// resourceMachineNetworkCustomizeDiff checks that the bridge options are used
+// only with the bridge interfaces, and that the OVS bridges don't use the
+// options supported only by the standard bridges.
+func resourceMachineNetworkCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	rawInterfaces := d.GetRawConfig().GetAttr("interfaces")
+	if !rawInterfaces.IsKnown() || rawInterfaces.IsNull() {
+		return nil
+	}
+	for it := rawInterfaces.ElementIterator(); it.Next(); {
+		_, n := it.Element()
+		if !n.IsKnown() || n.IsNull() {
+			continue
+		}
+		name, interfaceType, bridgeType := n.GetAttr("name"), n.GetAttr("type"), n.GetAttr("bridge_type")
+		if !name.IsKnown() || !interfaceType.IsKnown() || !bridgeType.IsKnown() {
+			continue
+		}
+		isBridge := !interfaceType.IsNull() && interfaceType.AsString() == "bridge"
+		isOVS := !bridgeType.IsNull() && bridgeType.AsString() == "ovs"
+		for _, option := range []string{"bridge_type", "bridge_stp", "bridge_fd"} {
+			if !n.GetAttr(option).IsNull() && !isBridge {
+				return fmt.Errorf("%s can only be used with bridge network interfaces, used on (%s)", option, name.AsString())
+			}
+		}
+		for _, option := range []string{"bridge_stp", "bridge_fd"} {
+			if !n.GetAttr(option).IsNull() && isOVS {
+				return fmt.Errorf("%s is not supported by the ovs bridge (%s)", option, name.AsString())
+			}
+		}
+	}
+	return nil
+}
+
+func validateMachineNetworkInterfaces(interfaces []interface{}) error {
+	names := map[string]bool{}
+	for _, i := range interfaces {
+		names[i.(map[string]interface{})["name"].(string)] = true
+	}
+	for _, i := range interfaces {
+		n := i.(map[string]interface{})
+		name := n["name"].(string)
+		parents := convertToStringSlice(n["parents"])
+		switch n["type"].(string) {
+		case "physical":
+			if len(parents) > 0 {
+				return fmt.Errorf("physical network interface (%s) cannot have parents", name)
+			}
+		case "bond":
+			if len(parents) == 0 {
+				return fmt.Errorf("bond network interface (%s) requires at least one parent", name)
+			}
+		case "bridge", "vlan":
+			if len(parents) != 1 {
+				return fmt.Errorf("%s network interface (%s) requires exactly one parent", n["type"].(string), name)
+			}
+		}
+		if n["type"].(string) == "vlan" && n["vlan"].(string) == "" {
+			return fmt.Errorf("vlan network interface (%s) requires the vlan argument", name)
+		}
+		for _, p := range parents {
+			if !names[p] {
+				return fmt.Errorf("parent (%s) of the network interface (%s) is not in the configuration", p, name)
+			}
+		}
+	}
+	return nil
+}
+
+// removeMachineNetworkInterfaces tears down the machine network interfaces
+// which are not in the configuration. The bond, bridge and VLAN interfaces are
+// deleted, together with the ones whose type or parents changed (and their
+// children), so they can be recreated. The physical interfaces are disconnected.
+func removeMachineNetworkInterfaces(client *client.Client, systemID string, interfaces []interface{}) error {
+	networkInterfaces, err := client.NetworkInterfaces.Get(systemID)
+	if err != nil {
+		return err
+	}
+	wanted := map[string]map[string]interface{}{}
+	for _, i := range interfaces {
+		n := i.(map[string]interface{})
+		wanted[n["name"].(string)] = n
+	}
+	stale := map[string]bool{}
+	for _, n := range networkInterfaces {
+		w, ok := wanted[n.Name]
+		if n.Type == "physical" {
+			if !ok && (len(n.Links) > 0 || n.VLAN.ID != 0) {
+				if _, err := client.NetworkInterface.Disconnect(systemID, n.ID); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+		if !ok || w["type"].(string) != n.Type || !sameStringSet(convertToStringSlice(w["parents"]), n.Parents) {
+			stale[n.Name] = true
+		}
+	}
+	// The children of the deleted interfaces are deleted as well
+	for changed := true; changed; {
+		changed = false
+		for _, n := range networkInterfaces {
+			if !stale[n.Name] {
+				continue
+			}
+			for _, c := range n.Children {
+				if !stale[c] {
+					stale[c] = true
+					changed = true
+				}
+			}
+		}
+	}
+	var toDelete []entity.NetworkInterface
+	for _, n := range networkInterfaces {
+		if stale[n.Name] && n.Type != "physical" {
+			toDelete = append(toDelete, n)
+		}
+	}
+	return deleteMachineNetworkInterfaces(client, systemID, toDelete)
+}
+
+// deleteMachineNetworkInterfaces deletes the given network interfaces, the
+// children before their parents.
+func deleteMachineNetworkInterfaces(client *client.Client, systemID string, networkInterfaces []entity.NetworkInterface) error {
+	sort.SliceStable(networkInterfaces, func(i, j int) bool {
+		return getMachineNetworkInterfaceTypeOrder(networkInterfaces[i].Type) > getMachineNetworkInterfaceTypeOrder(networkInterfaces[j].Type)
+	})
+	for _, n := range networkInterfaces {
+		if err := client.NetworkInterface.Delete(systemID, n.ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// setMachineNetworkInterfaces creates (or adopts) and updates the configured
+// network interfaces, parents first, and then links them to their subnets.
+func setMachineNetworkInterfaces(client *client.Client, systemID string, interfaces []interface{}, rawInterfaces cty.Value) error {
+	networkInterfaces, err := client.NetworkInterfaces.Get(systemID)
+	if err != nil {
+		return err
+	}
+	byName := map[string]*entity.NetworkInterface{}
+	byMACAddress := map[string]*entity.NetworkInterface{}
+	for i := range networkInterfaces {
+		n := &networkInterfaces[i]
+		byName[n.Name] = n
+		if n.Type == "physical" {
+			byMACAddress[strings.ToLower(n.MACAddress)] = n
+		}
+	}
+	for _, t := range machineNetworkInterfaceTypes {
+		for index, i := range interfaces {
+			config := i.(map[string]interface{})
+			if config["type"].(string) != t {
+				continue
+			}
+			networkInterface, err := setMachineNetworkInterface(client, systemID, config, byName, byMACAddress)
+			if err != nil {
+				return err
+			}
+			// The flags are computed, so only the ones set in the config are sent
+			flags := networkInterfaceFlagsParams{}
+			if rawInterfaces.IsKnown() && !rawInterfaces.IsNull() {
+				if rawInterface := rawInterfaces.Index(cty.NumberIntVal(int64(index))); rawInterface.IsKnown() && !rawInterface.IsNull() {
+					flags.AcceptRA = getRawConfigBool(rawInterface.GetAttr("accept_ra"))
+					if t == "bridge" && config["bridge_type"].(string) != "ovs" {
+						flags.BridgeSTP = getRawConfigBool(rawInterface.GetAttr("bridge_stp"))
+					}
+				}
+			}
+			if flags.AcceptRA != nil || flags.BridgeSTP != nil {
+				if networkInterface, err = client.NetworkInterface.Update(systemID, networkInterface.ID, &flags); err != nil {
+					return err
+				}
+			}
+			byName[networkInterface.Name] = networkInterface
+		}
+	}
+	defaultGateways := map[int]int{}
+	for _, i := range interfaces {
+		config := i.(map[string]interface{})
+		networkInterface := byName[config["name"].(string)]
+		linkIDs, err := setMachineNetworkInterfaceLinks(client, systemID, networkInterface, config["links"].([]interface{}))
+		if err != nil {
+			return err
+		}
+		for j, l := range config["links"].([]interface{}) {
+			if l.(map[string]interface{})["default_gateway"].(bool) {
+				defaultGateways[networkInterface.ID] = linkIDs[j]
+			}
+		}
+	}
+	if len(defaultGateways) > 0 {
+		if _, err := client.Machine.ClearDefaultGateways(systemID); err != nil {
+			return err
+		}
+		for networkInterfaceID, linkID := range defaultGateways {
+			if _, err := client.NetworkInterface.SetDefaultGateway(systemID, networkInterfaceID, linkID); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func setMachineNetworkInterface(client *client.Client, systemID string, config map[string]interface{}, byName map[string]*entity.NetworkInterface, byMACAddress map[string]*entity.NetworkInterface) (*entity.NetworkInterface, error) {
+	name := config["name"].(string)
+	macAddress := config["mac_address"].(string)
+	parentIDs := []int{}
+	for _, p := range convertToStringSlice(config["parents"]) {
+		parentIDs = append(parentIDs, byName[p].ID)
+	}
+	physicalParams := entity.NetworkInterfacePhysicalParams{
+		Name:       name,
+		MACAddress: macAddress,
+		VLAN:       config["vlan"].(string),
+		MTU:        config["mtu"].(int),
+	}
+	existing := byName[name]
+	if existing == nil && config["type"].(string) == "physical" && macAddress != "" {
+		existing = byMACAddress[strings.ToLower(macAddress)]
+	}
+	// The configured links are created again after the VLAN change
+	if existing != nil && isLinkedNetworkInterfaceVLANChange(existing, config["vlan"].(string)) {
+		var err error
+		if existing, err = unlinkNetworkInterfaceSubnets(client, systemID, existing); err != nil {
+			return nil, err
+		}
+	}
+	switch config["type"].(string) {
+	case "physical":
+		if existing != nil {
+			return client.NetworkInterface.Update(systemID, existing.ID, &physicalParams)
+		}
+		if macAddress == "" {
+			return nil, fmt.Errorf("physical network interface (%s) was not found on machine (%s), and mac_address is required to create it", name, systemID)
+		}
+		return client.NetworkInterfaces.CreatePhysical(systemID, &physicalParams)
+	case "bond":
+		params := entity.NetworkInterfaceBondParams{
+			NetworkInterfacePhysicalParams: physicalParams,
+			Parents:                        parentIDs,
+			BondMode:                       config["bond_mode"].(string),
+		}
+		if existing != nil {
+			return client.NetworkInterface.Update(systemID, existing.ID, &params)
+		}
+		return client.NetworkInterfaces.CreateBond(systemID, &params)
+	case "bridge":
+		params := entity.NetworkInterfaceBridgeParams{
+			NetworkInterfacePhysicalParams: physicalParams,
+			Parent:                         parentIDs[0],
+			Bridgetype:                     config["bridge_type"].(string),
+			BridgeFD:                       config["bridge_fd"].(int),
+		}
+		if existing != nil {
+			return client.NetworkInterface.Update(systemID, existing.ID, &params)
+		}
+		return client.NetworkInterfaces.CreateBridge(systemID, &params)
+	}
+	params := entity.NetworkInterfaceVLANParams{
+		VLAN:   config["vlan"].(string),
+		Parent: parentIDs[0],
+		MTU:    config["mtu"].(int),
+	}
+	if existing != nil {
+		return client.NetworkInterface.Update(systemID, existing.ID, &params)
+	}
+	networkInterface, err := client.NetworkInterfaces.CreateVLAN(systemID, &params)
+	if err != nil {
+		return nil, err
+	}
+	if networkInterface.Name != name {
+		return nil, fmt.Errorf("vlan network interface (%s) was created by MAAS as (%s), use this name in the configuration", name, networkInterface.Name)
+	}
+	return networkInterface, nil
+}
+
+// setMachineNetworkInterfaceLinks replaces the network interface links, if
+// they differ from the configured ones. It returns the IDs of the links, in
+// the configuration order.
+func setMachineNetworkInterfaceLinks(client *client.Client, systemID string, networkInterface *entity.NetworkInterface, links []interface{}) ([]int, error) {
+	params := make([]*entity.NetworkInterfaceLinkParams, len(links))
+	for i, l := range links {
+		link := l.(map[string]interface{})
+		subnet, err := getSubnet(client, link["subnet"].(string))
+		if err != nil {
+			return nil, err
+		}
+		params[i] = &entity.NetworkInterfaceLinkParams{
+			Subnet:    subnet.ID,
+			Mode:      link["mode"].(string),
+			IPAddress: link["ip_address"].(string),
+		}
+	}
+	if !machineNetworkInterfaceLinksMatch(networkInterface.Links, params) {
+		for _, l := range networkInterface.Links {
+			if _, err := client.NetworkInterface.UnlinkSubnet(systemID, networkInterface.ID, l.ID); err != nil {
+				return nil, err
+			}
+		}
+		for _, p := range params {
+			if p.Mode != "STATIC" {
+				p.IPAddress = ""
+			}
+			n, err := client.NetworkInterface.LinkSubnet(systemID, networkInterface.ID, p)
+			if err != nil {
+				return nil, err
+			}
+			networkInterface = n
+		}
+	}
+	linkIDs := make([]int, len(params))
+	for i, p := range params {
+		for _, l := range networkInterface.Links {
+			if l.Subnet.ID == p.Subnet {
+				linkIDs[i] = l.ID
+			}
+		}
+	}
+	return linkIDs, nil
+}
+
+func machineNetworkInterfaceLinksMatch(links []entity.NetworkInterfaceLink, params []*entity.NetworkInterfaceLinkParams) bool {
+	if len(links) != len(params) {
+		return false
+	}
+	for i, p := range params {
+		if links[i].Subnet.ID != p.Subnet || !strings.EqualFold(links[i].Mode, p.Mode) {
+			return false
+		}
+		if p.Mode == "STATIC" && p.IPAddress != "" && links[i].IPAddress != p.IPAddress {
+			return false
+		}
+	}
+	return true
+}
+
+func sameStringSet(a []string, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	items := map[string]bool{}
+	for _, i := range a {
+		items[i] = true
+	}
+	for _, i := range b {
+		if !items[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func getMachineNetworkInterfaceTypeOrder(interfaceType string) int {
+	for i, t := range machineNetworkInterfaceTypes {
+		if t == interfaceType {
+			return i
+		}
+	}
+	return len(machineNetworkInterfaceTypes)
+}
+
+// getMachineNetworkInterfaceTFState returns the Terraform state of the network
+// interface. The link arguments which can't be read from MAAS are taken from
+// the given configuration, if any.
+func getMachineNetworkInterfaceTFState(networkInterface *entity.NetworkInterface, config map[string]interface{}) map[string]interface{} {
+	var configLinks []interface{}
+	if config != nil {
+		configLinks = config["links"].([]interface{})
+	}
+	links := []map[string]interface{}{}
+	for _, l := range networkInterface.Links {
+		if l.Subnet.ID == 0 {
+			continue
+		}
+		link := map[string]interface{}{
+			"subnet":          l.Subnet.CIDR,
+			"mode":            strings.ToUpper(l.Mode),
+			"ip_address":      l.IPAddress,
+			"default_gateway": false,
+		}
+		if i := len(links); i < len(configLinks) {
+			configLink := configLinks[i].(map[string]interface{})
+			if configLink["subnet"].(string) == fmt.Sprintf("%v", l.Subnet.ID) {
+				link["subnet"] = configLink["subnet"]
+			}
+			link["default_gateway"] = configLink["default_gateway"]
+		}
+		links = append(links, link)
+	}
+	vlan := ""
+	if networkInterface.VLAN.ID != 0 {
+		vlan = fmt.Sprintf("%v", networkInterface.VLAN.ID)
+	}
+	parents := networkInterface.Parents
+	if parents == nil {
+		parents = []string{}
+	}
+	bridgeType, _ := getNetworkInterfaceParam(networkInterface, "bridge_type").(string)
+	bridgeFD := networkInterface.BridgeFD
+	if v, ok := getNetworkInterfaceParam(networkInterface, "bridge_fd").(float64); ok {
+		bridgeFD = int(v)
+	}
+	bridgeSTP := networkInterface.BridgeSTP
+	if v, ok := getNetworkInterfaceParam(networkInterface, "bridge_stp").(bool); ok {
+		bridgeSTP = v
+	}
+	return map[string]interface{}{
+		"id":          networkInterface.ID,
+		"name":        networkInterface.Name,
+		"type":        networkInterface.Type,
+		"mac_address": networkInterface.MACAddress,
+		"parents":     parents,
+		"vlan":        vlan,
+		"mtu":         networkInterface.EffectiveMTU,
+		"bond_mode":   networkInterface.BondMode,
+		"accept_ra":   getNetworkInterfaceAcceptRA(networkInterface),
+		"bridge_type": bridgeType,
+		"bridge_stp":  bridgeSTP,
+		"bridge_fd":   bridgeFD,
+		"links":       links,
+	}
+}
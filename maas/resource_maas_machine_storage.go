@@ -0,0 +1,461 @@
+package maas
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/maas/gomaasclient/client"
+	"github.com/maas/gomaasclient/entity"
+)
+
+func resourceMaasMachineStorage() *schema.Resource {
+	fileSystemSchema := func(device string) map[string]*schema.Schema {
+		return map[string]*schema.Schema{
+			"fs_type": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Description: fmt.Sprintf("The file system type (e.g. `ext4`) the %s is formatted with. If this is not set, the %s is unformatted.", device, device),
+			},
+			"mount_point": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Description: fmt.Sprintf("The mount point of the %s file system. If this is not set, the file system is not mounted.", device),
+			},
+			"mount_options": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Description: fmt.Sprintf("The options used for the %s file system mount.", device),
+			},
+		}
+	}
+	partitionSchema := fileSystemSchema("partition")
+	partitionSchema["name"] = &schema.Schema{
+		Type:        schema.TypeString,
+		Required:    true,
+		ForceNew:    true,
+		Description: "The name used to reference the partition in the `devices` of the volume groups. It must be unique in the resource.",
+	}
+	partitionSchema["size"] = &schema.Schema{
+		Type:             schema.TypeString,
+		Required:         true,
+		ForceNew:         true,
+		ValidateDiagFunc: validation.ToDiagFunc(validateSize),
+		Description:      "The partition size as a human-readable string (e.g. `100G`, `1.5T`, `512M`).",
+	}
+	partitionSchema["bootable"] = &schema.Schema{
+		Type:        schema.TypeBool,
+		Optional:    true,
+		ForceNew:    true,
+		Description: "Boolean value indicating if the partition is set as bootable.",
+	}
+	diskSchema := fileSystemSchema("block device")
+	diskSchema["name"] = &schema.Schema{
+		Type:        schema.TypeString,
+		Required:    true,
+		ForceNew:    true,
+		Description: "The identifier (ID, name, ID path, or path) of the block device.",
+	}
+	diskSchema["partition"] = &schema.Schema{
+		Type:        schema.TypeList,
+		Optional:    true,
+		ForceNew:    true,
+		Description: "The partitions created on the block device, in order. Parameters defined below.",
+		Elem: &schema.Resource{
+			Schema: partitionSchema,
+		},
+	}
+	logicalVolumeSchema := fileSystemSchema("logical volume")
+	logicalVolumeSchema["name"] = &schema.Schema{
+		Type:        schema.TypeString,
+		Required:    true,
+		ForceNew:    true,
+		Description: "The logical volume name.",
+	}
+	logicalVolumeSchema["size"] = &schema.Schema{
+		Type:             schema.TypeString,
+		Required:         true,
+		ForceNew:         true,
+		ValidateDiagFunc: validation.ToDiagFunc(validateSize),
+		Description:      "The logical volume size as a human-readable string (e.g. `100G`, `1.5T`, `512M`).",
+	}
+
+	return &schema.Resource{
+		Description:   "Provides a resource to describe the whole storage configuration of a MAAS machine: the block devices, their partitions, the LVM volume groups and logical volumes, and their file systems. They are created in dependency order, and any change replaces the whole configuration.\n\n**NOTE:** This resource must not be used together with the other storage resources of the same machine. The machine must be `Ready` or `Allocated`.",
+		CreateContext: resourceMachineStorageCreate,
+		ReadContext:   resourceMachineStorageRead,
+		DeleteContext: resourceMachineStorageDelete,
+		CustomizeDiff: resourceMachineStorageCustomizeDiff,
+
+		Schema: map[string]*schema.Schema{
+			"machine": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The identifier (system ID, hostname, FQDN, or MAC address) of the machine.",
+			},
+			"wipe": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				ForceNew:    true,
+				Default:     false,
+				Description: "Boolean value indicating if the current storage configuration of the machine, including the one not described by this resource, is removed (by applying the `blank` storage layout) before the described one is created. Defaults to `false`.",
+			},
+			"disk": {
+				Type:        schema.TypeList,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The block devices of the machine, which are either partitioned or formatted. Parameters defined below.",
+				Elem: &schema.Resource{
+					Schema: diskSchema,
+				},
+			},
+			"volume_group": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				ForceNew:    true,
+				Description: "The LVM volume groups created on the block devices and partitions. Parameters defined below.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": {
+							Type:        schema.TypeString,
+							Required:    true,
+							ForceNew:    true,
+							Description: "The volume group name.",
+						},
+						"devices": {
+							Type:     schema.TypeList,
+							Required: true,
+							ForceNew: true,
+							Elem: &schema.Schema{
+								Type: schema.TypeString,
+							},
+							Description: "The physical volumes of the volume group, given by the `name` of a `disk` (which must not be partitioned) or of a `partition`.",
+						},
+						"logical_volume": {
+							Type:        schema.TypeList,
+							Optional:    true,
+							ForceNew:    true,
+							Description: "The logical volumes created in the volume group. Parameters defined below.",
+							Elem: &schema.Resource{
+								Schema: logicalVolumeSchema,
+							},
+						},
+					},
+				},
+			},
+			"partition_ids": {
+				Type:     schema.TypeMap,
+				Computed: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+				Description: "The MAAS IDs of the created partitions, by partition name.",
+			},
+			"volume_group_ids": {
+				Type:     schema.TypeMap,
+				Computed: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+				Description: "The MAAS IDs of the created volume groups, by volume group name.",
+			},
+			"logical_volume_ids": {
+				Type:     schema.TypeMap,
+				Computed: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+				Description: "The block device IDs of the created logical volumes, by `<volume group name>/<logical volume name>`.",
+			},
+		},
+	}
+}
+
+func resourceMachineStorageCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	clientConfig := m.(*ClientConfig)
+	client := clientConfig.Client
+
+	// The configuration is checked again, since some of its values may have
+	// been unknown during the plan
+	if err := validateMachineStorage(d.Get("disk").([]interface{}), d.Get("volume_group").([]interface{})); err != nil {
+		return diagFromErr(err)
+	}
+	machine, err := getMachine(client, d.Get("machine").(string))
+	if err != nil {
+		return diagFromErr(err)
+	}
+	if d.Get("wipe").(bool) {
+		if _, err := clientConfig.Machine.SetStorageLayout(machine.SystemID, &MachineStorageLayoutParams{StorageLayout: "blank"}); err != nil {
+			return diagFromErr(err)
+		}
+	}
+	d.SetId(machine.SystemID)
+
+	// The created objects are saved to the state as they're created, so they
+	// can be removed if a later step fails
+	partitionIDs := map[string]string{}
+	volumeGroupIDs := map[string]string{}
+	logicalVolumeIDs := map[string]string{}
+	saveState := func() error {
+		return setTerraformState(d, map[string]interface{}{
+			"partition_ids":      partitionIDs,
+			"volume_group_ids":   volumeGroupIDs,
+			"logical_volume_ids": logicalVolumeIDs,
+		})
+	}
+
+	// Block devices and partitions
+	blockDeviceIDs := map[string]int{}
+	partitionDevices := map[string]int{}
+	for _, disk := range d.Get("disk").([]interface{}) {
+		disk := disk.(map[string]interface{})
+		blockDevice, err := getBlockDevice(client, machine.SystemID, disk["name"].(string))
+		if err != nil {
+			return diagFromErr(err)
+		}
+		blockDeviceIDs[disk["name"].(string)] = blockDevice.ID
+		device := &filesystemDevice{SystemID: machine.SystemID, BlockDeviceID: blockDevice.ID}
+		if err := formatAndMountMachineStorageDevice(client, device, disk); err != nil {
+			return diagFromErr(err)
+		}
+		for _, part := range disk["partition"].([]interface{}) {
+			part := part.(map[string]interface{})
+			name := part["name"].(string)
+			if _, ok := partitionDevices[name]; ok {
+				return diagFromErr(fmt.Errorf("partition name (%s) is not unique", name))
+			}
+			size, err := parseSize(part["size"].(string))
+			if err != nil {
+				return diagFromErr(err)
+			}
+			params := &entity.BlockDevicePartitionParams{
+				Size:     int(roundSize(size, int64(blockDevice.BlockSize))),
+				Bootable: part["bootable"].(bool),
+			}
+			partition, err := client.BlockDevicePartitions.Create(machine.SystemID, blockDevice.ID, params)
+			if err != nil {
+				return diagFromErr(err)
+			}
+			partitionDevices[name] = partition.ID
+			partitionIDs[name] = fmt.Sprintf("%v", partition.ID)
+			if err := saveState(); err != nil {
+				return diagFromErr(err)
+			}
+			device := &filesystemDevice{SystemID: machine.SystemID, BlockDeviceID: blockDevice.ID, PartitionID: partition.ID}
+			if err := formatAndMountMachineStorageDevice(client, device, part); err != nil {
+				return diagFromErr(err)
+			}
+		}
+	}
+
+	// Volume groups and logical volumes
+	for _, vg := range d.Get("volume_group").([]interface{}) {
+		vg := vg.(map[string]interface{})
+		params := &VolumeGroupParams{
+			Name: vg["name"].(string),
+		}
+		for _, name := range vg["devices"].([]interface{}) {
+			if id, ok := partitionDevices[name.(string)]; ok {
+				params.Partitions = append(params.Partitions, id)
+			} else if id, ok := blockDeviceIDs[name.(string)]; ok {
+				params.BlockDevices = append(params.BlockDevices, id)
+			} else {
+				return diagFromErr(fmt.Errorf("volume group (%s) device (%s) is not the name of a disk or a partition", params.Name, name))
+			}
+		}
+		volumeGroup, err := clientConfig.VolumeGroups.Create(machine.SystemID, params)
+		if err != nil {
+			return diagFromErr(err)
+		}
+		volumeGroupIDs[volumeGroup.Name] = fmt.Sprintf("%v", volumeGroup.ID)
+		if err := saveState(); err != nil {
+			return diagFromErr(err)
+		}
+		for _, lv := range vg["logical_volume"].([]interface{}) {
+			lv := lv.(map[string]interface{})
+			size, err := parseSize(lv["size"].(string))
+			if err != nil {
+				return diagFromErr(err)
+			}
+			logicalVolumeParams := &LogicalVolumeParams{
+				Name: lv["name"].(string),
+				Size: size,
+			}
+			blockDevice, err := clientConfig.VolumeGroups.CreateLogicalVolume(machine.SystemID, volumeGroup.ID, logicalVolumeParams)
+			if err != nil {
+				return diagFromErr(err)
+			}
+			logicalVolumeIDs[fmt.Sprintf("%s/%s", volumeGroup.Name, logicalVolumeParams.Name)] = fmt.Sprintf("%v", blockDevice.ID)
+			if err := saveState(); err != nil {
+				return diagFromErr(err)
+			}
+			device := &filesystemDevice{SystemID: machine.SystemID, BlockDeviceID: blockDevice.ID}
+			if err := formatAndMountMachineStorageDevice(client, device, lv); err != nil {
+				return diagFromErr(err)
+			}
+		}
+	}
+	if err := saveState(); err != nil {
+		return diagFromErr(err)
+	}
+
+	return resourceMachineStorageRead(ctx, d, m)
+}
+
+func resourceMachineStorageCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	return validateMachineStorage(d.Get("disk").([]interface{}), d.Get("volume_group").([]interface{}))
+}
+
+// validateMachineStorage checks the storage configuration combinations which
+// MAAS would reject in the middle of the creation: a disk both formatted and
+// partitioned, and a partitioned disk used as a volume group device. The
+// unknown (empty) values are skipped.
+func validateMachineStorage(disks []interface{}, volumeGroups []interface{}) error {
+	partitioned := map[string]bool{}
+	for _, disk := range disks {
+		disk, ok := disk.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		name := disk["name"].(string)
+		if len(disk["partition"].([]interface{})) == 0 {
+			continue
+		}
+		if disk["fs_type"].(string) != "" {
+			return fmt.Errorf("disk (%s) can't be both formatted and partitioned, set fs_type on its partitions instead", name)
+		}
+		if name != "" {
+			partitioned[name] = true
+		}
+	}
+	for _, vg := range volumeGroups {
+		vg, ok := vg.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		for _, name := range vg["devices"].([]interface{}) {
+			if name, ok := name.(string); ok && partitioned[name] {
+				return fmt.Errorf("volume group (%s) device (%s) is a partitioned disk, use one of its partitions instead", vg["name"].(string), name)
+			}
+		}
+	}
+	return nil
+}
+
+func resourceMachineStorageRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	clientConfig := m.(*ClientConfig)
+
+	blockDevices, err := clientConfig.Client.BlockDevices.Get(d.Id())
+	if err != nil {
+		return diagFromErr(err)
+	}
+	volumeGroups, err := clientConfig.VolumeGroups.Get(d.Id())
+	if err != nil {
+		return diagFromErr(err)
+	}
+	// The storage configuration is replaced if any of the created objects was
+	// removed outside of Terraform
+	existing := map[string]bool{}
+	for _, b := range blockDevices {
+		existing[fmt.Sprintf("block_device:%v", b.ID)] = true
+		for _, p := range b.Partitions {
+			existing[fmt.Sprintf("partition:%v", p.ID)] = true
+		}
+	}
+	for _, v := range volumeGroups {
+		existing[fmt.Sprintf("volume_group:%v", v.ID)] = true
+	}
+	ids := map[string]string{
+		"partition_ids":      "partition",
+		"volume_group_ids":   "volume_group",
+		"logical_volume_ids": "block_device",
+	}
+	for k, kind := range ids {
+		for name, id := range d.Get(k).(map[string]interface{}) {
+			if !existing[fmt.Sprintf("%s:%v", kind, id)] {
+				log.Printf("[DEBUG] Storage object (%s) of machine (%s) was not found, removing the storage configuration from state\n", name, d.Id())
+				d.SetId("")
+				return nil
+			}
+		}
+	}
+
+	return nil
+}
+
+func resourceMachineStorageDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	clientConfig := m.(*ClientConfig)
+	client := clientConfig.Client
+
+	// The volume groups are deleted with their logical volumes, before the
+	// partitions they use
+	for _, id := range d.Get("volume_group_ids").(map[string]interface{}) {
+		volumeGroupID, err := strconv.Atoi(id.(string))
+		if err != nil {
+			return diagFromErr(err)
+		}
+		if err := clientConfig.VolumeGroups.Delete(d.Id(), volumeGroupID); err != nil {
+			return diagFromErr(err)
+		}
+	}
+	blockDevices, err := client.BlockDevices.Get(d.Id())
+	if err != nil {
+		return diagFromErr(err)
+	}
+	partitionIDs := map[string]bool{}
+	for _, id := range d.Get("partition_ids").(map[string]interface{}) {
+		partitionIDs[id.(string)] = true
+	}
+	for _, disk := range d.Get("disk").([]interface{}) {
+		disk := disk.(map[string]interface{})
+		for _, b := range blockDevices {
+			if !isBlockDeviceIdentifier(&b, disk["name"].(string)) {
+				continue
+			}
+			for _, p := range b.Partitions {
+				if !partitionIDs[fmt.Sprintf("%v", p.ID)] {
+					continue
+				}
+				device := &filesystemDevice{SystemID: d.Id(), BlockDeviceID: b.ID, PartitionID: p.ID, FileSystem: p.FileSystem}
+				if err := device.unmountAndUnformat(client); err != nil {
+					return diagFromErr(err)
+				}
+				if err := client.BlockDevicePartition.Delete(d.Id(), b.ID, p.ID); err != nil {
+					return diagFromErr(err)
+				}
+			}
+			if disk["fs_type"].(string) != "" {
+				device := &filesystemDevice{SystemID: d.Id(), BlockDeviceID: b.ID, FileSystem: b.Filesystem}
+				if err := device.unmountAndUnformat(client); err != nil {
+					return diagFromErr(err)
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// formatAndMountMachineStorageDevice formats and mounts the given device as
+// configured by its file system attributes.
+func formatAndMountMachineStorageDevice(client *client.Client, device *filesystemDevice, config map[string]interface{}) error {
+	fsType := config["fs_type"].(string)
+	if fsType == "" {
+		return nil
+	}
+	if err := device.format(client, fsType); err != nil {
+		return err
+	}
+	if mountPoint := config["mount_point"].(string); mountPoint != "" {
+		return device.mount(client, mountPoint, config["mount_options"].(string))
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+package maas
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateMachineStorage(t *testing.T) {
+	disk := func(name string, fsType string, partitions ...string) map[string]interface{} {
+		parts := []interface{}{}
+		for _, p := range partitions {
+			parts = append(parts, map[string]interface{}{"name": p})
+		}
+		return map[string]interface{}{"name": name, "fs_type": fsType, "partition": parts}
+	}
+	volumeGroup := func(name string, devices ...interface{}) map[string]interface{} {
+		return map[string]interface{}{"name": name, "devices": devices}
+	}
+	testCases := []struct {
+		name         string
+		disks        []interface{}
+		volumeGroups []interface{}
+		out          error
+	}{
+		{
+			name:         "formatted disk and partitioned disk used by its partitions",
+			disks:        []interface{}{disk("sda", "ext4"), disk("sdb", "", "sdb-part1")},
+			volumeGroups: []interface{}{volumeGroup("vg0", "sdb-part1")},
+			out:          nil,
+		},
+		{
+			name:         "unpartitioned disk used as volume group device",
+			disks:        []interface{}{disk("sda", "")},
+			volumeGroups: []interface{}{volumeGroup("vg0", "sda")},
+			out:          nil,
+		},
+		{
+			name:  "formatted and partitioned disk",
+			disks: []interface{}{disk("sda", "ext4", "sda-part1")},
+			out:   fmt.Errorf("disk (sda) can't be both formatted and partitioned, set fs_type on its partitions instead"),
+		},
+		{
+			name:         "partitioned disk used as volume group device",
+			disks:        []interface{}{disk("sda", "", "sda-part1")},
+			volumeGroups: []interface{}{volumeGroup("vg0", "sda-part1", "sda")},
+			out:          fmt.Errorf("volume group (vg0) device (sda) is a partitioned disk, use one of its partitions instead"),
+		},
+		{
+			name:         "unknown disk name",
+			disks:        []interface{}{disk("", "", "part1")},
+			volumeGroups: []interface{}{volumeGroup("vg0", "")},
+			out:          nil,
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			out := validateMachineStorage(testCase.disks, testCase.volumeGroups)
+			assert.Equal(t, testCase.out, out, fmt.Sprintf("validateMachineStorage(%s)", testCase.name))
+		})
+	}
+}
@@ -0,0 +1,187 @@
+package maas
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/maas/gomaasclient/client"
+)
+
+func resourceMaasMachineTags() *schema.Resource {
+	return &schema.Resource{
+		Description:   "Provides a resource to attach a set of tags to an existing MAAS machine. Only the tags in the set are managed, so other tags of the machine (e.g. managed by other Terraform configurations) are left untouched.\n\n**NOTE:** The same tag membership must not be managed also by the `tags` argument of `maas_machine`, or the `machines` argument of `maas_tag`, since they detach the tags missing from their configuration.",
+		CreateContext: resourceMachineTagsCreate,
+		ReadContext:   resourceMachineTagsRead,
+		UpdateContext: resourceMachineTagsUpdate,
+		DeleteContext: resourceMachineTagsDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: func(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+				idParts := splitMachineTagsImportID(d.Id())
+				if idParts[0] == "" || (len(idParts) == 2 && idParts[1] == "") {
+					return nil, fmt.Errorf("unexpected format of ID (%q), expected MACHINE or MACHINE:TAGS, where MACHINE is a system ID, hostname, FQDN, or MAC address, and TAGS is a comma separated list of tag names", d.Id())
+				}
+				client := m.(*ClientConfig).Client
+				machine, err := getMachine(client, idParts[0])
+				if err != nil {
+					return nil, err
+				}
+				var tags []string
+				if len(idParts) == 2 {
+					tags = strings.Split(idParts[1], ",")
+				} else {
+					// Without the tag names, all the manual tags of the
+					// machine are managed
+					tags, err = getMachineManualTags(client, machine)
+					if err != nil {
+						return nil, err
+					}
+				}
+				tfState := map[string]interface{}{
+					"id":      machine.SystemID,
+					"machine": machine.SystemID,
+					"tags":    tags,
+				}
+				if err := setTerraformState(d, tfState); err != nil {
+					return nil, err
+				}
+				return []*schema.ResourceData{d}, nil
+			},
+		},
+
+		Schema: map[string]*schema.Schema{
+			"machine": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The identifier (system ID, hostname, FQDN, or MAC address) of the machine the tags are attached to.",
+			},
+			"tags": {
+				Type:     schema.TypeSet,
+				Required: true,
+				MinItems: 1,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+				Description: "A set of names of existing manual tags (the ones without a definition) attached to the machine. The tags removed from the set are detached from the machine.",
+			},
+		},
+	}
+}
+
+func resourceMachineTagsCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*ClientConfig).Client
+
+	machine, err := getMachine(client, d.Get("machine").(string))
+	if err != nil {
+		return diagFromErr(err)
+	}
+	tags := convertToStringSlice(d.Get("tags").(*schema.Set).List())
+	if err := updateMachineTagsMembership(client, machine.SystemID, tags, nil); err != nil {
+		return diagFromErr(err)
+	}
+	d.SetId(machine.SystemID)
+
+	return resourceMachineTagsRead(ctx, d, m)
+}
+
+func resourceMachineTagsRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*ClientConfig).Client
+
+	machine, err := client.Machine.Get(d.Id())
+	if err != nil {
+		if isNotFoundError(err) {
+			log.Printf("[DEBUG] Machine (%s) was not found, removing its tags from state\n", d.Id())
+			d.SetId("")
+			return nil
+		}
+		return diagFromErr(err)
+	}
+	// Only the managed tags still attached to the machine are kept, so the
+	// detached ones are attached again
+	attached := map[string]bool{}
+	for _, t := range machine.TagNames {
+		attached[t] = true
+	}
+	tags := []string{}
+	for _, t := range convertToStringSlice(d.Get("tags").(*schema.Set).List()) {
+		if attached[t] {
+			tags = append(tags, t)
+		}
+	}
+	if err := d.Set("tags", tags); err != nil {
+		return diagFromErr(err)
+	}
+
+	return nil
+}
+
+func resourceMachineTagsUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*ClientConfig).Client
+
+	o, n := d.GetChange("tags")
+	removed := convertToStringSlice(o.(*schema.Set).Difference(n.(*schema.Set)).List())
+	added := convertToStringSlice(n.(*schema.Set).Difference(o.(*schema.Set)).List())
+	if err := updateMachineTagsMembership(client, d.Id(), added, removed); err != nil {
+		return diagFromErr(err)
+	}
+
+	return resourceMachineTagsRead(ctx, d, m)
+}
+
+func resourceMachineTagsDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*ClientConfig).Client
+
+	tags := convertToStringSlice(d.Get("tags").(*schema.Set).List())
+	if err := updateMachineTagsMembership(client, d.Id(), nil, tags); err != nil {
+		return diagFromErr(err)
+	}
+
+	return nil
+}
+
+// splitMachineTagsImportID splits the import ID into the machine identifier
+// and, if given, the tag names. The machine identifier may be a MAC address,
+// so the tag names are the part after the last colon, since tag names never
+// contain colons.
+func splitMachineTagsImportID(id string) []string {
+	if _, err := net.ParseMAC(id); err == nil {
+		return []string{id}
+	}
+	i := strings.LastIndex(id, ":")
+	if i < 0 {
+		return []string{id}
+	}
+	return []string{id[:i], id[i+1:]}
+}
+
+// updateMachineTagsMembership attaches the added tags to the machine, and
+// detaches the removed ones from it. The tags which were deleted are skipped
+// when they are detached.
+func updateMachineTagsMembership(client *client.Client, systemID string, added []string, removed []string) error {
+	for _, t := range added {
+		if _, err := getTag(client, t); err != nil {
+			return err
+		}
+		if err := client.Tag.AddMachines(t, []string{systemID}); err != nil {
+			return err
+		}
+	}
+	for _, t := range removed {
+		tag, err := findTag(client, t)
+		if err != nil {
+			return err
+		}
+		if tag == nil {
+			continue
+		}
+		if err := client.Tag.RemoveMachines(t, []string{systemID}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,29 @@
+package maas
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSplitMachineTagsImportID(t *testing.T) {
+	testCases := []struct {
+		in  string
+		out []string
+	}{
+		{"abc123", []string{"abc123"}},
+		{"abc123:web,db", []string{"abc123", "web,db"}},
+		{"node1.maas:web", []string{"node1.maas", "web"}},
+		{"52:54:00:12:34:56", []string{"52:54:00:12:34:56"}},
+		{"52:54:00:12:34:56:web,db", []string{"52:54:00:12:34:56", "web,db"}},
+		{"abc123:", []string{"abc123", ""}},
+		{":web", []string{"", "web"}},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.in, func(t *testing.T) {
+			out := splitMachineTagsImportID(testCase.in)
+			assert.Equal(t, testCase.out, out, fmt.Sprintf("splitMachineTagsImportID(%q)", testCase.in))
+		})
+	}
+}
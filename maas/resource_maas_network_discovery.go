@@ -0,0 +1,103 @@
+package maas
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+const networkDiscoveryID = "network_discovery"
+
+var (
+	// Active discovery intervals (in seconds) accepted by MAAS.
+	activeDiscoveryIntervals = []int{0, 600, 1800, 3600, 10800, 21600, 43200, 86400, 604800}
+)
+
+func resourceMaasNetworkDiscovery() *schema.Resource {
+	return &schema.Resource{
+		Description:   "Provides a resource to manage the MAAS network discovery settings.\n\n**NOTE:** Network discovery is a MAAS global setting. Destroying this resource re-enables network discovery and restores the default active discovery interval.",
+		CreateContext: resourceNetworkDiscoveryUpdate,
+		ReadContext:   resourceNetworkDiscoveryRead,
+		UpdateContext: resourceNetworkDiscoveryUpdate,
+		DeleteContext: resourceNetworkDiscoveryDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: func(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+				d.SetId(networkDiscoveryID)
+				return []*schema.ResourceData{d}, nil
+			},
+		},
+
+		Schema: map[string]*schema.Schema{
+			"network_discovery": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Default:          "enabled",
+				ValidateDiagFunc: validation.ToDiagFunc(validation.StringInSlice([]string{"enabled", "disabled"}, false)),
+				Description:      "Whether MAAS passively observes the networks to discover devices and neighbours. Valid options are: `enabled`, and `disabled`. Defaults to `enabled`.",
+			},
+			"active_discovery_interval": {
+				Type:             schema.TypeInt,
+				Optional:         true,
+				Default:          10800,
+				ValidateDiagFunc: validation.ToDiagFunc(validation.IntInSlice(activeDiscoveryIntervals)),
+				Description:      "The interval (in seconds) at which MAAS actively scans the subnets with active mapping enabled. Valid options are: `0` (never), `600`, `1800`, `3600`, `10800`, `21600`, `43200`, `86400`, and `604800`. Defaults to `10800`.",
+			},
+		},
+	}
+}
+
+func resourceNetworkDiscoveryRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	maasServer := m.(*ClientConfig).MAASServer
+
+	networkDiscovery, err := getMAASConfigValue(maasServer, "network_discovery")
+	if err != nil {
+		return diagFromErr(err)
+	}
+	activeDiscoveryInterval, err := getMAASConfigValue(maasServer, "active_discovery_interval")
+	if err != nil {
+		return diagFromErr(err)
+	}
+	interval, err := strconv.Atoi(activeDiscoveryInterval)
+	if err != nil {
+		return diagFromErr(err)
+	}
+	tfState := map[string]interface{}{
+		"network_discovery":         networkDiscovery,
+		"active_discovery_interval": interval,
+	}
+	if err := setTerraformState(d, tfState); err != nil {
+		return diagFromErr(err)
+	}
+
+	return nil
+}
+
+func resourceNetworkDiscoveryUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	maasServer := m.(*ClientConfig).MAASServer
+
+	if err := maasServer.Post("network_discovery", d.Get("network_discovery").(string)); err != nil {
+		return diagFromErr(err)
+	}
+	if err := maasServer.Post("active_discovery_interval", strconv.Itoa(d.Get("active_discovery_interval").(int))); err != nil {
+		return diagFromErr(err)
+	}
+	d.SetId(networkDiscoveryID)
+
+	return resourceNetworkDiscoveryRead(ctx, d, m)
+}
+
+func resourceNetworkDiscoveryDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	maasServer := m.(*ClientConfig).MAASServer
+
+	if err := maasServer.Post("network_discovery", maasConfigSettings["network_discovery"].Default); err != nil {
+		return diagFromErr(err)
+	}
+	if err := maasServer.Post("active_discovery_interval", maasConfigSettings["active_discovery_interval"].Default); err != nil {
+		return diagFromErr(err)
+	}
+
+	return nil
+}
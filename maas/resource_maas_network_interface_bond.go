@@ -0,0 +1,339 @@
+package maas
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/maas/gomaasclient/client"
+	"github.com/maas/gomaasclient/entity"
+)
+
+func resourceMaasNetworkInterfaceBond() *schema.Resource {
+	return &schema.Resource{
+		Description:   "Provides a resource to manage a bond network interface of an existing MAAS machine.",
+		CreateContext: resourceNetworkInterfaceBondCreate,
+		ReadContext:   resourceNetworkInterfaceBondRead,
+		UpdateContext: resourceNetworkInterfaceBondUpdate,
+		DeleteContext: resourceNetworkInterfaceBondDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: func(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+				idParts := strings.Split(d.Id(), ":")
+				if len(idParts) != 2 || idParts[0] == "" || idParts[1] == "" {
+					return nil, fmt.Errorf("unexpected format of ID (%q), expected MACHINE:NETWORK_INTERFACE, where MACHINE is a system ID, hostname, FQDN, or MAC address, and NETWORK_INTERFACE is a name or ID", d.Id())
+				}
+				client := m.(*ClientConfig).Client
+				machine, err := getMachine(client, idParts[0])
+				if err != nil {
+					return nil, err
+				}
+				n, err := getNetworkInterfaceOfType(client, machine.SystemID, idParts[1], "bond")
+				if err != nil {
+					return nil, err
+				}
+				tfState := map[string]interface{}{
+					"id":      fmt.Sprintf("%v", n.ID),
+					"machine": machine.SystemID,
+				}
+				if err := setTerraformState(d, tfState); err != nil {
+					return nil, err
+				}
+				return []*schema.ResourceData{d}, nil
+			},
+		},
+
+		Schema: map[string]*schema.Schema{
+			"machine": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The identifier (system ID, hostname, FQDN, or MAC address) of the machine with the bond network interface.",
+			},
+			"name": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The bond network interface name (e.g. `bond0`).",
+			},
+			"parents": {
+				Type:     schema.TypeSet,
+				Required: true,
+				MinItems: 1,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+				Description: "A set of the parent network interfaces names (e.g. `eth0`), which are bonded together.",
+			},
+			"mac_address": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				Description: "The bond network interface MAC address. If it's not set, the MAC address of the first parent is used. This argument is computed if it's not set.",
+			},
+			"vlan": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				Description: "The ID of the VLAN the bond network interface is connected to. This argument is computed if it's not set.\n\n**NOTE:** MAAS can't change the VLAN of a network interface linked to subnets, so all its links are removed before changing it. The links managed by `maas_network_interface_link` resources are created again on the next apply, and the other ones are lost.",
+			},
+			"tags": {
+				Type:     schema.TypeSet,
+				Optional: true,
+				Computed: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+				Description: "A set of tag names to be assigned to the bond network interface. This argument is computed if it's not set.",
+			},
+			"mtu": {
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Computed:    true,
+				Description: "The MTU of the bond network interface. This argument is computed if it's not set.",
+			},
+			"accept_ra": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Computed:    true,
+				Description: "Boolean value indicating if the bond network interface accepts IPv6 router advertisements. This argument is computed if it's not set.",
+			},
+			"bond_mode": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Computed:         true,
+				ValidateDiagFunc: validation.ToDiagFunc(validation.StringInSlice([]string{"balance-rr", "active-backup", "balance-xor", "broadcast", "802.3ad", "balance-tlb", "balance-alb"}, false)),
+				Description:      "The bonding mode. Valid options are: `balance-rr`, `active-backup`, `balance-xor`, `broadcast`, `802.3ad`, `balance-tlb`, and `balance-alb`. This argument is computed if it's not set.",
+			},
+			"bond_lacp_rate": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Computed:         true,
+				ValidateDiagFunc: validation.ToDiagFunc(validation.StringInSlice([]string{"fast", "slow"}, false)),
+				Description:      "The rate at which the LACPDU frames are sent, used by the `802.3ad` bonding mode. Valid options are: `fast` and `slow`. This argument is computed if it's not set.",
+			},
+			"bond_xmit_hash_policy": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Computed:         true,
+				ValidateDiagFunc: validation.ToDiagFunc(validation.StringInSlice([]string{"layer2", "layer2+3", "layer3+4", "encap2+3", "encap3+4"}, false)),
+				Description:      "The transmit hash policy used for the slave selection by the `balance-xor`, `802.3ad`, and `balance-tlb` bonding modes. Valid options are: `layer2`, `layer2+3`, `layer3+4`, `encap2+3`, and `encap3+4`. This argument is computed if it's not set.",
+			},
+			"bond_miimon": {
+				Type:             schema.TypeInt,
+				Optional:         true,
+				Computed:         true,
+				ValidateDiagFunc: validation.ToDiagFunc(validation.IntAtLeast(1)),
+				Description:      "The link monitoring frequency, given in milliseconds. This argument is computed if it's not set.",
+			},
+			"bond_downdelay": {
+				Type:             schema.TypeInt,
+				Optional:         true,
+				Computed:         true,
+				ValidateDiagFunc: validation.ToDiagFunc(validation.IntAtLeast(1)),
+				Description:      "The time to wait before disabling a slave after a link failure is detected, given in milliseconds. It should be a multiple of `bond_miimon`. This argument is computed if it's not set.",
+			},
+			"bond_updelay": {
+				Type:             schema.TypeInt,
+				Optional:         true,
+				Computed:         true,
+				ValidateDiagFunc: validation.ToDiagFunc(validation.IntAtLeast(1)),
+				Description:      "The time to wait before enabling a slave after a link recovery is detected, given in milliseconds. It should be a multiple of `bond_miimon`. This argument is computed if it's not set.",
+			},
+			"bond_num_grat_arp": {
+				Type:             schema.TypeInt,
+				Optional:         true,
+				Computed:         true,
+				ValidateDiagFunc: validation.ToDiagFunc(validation.IntBetween(1, 255)),
+				Description:      "The number of peer notifications (gratuitous ARPs and unsolicited IPv6 neighbor advertisements) sent after a failover. This argument is computed if it's not set.",
+			},
+		},
+	}
+}
+
+func resourceNetworkInterfaceBondCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*ClientConfig).Client
+
+	machine, err := getMachine(client, d.Get("machine").(string))
+	if err != nil {
+		return diagFromErr(err)
+	}
+	params, err := getNetworkInterfaceBondParams(client, d, machine.SystemID)
+	if err != nil {
+		return diagFromErr(err)
+	}
+	networkInterface, err := client.NetworkInterfaces.CreateBond(machine.SystemID, params)
+	if err != nil {
+		return diagFromErr(err)
+	}
+	d.SetId(fmt.Sprintf("%v", networkInterface.ID))
+
+	return resourceNetworkInterfaceBondUpdate(ctx, d, m)
+}
+
+func resourceNetworkInterfaceBondRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*ClientConfig).Client
+
+	machine, err := getMachine(client, d.Get("machine").(string))
+	if err != nil {
+		return diagFromErr(err)
+	}
+	id, err := strconv.Atoi(d.Id())
+	if err != nil {
+		return diagFromErr(err)
+	}
+	networkInterface, err := client.NetworkInterface.Get(machine.SystemID, id)
+	if err != nil {
+		return diagFromErr(err)
+	}
+
+	tfState := map[string]interface{}{
+		"name":        networkInterface.Name,
+		"parents":     networkInterface.Parents,
+		"mac_address": networkInterface.MACAddress,
+		"vlan":        fmt.Sprintf("%v", networkInterface.VLAN.ID),
+		"tags":        networkInterface.Tags,
+		"mtu":         networkInterface.EffectiveMTU,
+		"accept_ra":   getNetworkInterfaceAcceptRA(networkInterface),
+		"bond_mode":   networkInterface.BondMode,
+	}
+	for _, k := range []string{"bond_mode", "bond_lacp_rate", "bond_xmit_hash_policy"} {
+		if v, ok := getNetworkInterfaceParam(networkInterface, k).(string); ok {
+			tfState[k] = v
+		}
+	}
+	for _, k := range []string{"bond_miimon", "bond_downdelay", "bond_updelay", "bond_num_grat_arp"} {
+		if v, ok := getNetworkInterfaceParam(networkInterface, k).(float64); ok {
+			tfState[k] = int(v)
+		}
+	}
+	if err := setTerraformState(d, tfState); err != nil {
+		return diagFromErr(err)
+	}
+
+	return nil
+}
+
+func resourceNetworkInterfaceBondUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*ClientConfig).Client
+
+	machine, err := getMachine(client, d.Get("machine").(string))
+	if err != nil {
+		return diagFromErr(err)
+	}
+	id, err := strconv.Atoi(d.Id())
+	if err != nil {
+		return diagFromErr(err)
+	}
+	if d.HasChange("vlan") {
+		networkInterface, err := client.NetworkInterface.Get(machine.SystemID, id)
+		if err != nil {
+			return diagFromErr(err)
+		}
+		if isLinkedNetworkInterfaceVLANChange(networkInterface, d.Get("vlan").(string)) {
+			if _, err := unlinkNetworkInterfaceSubnets(client, machine.SystemID, networkInterface); err != nil {
+				return diagFromErr(err)
+			}
+		}
+	}
+	params, err := getNetworkInterfaceBondParams(client, d, machine.SystemID)
+	if err != nil {
+		return diagFromErr(err)
+	}
+	if _, err = client.NetworkInterface.Update(machine.SystemID, id, params); err != nil {
+		return diagFromErr(err)
+	}
+	if acceptRA := getRawConfigBool(d.GetRawConfig().GetAttr("accept_ra")); acceptRA != nil {
+		if _, err = client.NetworkInterface.Update(machine.SystemID, id, &networkInterfaceFlagsParams{AcceptRA: acceptRA}); err != nil {
+			return diagFromErr(err)
+		}
+	}
+
+	return resourceNetworkInterfaceBondRead(ctx, d, m)
+}
+
+func resourceNetworkInterfaceBondDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*ClientConfig).Client
+
+	machine, err := getMachine(client, d.Get("machine").(string))
+	if err != nil {
+		return diagFromErr(err)
+	}
+	id, err := strconv.Atoi(d.Id())
+	if err != nil {
+		return diagFromErr(err)
+	}
+	if err := client.NetworkInterface.Delete(machine.SystemID, id); err != nil {
+		return diagFromErr(err)
+	}
+
+	return nil
+}
+
+func getNetworkInterfaceBondParams(client *client.Client, d *schema.ResourceData, machineSystemID string) (*entity.NetworkInterfaceBondParams, error) {
+	parents, err := getNetworkInterfaceIDs(client, machineSystemID, convertToStringSlice(d.Get("parents").(*schema.Set).List()))
+	if err != nil {
+		return nil, err
+	}
+	return &entity.NetworkInterfaceBondParams{
+		NetworkInterfacePhysicalParams: entity.NetworkInterfacePhysicalParams{
+			Name:       d.Get("name").(string),
+			MACAddress: d.Get("mac_address").(string),
+			VLAN:       d.Get("vlan").(string),
+			MTU:        d.Get("mtu").(int),
+			Tags:       strings.Join(convertToStringSlice(d.Get("tags").(*schema.Set).List()), ","),
+		},
+		Parents:            parents,
+		BondMode:           d.Get("bond_mode").(string),
+		BondMiimon:         d.Get("bond_miimon").(int),
+		BondDownDelay:      d.Get("bond_downdelay").(int),
+		BondUpDelay:        d.Get("bond_updelay").(int),
+		BondLACPRate:       d.Get("bond_lacp_rate").(string),
+		BondXMitHashPolicy: d.Get("bond_xmit_hash_policy").(string),
+		BondNumberGratARP:  d.Get("bond_num_grat_arp").(int),
+	}, nil
+}
+
+// getNetworkInterfaceIDs returns the IDs of the machine network interfaces
+// with the given names.
+func getNetworkInterfaceIDs(client *client.Client, machineSystemID string, names []string) ([]int, error) {
+	networkInterfaces, err := client.NetworkInterfaces.Get(machineSystemID)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]int, len(names))
+	for i, name := range names {
+		found := false
+		for _, n := range networkInterfaces {
+			if n.Name == name {
+				ids[i] = n.ID
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("network interface (%s) was not found on machine (%s)", name, machineSystemID)
+		}
+	}
+	return ids, nil
+}
+
+// getNetworkInterfaceOfType returns the machine network interface of the
+// given type (e.g. `bond`), by name or ID.
+func getNetworkInterfaceOfType(client *client.Client, machineSystemID string, identifier string, interfaceType string) (*entity.NetworkInterface, error) {
+	networkInterfaces, err := client.NetworkInterfaces.Get(machineSystemID)
+	if err != nil {
+		return nil, err
+	}
+	for _, n := range networkInterfaces {
+		if n.Type != interfaceType {
+			continue
+		}
+		if n.Name == identifier || fmt.Sprintf("%v", n.ID) == identifier {
+			return &n, nil
+		}
+	}
+	return nil, fmt.Errorf("%s network interface (%s) was not found on machine (%s)", interfaceType, identifier, machineSystemID)
+}
@@ -0,0 +1,279 @@
+package maas
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/maas/gomaasclient/client"
+	"github.com/maas/gomaasclient/entity"
+)
+
+func resourceMaasNetworkInterfaceBridge() *schema.Resource {
+	return &schema.Resource{
+		Description:   "Provides a resource to manage a bridge network interface of an existing MAAS machine.",
+		CreateContext: resourceNetworkInterfaceBridgeCreate,
+		ReadContext:   resourceNetworkInterfaceBridgeRead,
+		UpdateContext: resourceNetworkInterfaceBridgeUpdate,
+		DeleteContext: resourceNetworkInterfaceBridgeDelete,
+		CustomizeDiff: resourceNetworkInterfaceBridgeCustomizeDiff,
+		Importer: &schema.ResourceImporter{
+			StateContext: func(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+				idParts := strings.Split(d.Id(), ":")
+				if len(idParts) != 2 || idParts[0] == "" || idParts[1] == "" {
+					return nil, fmt.Errorf("unexpected format of ID (%q), expected MACHINE:NETWORK_INTERFACE, where MACHINE is a system ID, hostname, FQDN, or MAC address, and NETWORK_INTERFACE is a name or ID", d.Id())
+				}
+				client := m.(*ClientConfig).Client
+				machine, err := getMachine(client, idParts[0])
+				if err != nil {
+					return nil, err
+				}
+				n, err := getNetworkInterfaceOfType(client, machine.SystemID, idParts[1], "bridge")
+				if err != nil {
+					return nil, err
+				}
+				tfState := map[string]interface{}{
+					"id":      fmt.Sprintf("%v", n.ID),
+					"machine": machine.SystemID,
+				}
+				if err := setTerraformState(d, tfState); err != nil {
+					return nil, err
+				}
+				return []*schema.ResourceData{d}, nil
+			},
+		},
+
+		Schema: map[string]*schema.Schema{
+			"machine": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The identifier (system ID, hostname, FQDN, or MAC address) of the machine with the bridge network interface.",
+			},
+			"name": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The bridge network interface name (e.g. `br0`).",
+			},
+			"parent": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The name of the parent network interface (e.g. `eth0` or `bond0`) attached to the bridge.",
+			},
+			"mac_address": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				Description: "The bridge network interface MAC address. If it's not set, the MAC address of the parent is used. This argument is computed if it's not set.",
+			},
+			"vlan": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				Description: "The ID of the VLAN the bridge network interface is connected to. This argument is computed if it's not set.\n\n**NOTE:** MAAS can't change the VLAN of a network interface linked to subnets, so all its links are removed before changing it. The links managed by `maas_network_interface_link` resources are created again on the next apply, and the other ones are lost.",
+			},
+			"tags": {
+				Type:     schema.TypeSet,
+				Optional: true,
+				Computed: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+				Description: "A set of tag names to be assigned to the bridge network interface. This argument is computed if it's not set.",
+			},
+			"mtu": {
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Computed:    true,
+				Description: "The MTU of the bridge network interface. This argument is computed if it's not set.",
+			},
+			"accept_ra": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Computed:    true,
+				Description: "Boolean value indicating if the bridge network interface accepts IPv6 router advertisements. This argument is computed if it's not set.",
+			},
+			"bridge_type": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Default:          "standard",
+				ValidateDiagFunc: validation.ToDiagFunc(validation.StringInSlice([]string{"standard", "ovs"}, false)),
+				Description:      "The bridge type. Valid options are: `standard` and `ovs` (Open vSwitch). Defaults to `standard`.",
+			},
+			"bridge_stp": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Computed:    true,
+				Description: "Boolean value indicating if the spanning tree protocol is enabled on the bridge. This is supported only by the `standard` bridges. This argument is computed if it's not set.",
+			},
+			"bridge_fd": {
+				Type:             schema.TypeInt,
+				Optional:         true,
+				Computed:         true,
+				ValidateDiagFunc: validation.ToDiagFunc(validation.IntAtLeast(0)),
+				Description:      "The forward delay of the bridge, given in seconds. This is supported only by the `standard` bridges. This argument is computed if it's not set.",
+			},
+		},
+	}
+}
+
+func resourceNetworkInterfaceBridgeCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*ClientConfig).Client
+
+	machine, err := getMachine(client, d.Get("machine").(string))
+	if err != nil {
+		return diagFromErr(err)
+	}
+	params, err := getNetworkInterfaceBridgeParams(client, d, machine.SystemID)
+	if err != nil {
+		return diagFromErr(err)
+	}
+	networkInterface, err := client.NetworkInterfaces.CreateBridge(machine.SystemID, params)
+	if err != nil {
+		return diagFromErr(err)
+	}
+	d.SetId(fmt.Sprintf("%v", networkInterface.ID))
+
+	return resourceNetworkInterfaceBridgeUpdate(ctx, d, m)
+}
+
+func resourceNetworkInterfaceBridgeRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*ClientConfig).Client
+
+	machine, err := getMachine(client, d.Get("machine").(string))
+	if err != nil {
+		return diagFromErr(err)
+	}
+	id, err := strconv.Atoi(d.Id())
+	if err != nil {
+		return diagFromErr(err)
+	}
+	networkInterface, err := client.NetworkInterface.Get(machine.SystemID, id)
+	if err != nil {
+		return diagFromErr(err)
+	}
+
+	parent := ""
+	if len(networkInterface.Parents) > 0 {
+		parent = networkInterface.Parents[0]
+	}
+	tfState := map[string]interface{}{
+		"name":        networkInterface.Name,
+		"parent":      parent,
+		"mac_address": networkInterface.MACAddress,
+		"vlan":        fmt.Sprintf("%v", networkInterface.VLAN.ID),
+		"tags":        networkInterface.Tags,
+		"mtu":         networkInterface.EffectiveMTU,
+		"accept_ra":   getNetworkInterfaceAcceptRA(networkInterface),
+		"bridge_stp":  networkInterface.BridgeSTP,
+		"bridge_fd":   networkInterface.BridgeFD,
+	}
+	if v, ok := getNetworkInterfaceParam(networkInterface, "bridge_type").(string); ok {
+		tfState["bridge_type"] = v
+	}
+	if v, ok := getNetworkInterfaceParam(networkInterface, "bridge_stp").(bool); ok {
+		tfState["bridge_stp"] = v
+	}
+	if v, ok := getNetworkInterfaceParam(networkInterface, "bridge_fd").(float64); ok {
+		tfState["bridge_fd"] = int(v)
+	}
+	if err := setTerraformState(d, tfState); err != nil {
+		return diagFromErr(err)
+	}
+
+	return nil
+}
+
+func resourceNetworkInterfaceBridgeUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*ClientConfig).Client
+
+	machine, err := getMachine(client, d.Get("machine").(string))
+	if err != nil {
+		return diagFromErr(err)
+	}
+	id, err := strconv.Atoi(d.Id())
+	if err != nil {
+		return diagFromErr(err)
+	}
+	if d.HasChange("vlan") {
+		networkInterface, err := client.NetworkInterface.Get(machine.SystemID, id)
+		if err != nil {
+			return diagFromErr(err)
+		}
+		if isLinkedNetworkInterfaceVLANChange(networkInterface, d.Get("vlan").(string)) {
+			if _, err := unlinkNetworkInterfaceSubnets(client, machine.SystemID, networkInterface); err != nil {
+				return diagFromErr(err)
+			}
+		}
+	}
+	params, err := getNetworkInterfaceBridgeParams(client, d, machine.SystemID)
+	if err != nil {
+		return diagFromErr(err)
+	}
+	if _, err = client.NetworkInterface.Update(machine.SystemID, id, params); err != nil {
+		return diagFromErr(err)
+	}
+	flags := &networkInterfaceFlagsParams{AcceptRA: getRawConfigBool(d.GetRawConfig().GetAttr("accept_ra"))}
+	if d.Get("bridge_type").(string) != "ovs" {
+		flags.BridgeSTP = getRawConfigBool(d.GetRawConfig().GetAttr("bridge_stp"))
+	}
+	if flags.AcceptRA != nil || flags.BridgeSTP != nil {
+		if _, err = client.NetworkInterface.Update(machine.SystemID, id, flags); err != nil {
+			return diagFromErr(err)
+		}
+	}
+
+	return resourceNetworkInterfaceBridgeRead(ctx, d, m)
+}
+
+func resourceNetworkInterfaceBridgeDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*ClientConfig).Client
+
+	machine, err := getMachine(client, d.Get("machine").(string))
+	if err != nil {
+		return diagFromErr(err)
+	}
+	id, err := strconv.Atoi(d.Id())
+	if err != nil {
+		return diagFromErr(err)
+	}
+	if err := client.NetworkInterface.Delete(machine.SystemID, id); err != nil {
+		return diagFromErr(err)
+	}
+
+	return nil
+}
+
+func resourceNetworkInterfaceBridgeCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	if d.Get("bridge_type").(string) != "ovs" {
+		return nil
+	}
+	if bridgeSTP := d.GetRawConfig().GetAttr("bridge_stp"); bridgeSTP.IsKnown() && !bridgeSTP.IsNull() && bridgeSTP.True() {
+		return fmt.Errorf("bridge_stp is supported only by the standard bridges")
+	}
+	return nil
+}
+
+func getNetworkInterfaceBridgeParams(client *client.Client, d *schema.ResourceData, machineSystemID string) (*entity.NetworkInterfaceBridgeParams, error) {
+	parents, err := getNetworkInterfaceIDs(client, machineSystemID, []string{d.Get("parent").(string)})
+	if err != nil {
+		return nil, err
+	}
+	return &entity.NetworkInterfaceBridgeParams{
+		NetworkInterfacePhysicalParams: entity.NetworkInterfacePhysicalParams{
+			Name:       d.Get("name").(string),
+			MACAddress: d.Get("mac_address").(string),
+			VLAN:       d.Get("vlan").(string),
+			MTU:        d.Get("mtu").(int),
+			Tags:       strings.Join(convertToStringSlice(d.Get("tags").(*schema.Set).List()), ","),
+		},
+		Parent:     parents[0],
+		Bridgetype: d.Get("bridge_type").(string),
+		BridgeFD:   d.Get("bridge_fd").(int),
+	}, nil
+}
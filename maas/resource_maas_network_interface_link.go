@@ -3,7 +3,9 @@ package maas
 import (
 	"context"
 	"fmt"
+	"log"
 	"strconv"
+	"strings"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -19,13 +21,49 @@ func resourceMaasNetworkInterfaceLink() *schema.Resource {
 		ReadContext:   resourceNetworkInterfaceLinkRead,
 		UpdateContext: resourceNetworkInterfaceLinkUpdate,
 		DeleteContext: resourceNetworkInterfaceLinkDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: func(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+				idParts := strings.Split(d.Id(), ":")
+				if len(idParts) != 3 || idParts[0] == "" || idParts[1] == "" || idParts[2] == "" {
+					return nil, fmt.Errorf("unexpected format of ID (%q), expected MACHINE:NETWORK_INTERFACE:LINK, where MACHINE is a system ID, hostname, FQDN, or MAC address, NETWORK_INTERFACE is a MAC address, name, or ID, and LINK is an ID", d.Id())
+				}
+				client := m.(*ClientConfig).Client
+				machine, err := getMachine(client, idParts[0])
+				if err != nil {
+					return nil, err
+				}
+				networkInterface, err := getNetworkInterface(client, machine.SystemID, idParts[1])
+				if err != nil {
+					return nil, err
+				}
+				linkID, err := strconv.Atoi(idParts[2])
+				if err != nil {
+					return nil, err
+				}
+				link := findNetworkInterfaceLink(networkInterface, linkID)
+				if link == nil {
+					return nil, fmt.Errorf("cannot find link (%v) on the network interface (%v) from machine (%s)", linkID, networkInterface.ID, machine.SystemID)
+				}
+				tfState := map[string]interface{}{
+					"id":                fmt.Sprintf("%v", link.ID),
+					"machine":           machine.SystemID,
+					"network_interface": idParts[1],
+					"subnet":            link.Subnet.CIDR,
+					"default_gateway":   false,
+				}
+				if err := setTerraformState(d, tfState); err != nil {
+					return nil, err
+				}
+				return []*schema.ResourceData{d}, nil
+			},
+		},
 
 		Schema: map[string]*schema.Schema{
 			"machine": {
 				Type:        schema.TypeString,
 				Required:    true,
 				ForceNew:    true,
-				Description: "The identifier (system ID, hostname, or FQDN) of the machine with the network interface.",
+				Description: "The identifier (system ID, hostname, FQDN, or MAC address) of the machine with the network interface.",
 			},
 			"network_interface": {
 				Type:        schema.TypeString,
@@ -59,31 +97,31 @@ func resourceMaasNetworkInterfaceLink() *schema.Resource {
 				ForceNew:         true,
 				Computed:         true,
 				ValidateDiagFunc: validation.ToDiagFunc(validation.IsIPAddress),
-				Description:      "Valid IP address (from the given subnet) to be configured on the network interface. Only used when `mode` is set to `STATIC`.",
+				Description:      "Valid IP address (from the given subnet) to be configured on the network interface. Only used when `mode` is set to `STATIC`. This is computed if it's not set, with the IP address assigned by MAAS. The `AUTO` mode IP addresses are assigned when the machine is deployed, and the `DHCP` mode ones are known only after they are leased.",
 			},
 		},
 	}
 }
 
 func resourceNetworkInterfaceLinkCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	client := m.(*client.Client)
+	client := m.(*ClientConfig).Client
 
 	// Create network interface link
 	machine, err := getMachine(client, d.Get("machine").(string))
 	if err != nil {
-		return diag.FromErr(err)
+		return diagFromErr(err)
 	}
 	networkInterface, err := getNetworkInterface(client, machine.SystemID, d.Get("network_interface").(string))
 	if err != nil {
-		return diag.FromErr(err)
+		return diagFromErr(err)
 	}
 	subnet, err := getSubnet(client, d.Get("subnet").(string))
 	if err != nil {
-		return diag.FromErr(err)
+		return diagFromErr(err)
 	}
 	link, err := createNetworkInterfaceLink(client, machine.SystemID, networkInterface.ID, getNetworkInterfaceLinkParams(d, subnet.ID))
 	if err != nil {
-		return diag.FromErr(err)
+		return diagFromErr(err)
 	}
 
 	// Save the resource id
@@ -93,60 +131,75 @@ func resourceNetworkInterfaceLinkCreate(ctx context.Context, d *schema.ResourceD
 }
 
 func resourceNetworkInterfaceLinkRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	client := m.(*client.Client)
+	client := m.(*ClientConfig).Client
 
 	// Get params for the read operation
 	linkID, err := strconv.Atoi(d.Id())
 	if err != nil {
-		return diag.FromErr(err)
+		return diagFromErr(err)
 	}
 	machine, err := getMachine(client, d.Get("machine").(string))
 	if err != nil {
-		return diag.FromErr(err)
+		return diagFromErr(err)
 	}
 	networkInterface, err := getNetworkInterface(client, machine.SystemID, d.Get("network_interface").(string))
 	if err != nil {
-		return diag.FromErr(err)
+		return diagFromErr(err)
 	}
 
 	// Get the network interface link
-	link, err := getNetworkInterfaceLink(client, machine.SystemID, networkInterface.ID, linkID)
-	if err != nil {
-		return diag.FromErr(err)
+	link := findNetworkInterfaceLink(networkInterface, linkID)
+	if link == nil {
+		log.Printf("[DEBUG] Link (%v) was not found on the network interface (%v) from machine (%s), removing it from state\n", linkID, networkInterface.ID, machine.SystemID)
+		d.SetId("")
+		return nil
 	}
 
 	// Set the Terraform state
-	if err := d.Set("ip_address", link.IPAddress); err != nil {
-		return diag.FromErr(err)
+	subnet := d.Get("subnet").(string)
+	if subnet != fmt.Sprintf("%v", link.Subnet.ID) {
+		subnet = link.Subnet.CIDR
+	}
+	tfState := map[string]interface{}{
+		"subnet":     subnet,
+		"mode":       strings.ToUpper(link.Mode),
+		"ip_address": link.IPAddress,
+	}
+	if err := setTerraformState(d, tfState); err != nil {
+		return diagFromErr(err)
 	}
 
 	return nil
 }
 
 func resourceNetworkInterfaceLinkUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	client := m.(*client.Client)
+	client := m.(*ClientConfig).Client
 
 	// Get params for the update operation
 	linkID, err := strconv.Atoi(d.Id())
 	if err != nil {
-		return diag.FromErr(err)
+		return diagFromErr(err)
 	}
 	machine, err := getMachine(client, d.Get("machine").(string))
 	if err != nil {
-		return diag.FromErr(err)
+		return diagFromErr(err)
 	}
 	networkInterface, err := getNetworkInterface(client, machine.SystemID, d.Get("network_interface").(string))
 	if err != nil {
-		return diag.FromErr(err)
+		return diagFromErr(err)
 	}
 
-	// Run update operation
+	// Run update operation. The default gateways are cleared only when this
+	// link changes, so the ones set by the other links are kept.
+	if !d.HasChange("default_gateway") {
+		return resourceNetworkInterfaceLinkRead(ctx, d, m)
+	}
 	if _, err := client.Machine.ClearDefaultGateways(machine.SystemID); err != nil {
-		return diag.FromErr(err)
+		return diagFromErr(err)
 	}
 	if d.Get("default_gateway").(bool) {
 		if _, err := client.NetworkInterface.SetDefaultGateway(machine.SystemID, networkInterface.ID, linkID); err != nil {
-			return diag.FromErr(err)
+			return diagFromErr(err)
 		}
 	}
 
@@ -154,25 +207,25 @@ func resourceNetworkInterfaceLinkUpdate(ctx context.Context, d *schema.ResourceD
 }
 
 func resourceNetworkInterfaceLinkDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	client := m.(*client.Client)
+	client := m.(*ClientConfig).Client
 
 	// Get params for the delete operation
 	linkID, err := strconv.Atoi(d.Id())
 	if err != nil {
-		return diag.FromErr(err)
+		return diagFromErr(err)
 	}
 	machine, err := getMachine(client, d.Get("machine").(string))
 	if err != nil {
-		return diag.FromErr(err)
+		return diagFromErr(err)
 	}
 	networkInterface, err := getNetworkInterface(client, machine.SystemID, d.Get("network_interface").(string))
 	if err != nil {
-		return diag.FromErr(err)
+		return diagFromErr(err)
 	}
 
 	// Delete the network interface link
 	if err := deleteNetworkInterfaceLink(client, machine.SystemID, networkInterface.ID, linkID); err != nil {
-		return diag.FromErr(err)
+		return diagFromErr(err)
 	}
 
 	return nil
@@ -201,17 +254,13 @@ func createNetworkInterfaceLink(client *client.Client, machineSystemID string, n
 	return &networkInterface.Links[0], nil
 }
 
-func getNetworkInterfaceLink(client *client.Client, machineSystemID string, networkInterfaceID int, linkID int) (*entity.NetworkInterfaceLink, error) {
-	networkInterface, err := client.NetworkInterface.Get(machineSystemID, networkInterfaceID)
-	if err != nil {
-		return nil, err
-	}
+func findNetworkInterfaceLink(networkInterface *entity.NetworkInterface, linkID int) *entity.NetworkInterfaceLink {
 	for _, link := range networkInterface.Links {
 		if link.ID == linkID {
-			return &link, nil
+			return &link
 		}
 	}
-	return nil, fmt.Errorf("cannot find link (%v) on the network interface (%v) from machine (%s)", linkID, networkInterfaceID, machineSystemID)
+	return nil
 }
 
 func deleteNetworkInterfaceLink(client *client.Client, machineSystemID string, networkInterfaceID int, linkID int) error {
@@ -6,6 +6,7 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/hashicorp/go-cty/cty"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/maas/gomaasclient/client"
@@ -23,9 +24,9 @@ func resourceMaasNetworkInterfacePhysical() *schema.Resource {
 			StateContext: func(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
 				idParts := strings.Split(d.Id(), ":")
 				if len(idParts) != 2 || idParts[0] == "" || idParts[1] == "" {
-					return nil, fmt.Errorf("unexpected format of ID (%q), expected MACHINE:NETWORK_INTERFACE", d.Id())
+					return nil, fmt.Errorf("unexpected format of ID (%q), expected MACHINE:NETWORK_INTERFACE, where MACHINE is a system ID, hostname, FQDN, or MAC address, and NETWORK_INTERFACE is a MAC address, name, or ID", d.Id())
 				}
-				client := m.(*client.Client)
+				client := m.(*ClientConfig).Client
 				machine, err := getMachine(client, idParts[0])
 				if err != nil {
 					return nil, err
@@ -52,7 +53,7 @@ func resourceMaasNetworkInterfacePhysical() *schema.Resource {
 				Type:        schema.TypeString,
 				Required:    true,
 				ForceNew:    true,
-				Description: "The identifier (system ID, hostname, or FQDN) of the machine with the physical network interface.",
+				Description: "The identifier (system ID, hostname, FQDN, or MAC address) of the machine with the physical network interface.",
 			},
 			"mac_address": {
 				Type:        schema.TypeString,
@@ -63,7 +64,7 @@ func resourceMaasNetworkInterfacePhysical() *schema.Resource {
 			"vlan": {
 				Type:        schema.TypeString,
 				Optional:    true,
-				Description: "VLAN the physical network interface is connected to. Defaults to `untagged`.",
+				Description: "VLAN the physical network interface is connected to. Defaults to `untagged`.\n\n**NOTE:** MAAS can't change the VLAN of a network interface linked to subnets, so all its links are removed before changing it. The links managed by `maas_network_interface_link` resources are created again on the next apply, and the other ones are lost.",
 			},
 			"name": {
 				Type:        schema.TypeString,
@@ -86,25 +87,37 @@ func resourceMaasNetworkInterfacePhysical() *schema.Resource {
 				Computed:    true,
 				Description: "The MTU of the physical network interface. This argument is computed if it's not set.",
 			},
+			"enabled": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+				Description: "Boolean value indicating if the physical network interface is enabled. A disabled network interface is not configured when the machine is deployed. Defaults to `true`.",
+			},
+			"accept_ra": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Computed:    true,
+				Description: "Boolean value indicating if the physical network interface accepts IPv6 router advertisements. This argument is computed if it's not set.",
+			},
 		},
 	}
 }
 
 func resourceNetworkInterfacePhysicalCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	client := m.(*client.Client)
+	client := m.(*ClientConfig).Client
 
 	machine, err := getMachine(client, d.Get("machine").(string))
 	if err != nil {
-		return diag.FromErr(err)
+		return diagFromErr(err)
 	}
 	networkInterface, err := findNetworkInterfacePhysical(client, machine.SystemID, d.Get("mac_address").(string))
 	if err != nil {
-		return diag.FromErr(err)
+		return diagFromErr(err)
 	}
 	if networkInterface == nil {
 		networkInterface, err = client.NetworkInterfaces.CreatePhysical(machine.SystemID, getNetworkInterfacePhysicalParams(d))
 		if err != nil {
-			return diag.FromErr(err)
+			return diagFromErr(err)
 		}
 	}
 	d.SetId(fmt.Sprintf("%v", networkInterface.ID))
@@ -113,64 +126,85 @@ func resourceNetworkInterfacePhysicalCreate(ctx context.Context, d *schema.Resou
 }
 
 func resourceNetworkInterfacePhysicalRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	client := m.(*client.Client)
+	client := m.(*ClientConfig).Client
 
 	machine, err := getMachine(client, d.Get("machine").(string))
 	if err != nil {
-		return diag.FromErr(err)
+		return diagFromErr(err)
 	}
 	id, err := strconv.Atoi(d.Id())
 	if err != nil {
-		return diag.FromErr(err)
+		return diagFromErr(err)
 	}
 	networkInterface, err := client.NetworkInterface.Get(machine.SystemID, id)
 	if err != nil {
-		return diag.FromErr(err)
+		return diagFromErr(err)
 	}
 
 	tfState := map[string]interface{}{
-		"name": networkInterface.Name,
-		"tags": networkInterface.Tags,
-		"mtu":  networkInterface.EffectiveMTU,
+		"name":      networkInterface.Name,
+		"tags":      networkInterface.Tags,
+		"mtu":       networkInterface.EffectiveMTU,
+		"enabled":   networkInterface.Enabled,
+		"accept_ra": getNetworkInterfaceAcceptRA(networkInterface),
 	}
 	if err := setTerraformState(d, tfState); err != nil {
-		return diag.FromErr(err)
+		return diagFromErr(err)
 	}
 
 	return nil
 }
 
 func resourceNetworkInterfacePhysicalUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	client := m.(*client.Client)
+	client := m.(*ClientConfig).Client
 
 	machine, err := getMachine(client, d.Get("machine").(string))
 	if err != nil {
-		return diag.FromErr(err)
+		return diagFromErr(err)
 	}
 	id, err := strconv.Atoi(d.Id())
 	if err != nil {
-		return diag.FromErr(err)
+		return diagFromErr(err)
+	}
+	if d.HasChange("vlan") {
+		networkInterface, err := client.NetworkInterface.Get(machine.SystemID, id)
+		if err != nil {
+			return diagFromErr(err)
+		}
+		if isLinkedNetworkInterfaceVLANChange(networkInterface, d.Get("vlan").(string)) {
+			if _, err := unlinkNetworkInterfaceSubnets(client, machine.SystemID, networkInterface); err != nil {
+				return diagFromErr(err)
+			}
+		}
 	}
 	if _, err = client.NetworkInterface.Update(machine.SystemID, id, getNetworkInterfacePhysicalParams(d)); err != nil {
-		return diag.FromErr(err)
+		return diagFromErr(err)
+	}
+	enabled := d.Get("enabled").(bool)
+	flags := &networkInterfaceFlagsParams{
+		AcceptRA: getRawConfigBool(d.GetRawConfig().GetAttr("accept_ra")),
+		Enabled:  &enabled,
+	}
+	if _, err = client.NetworkInterface.Update(machine.SystemID, id, flags); err != nil {
+		return diagFromErr(err)
 	}
 
 	return resourceNetworkInterfacePhysicalRead(ctx, d, m)
 }
 
 func resourceNetworkInterfacePhysicalDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	client := m.(*client.Client)
+	client := m.(*ClientConfig).Client
 
 	machine, err := getMachine(client, d.Get("machine").(string))
 	if err != nil {
-		return diag.FromErr(err)
+		return diagFromErr(err)
 	}
 	id, err := strconv.Atoi(d.Id())
 	if err != nil {
-		return diag.FromErr(err)
+		return diagFromErr(err)
 	}
 	if err := client.NetworkInterface.Delete(machine.SystemID, id); err != nil {
-		return diag.FromErr(err)
+		return diagFromErr(err)
 	}
 
 	return nil
@@ -212,3 +246,45 @@ func getNetworkInterfacePhysical(client *client.Client, machineSystemID string,
 	}
 	return nil, fmt.Errorf("physical network interface (%s) was not found on machine (%s)", identifier, machineSystemID)
 }
+
+// networkInterfaceFlagsParams holds the boolean network interface parameters.
+// The gomaasclient params omit them when they are false, so they couldn't be
+// disabled otherwise. The unset ones are omitted, so MAAS keeps their values.
+type networkInterfaceFlagsParams struct {
+	AcceptRA  *bool `url:"accept_ra,omitempty"`
+	BridgeSTP *bool `url:"bridge_stp,omitempty"`
+	Enabled   *bool `url:"enabled,omitempty"`
+}
+
+// getRawConfigBool returns the given boolean value of the raw config, or nil
+// if it's not set.
+func getRawConfigBool(v cty.Value) *bool {
+	if v.IsNull() || !v.IsKnown() {
+		return nil
+	}
+	b := v.True()
+	return &b
+}
+
+// getNetworkInterfaceParam returns the value of the given network interface
+// parameter (e.g. `bridge_type`), or nil if it's not set.
+func getNetworkInterfaceParam(networkInterface *entity.NetworkInterface, name string) interface{} {
+	params, ok := networkInterface.Params.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	return params[name]
+}
+
+func getNetworkInterfaceAcceptRA(networkInterface *entity.NetworkInterface) bool {
+	if networkInterface.AcceptRA {
+		return true
+	}
+	// MAAS keeps this parameter as `accept-ra`
+	for _, name := range []string{"accept-ra", "accept_ra"} {
+		if v, ok := getNetworkInterfaceParam(networkInterface, name).(bool); ok {
+			return v
+		}
+	}
+	return false
+}
@@ -0,0 +1,198 @@
+package maas
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/maas/gomaasclient/entity"
+)
+
+func resourceMaasNetworkInterfaceVLAN() *schema.Resource {
+	return &schema.Resource{
+		Description:   "Provides a resource to manage a VLAN network interface (e.g. `eth0.100`) of an existing MAAS machine.",
+		CreateContext: resourceNetworkInterfaceVLANCreate,
+		ReadContext:   resourceNetworkInterfaceVLANRead,
+		UpdateContext: resourceNetworkInterfaceVLANUpdate,
+		DeleteContext: resourceNetworkInterfaceVLANDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: func(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+				idParts := strings.Split(d.Id(), ":")
+				if len(idParts) != 2 || idParts[0] == "" || idParts[1] == "" {
+					return nil, fmt.Errorf("unexpected format of ID (%q), expected MACHINE:NETWORK_INTERFACE, where MACHINE is a system ID, hostname, FQDN, or MAC address, and NETWORK_INTERFACE is a name or ID", d.Id())
+				}
+				client := m.(*ClientConfig).Client
+				machine, err := getMachine(client, idParts[0])
+				if err != nil {
+					return nil, err
+				}
+				n, err := getNetworkInterfaceOfType(client, machine.SystemID, idParts[1], "vlan")
+				if err != nil {
+					return nil, err
+				}
+				tfState := map[string]interface{}{
+					"id":      fmt.Sprintf("%v", n.ID),
+					"machine": machine.SystemID,
+				}
+				if err := setTerraformState(d, tfState); err != nil {
+					return nil, err
+				}
+				return []*schema.ResourceData{d}, nil
+			},
+		},
+
+		Schema: map[string]*schema.Schema{
+			"machine": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The identifier (system ID, hostname, FQDN, or MAC address) of the machine with the VLAN network interface.",
+			},
+			"parent": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The name of the parent network interface (e.g. `eth0` or `bond0`) the VLAN network interface is created on.",
+			},
+			"vlan": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The ID of the tagged VLAN of the VLAN network interface. It must be on the same fabric as the VLAN of the parent network interface.",
+			},
+			"name": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The VLAN network interface name, given by MAAS as `PARENT.VID` (e.g. `eth0.100`).",
+			},
+			"tags": {
+				Type:     schema.TypeSet,
+				Optional: true,
+				Computed: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+				Description: "A set of tag names to be assigned to the VLAN network interface. This argument is computed if it's not set.",
+			},
+			"mtu": {
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Computed:    true,
+				Description: "The MTU of the VLAN network interface. It can't be greater than the MTU of the parent. This argument is computed if it's not set.",
+			},
+			"accept_ra": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Computed:    true,
+				Description: "Boolean value indicating if the VLAN network interface accepts IPv6 router advertisements. This argument is computed if it's not set.",
+			},
+		},
+	}
+}
+
+func resourceNetworkInterfaceVLANCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*ClientConfig).Client
+
+	machine, err := getMachine(client, d.Get("machine").(string))
+	if err != nil {
+		return diagFromErr(err)
+	}
+	parents, err := getNetworkInterfaceIDs(client, machine.SystemID, []string{d.Get("parent").(string)})
+	if err != nil {
+		return diagFromErr(err)
+	}
+	params := &entity.NetworkInterfaceVLANParams{
+		VLAN:   d.Get("vlan").(string),
+		Parent: parents[0],
+	}
+	networkInterface, err := client.NetworkInterfaces.CreateVLAN(machine.SystemID, params)
+	if err != nil {
+		return diagFromErr(err)
+	}
+	d.SetId(fmt.Sprintf("%v", networkInterface.ID))
+
+	return resourceNetworkInterfaceVLANUpdate(ctx, d, m)
+}
+
+func resourceNetworkInterfaceVLANRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*ClientConfig).Client
+
+	machine, err := getMachine(client, d.Get("machine").(string))
+	if err != nil {
+		return diagFromErr(err)
+	}
+	id, err := strconv.Atoi(d.Id())
+	if err != nil {
+		return diagFromErr(err)
+	}
+	networkInterface, err := client.NetworkInterface.Get(machine.SystemID, id)
+	if err != nil {
+		return diagFromErr(err)
+	}
+
+	parent := ""
+	if len(networkInterface.Parents) > 0 {
+		parent = networkInterface.Parents[0]
+	}
+	tfState := map[string]interface{}{
+		"parent":    parent,
+		"vlan":      fmt.Sprintf("%v", networkInterface.VLAN.ID),
+		"name":      networkInterface.Name,
+		"tags":      networkInterface.Tags,
+		"mtu":       networkInterface.EffectiveMTU,
+		"accept_ra": getNetworkInterfaceAcceptRA(networkInterface),
+	}
+	if err := setTerraformState(d, tfState); err != nil {
+		return diagFromErr(err)
+	}
+
+	return nil
+}
+
+func resourceNetworkInterfaceVLANUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*ClientConfig).Client
+
+	machine, err := getMachine(client, d.Get("machine").(string))
+	if err != nil {
+		return diagFromErr(err)
+	}
+	id, err := strconv.Atoi(d.Id())
+	if err != nil {
+		return diagFromErr(err)
+	}
+	params := &entity.NetworkInterfacePhysicalParams{
+		MTU:  d.Get("mtu").(int),
+		Tags: strings.Join(convertToStringSlice(d.Get("tags").(*schema.Set).List()), ","),
+	}
+	if _, err = client.NetworkInterface.Update(machine.SystemID, id, params); err != nil {
+		return diagFromErr(err)
+	}
+	if acceptRA := getRawConfigBool(d.GetRawConfig().GetAttr("accept_ra")); acceptRA != nil {
+		if _, err = client.NetworkInterface.Update(machine.SystemID, id, &networkInterfaceFlagsParams{AcceptRA: acceptRA}); err != nil {
+			return diagFromErr(err)
+		}
+	}
+
+	return resourceNetworkInterfaceVLANRead(ctx, d, m)
+}
+
+func resourceNetworkInterfaceVLANDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*ClientConfig).Client
+
+	machine, err := getMachine(client, d.Get("machine").(string))
+	if err != nil {
+		return diagFromErr(err)
+	}
+	id, err := strconv.Atoi(d.Id())
+	if err != nil {
+		return diagFromErr(err)
+	}
+	if err := client.NetworkInterface.Delete(machine.SystemID, id); err != nil {
+		return diagFromErr(err)
+	}
+
+	return nil
+}
@@ -0,0 +1,219 @@
+package maas
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func resourceMaasNotification() *schema.Resource {
+	return &schema.Resource{
+		Description:   "Provides a resource to manage a MAAS notification, shown as a banner in the MAAS UI.",
+		CreateContext: resourceNotificationCreate,
+		ReadContext:   resourceNotificationRead,
+		UpdateContext: resourceNotificationUpdate,
+		DeleteContext: resourceNotificationDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"message": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The notification message. It may contain HTML, and `{placeholders}` filled from the `context`.",
+			},
+			"category": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Default:          "info",
+				ValidateDiagFunc: validation.ToDiagFunc(validation.StringInSlice([]string{"info", "warning", "error", "success"}, false)),
+				Description:      "The notification category. Valid options are: `info`, `warning`, `error`, `success`. Defaults to `info`.",
+			},
+			"ident": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Description: "A unique identifier of the notification. If a notification with this identifier already exists, it's updated instead of creating a new one.",
+			},
+			"user": {
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Description: "The ID of the user the notification is shown to. If it's not set, the notification isn't shown to a single user.",
+			},
+			"users": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Boolean value indicating if the notification is shown to all the users. Defaults to `false`.",
+			},
+			"admins": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Boolean value indicating if the notification is shown to all the administrators. Defaults to `false`.",
+			},
+			"context": {
+				Type:        schema.TypeMap,
+				Optional:    true,
+				Description: "A map with the values of the `message` placeholders.",
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"dismissable": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+				Description: "Boolean value indicating if the notification can be dismissed by the users. Defaults to `true`.",
+			},
+		},
+	}
+}
+
+func resourceNotificationCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	notifications := m.(*ClientConfig).Notifications
+
+	notification, err := findNotification(notifications, d.Get("ident").(string))
+	if err != nil {
+		return diagFromErr(err)
+	}
+	if notification == nil {
+		params, err := getNotificationParams(d)
+		if err != nil {
+			return diagFromErr(err)
+		}
+		notification, err = notifications.Create(params)
+		if err != nil {
+			return diagFromErr(err)
+		}
+	}
+	d.SetId(fmt.Sprintf("%v", notification.ID))
+
+	return resourceNotificationUpdate(ctx, d, m)
+}
+
+func resourceNotificationRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	notifications := m.(*ClientConfig).Notifications
+
+	id, err := strconv.Atoi(d.Id())
+	if err != nil {
+		return diagFromErr(err)
+	}
+	notification, err := notifications.GetByID(id)
+	if err != nil {
+		if isNotFoundError(err) {
+			log.Printf("[DEBUG] Notification (%s) was not found, removing it from state\n", d.Id())
+			d.SetId("")
+			return nil
+		}
+		return diagFromErr(err)
+	}
+	notificationContext := map[string]string{}
+	for k, v := range notification.Context {
+		notificationContext[k] = fmt.Sprintf("%v", v)
+	}
+	tfState := map[string]interface{}{
+		"message":     notification.Message,
+		"category":    notification.Category,
+		"ident":       notification.Ident,
+		"user":        getNotificationUserID(notification),
+		"users":       notification.Users,
+		"admins":      notification.Admins,
+		"context":     notificationContext,
+		"dismissable": notification.Dismissable,
+	}
+	if err := setTerraformState(d, tfState); err != nil {
+		return diagFromErr(err)
+	}
+
+	return nil
+}
+
+func resourceNotificationUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	notifications := m.(*ClientConfig).Notifications
+
+	id, err := strconv.Atoi(d.Id())
+	if err != nil {
+		return diagFromErr(err)
+	}
+	params, err := getNotificationParams(d)
+	if err != nil {
+		return diagFromErr(err)
+	}
+	if _, err := notifications.Update(id, params); err != nil {
+		return diagFromErr(err)
+	}
+
+	return resourceNotificationRead(ctx, d, m)
+}
+
+func resourceNotificationDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	notifications := m.(*ClientConfig).Notifications
+
+	id, err := strconv.Atoi(d.Id())
+	if err != nil {
+		return diagFromErr(err)
+	}
+	if err := notifications.Delete(id); err != nil {
+		return diagFromErr(err)
+	}
+
+	return nil
+}
+
+func getNotificationParams(d *schema.ResourceData) (*NotificationParams, error) {
+	params := NotificationParams{
+		Message:     d.Get("message").(string),
+		Category:    d.Get("category").(string),
+		Ident:       d.Get("ident").(string),
+		Users:       d.Get("users").(bool),
+		Admins:      d.Get("admins").(bool),
+		Dismissable: d.Get("dismissable").(bool),
+	}
+	if user := d.Get("user").(int); user != 0 {
+		params.User = strconv.Itoa(user)
+	}
+	notificationContext, err := json.Marshal(d.Get("context"))
+	if err != nil {
+		return nil, err
+	}
+	params.Context = string(notificationContext)
+	return &params, nil
+}
+
+// getNotificationUserID returns the ID of the notification user. MAAS returns
+// either the user ID, or the user object.
+func getNotificationUserID(notification *Notification) int {
+	switch user := notification.User.(type) {
+	case float64:
+		return int(user)
+	case map[string]interface{}:
+		if id, ok := user["id"].(float64); ok {
+			return int(id)
+		}
+	}
+	return 0
+}
+
+func findNotification(notifications *Notifications, ident string) (*Notification, error) {
+	if ident == "" {
+		return nil, nil
+	}
+	list, err := notifications.Get()
+	if err != nil {
+		return nil, err
+	}
+	for _, n := range list {
+		if n.Ident == ident {
+			return &n, nil
+		}
+	}
+	return nil, nil
+}
@@ -0,0 +1,256 @@
+package maas
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+var (
+	// defaultPackageRepositories are the built-in Ubuntu archives, which
+	// MAAS doesn't allow to be created or deleted.
+	defaultPackageRepositories = []string{"main_archive", "ports_archive"}
+)
+
+func resourceMaasPackageRepository() *schema.Resource {
+	return &schema.Resource{
+		Description:   "Provides a resource to manage MAAS package repositories.",
+		CreateContext: resourcePackageRepositoryCreate,
+		ReadContext:   resourcePackageRepositoryRead,
+		UpdateContext: resourcePackageRepositoryUpdate,
+		DeleteContext: resourcePackageRepositoryDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: func(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+				packageRepository, err := getPackageRepository(m.(*ClientConfig), d.Id())
+				if err != nil {
+					return nil, err
+				}
+				tfState := map[string]interface{}{
+					"id":   fmt.Sprintf("%v", packageRepository.ID),
+					"name": packageRepository.Name,
+				}
+				if err := setTerraformState(d, tfState); err != nil {
+					return nil, err
+				}
+				return []*schema.ResourceData{d}, nil
+			},
+		},
+
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The name of the package repository. The built-in Ubuntu archives are named `main_archive` and `ports_archive`. These are updated in place instead of being created, and they are left in MAAS when the resource is destroyed.",
+			},
+			"url": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The URL of the package repository.",
+			},
+			"distributions": {
+				Type:     schema.TypeSet,
+				Optional: true,
+				Computed: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+				Description: "A set of the distributions to include from the package repository (e.g. `jammy`). This argument is computed if it's not set.",
+			},
+			"components": {
+				Type:     schema.TypeSet,
+				Optional: true,
+				Computed: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+				Description: "A set of the components to include from the package repository (e.g. `main`). This is not supported by the built-in Ubuntu archives, which use `disabled_components` instead. This argument is computed if it's not set.",
+			},
+			"arches": {
+				Type:     schema.TypeSet,
+				Optional: true,
+				Computed: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+				Description: "A set of the architectures to include from the package repository (e.g. `amd64`). This argument is computed if it's not set.",
+			},
+			"disabled_pockets": {
+				Type:     schema.TypeSet,
+				Optional: true,
+				Computed: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+				Description: "A set of the pockets to exclude from the built-in Ubuntu archives. Valid options are: `updates`, `security`, and `backports`. This argument is computed if it's not set.",
+			},
+			"disabled_components": {
+				Type:     schema.TypeSet,
+				Optional: true,
+				Computed: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+				Description: "A set of the components to exclude from the built-in Ubuntu archives. Valid options are: `restricted`, `universe`, and `multiverse`. This argument is computed if it's not set.",
+			},
+			"disable_sources": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+				Description: "Boolean value indicating if the source packages (`deb-src`) of the package repository are disabled. Defaults to `true`.",
+			},
+			"key": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				Description: "The ASCII armored GPG key used to verify the package repository. This argument is computed if it's not set.",
+			},
+			"enabled": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+				Description: "Boolean value indicating if the package repository is enabled. Defaults to `true`.",
+			},
+		},
+	}
+}
+
+func resourcePackageRepositoryCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	clientConfig := m.(*ClientConfig)
+
+	name := d.Get("name").(string)
+	if isDefaultPackageRepository(name) {
+		packageRepository, err := getPackageRepository(clientConfig, name)
+		if err != nil {
+			return diagFromErr(err)
+		}
+		d.SetId(fmt.Sprintf("%v", packageRepository.ID))
+		return resourcePackageRepositoryUpdate(ctx, d, m)
+	}
+	packageRepository, err := clientConfig.PackageRepositories.Create(getPackageRepositoryParams(d))
+	if err != nil {
+		return diagFromErr(err)
+	}
+	d.SetId(fmt.Sprintf("%v", packageRepository.ID))
+
+	return resourcePackageRepositoryRead(ctx, d, m)
+}
+
+func resourcePackageRepositoryRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	packageRepository, err := findPackageRepository(m.(*ClientConfig), d.Id())
+	if err != nil {
+		return diagFromErr(err)
+	}
+	if packageRepository == nil {
+		log.Printf("[DEBUG] Package repository (%s) was not found, removing it from state\n", d.Id())
+		d.SetId("")
+		return nil
+	}
+	tfState := map[string]interface{}{
+		"name":                packageRepository.Name,
+		"url":                 packageRepository.URL,
+		"distributions":       packageRepository.Distributions,
+		"components":          packageRepository.Components,
+		"arches":              packageRepository.Arches,
+		"disabled_pockets":    packageRepository.DisabledPockets,
+		"disabled_components": packageRepository.DisabledComponents,
+		"disable_sources":     packageRepository.DisableSources,
+		"key":                 packageRepository.Key,
+		"enabled":             packageRepository.Enabled,
+	}
+	if err := setTerraformState(d, tfState); err != nil {
+		return diagFromErr(err)
+	}
+
+	return nil
+}
+
+func resourcePackageRepositoryUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	id, err := strconv.Atoi(d.Id())
+	if err != nil {
+		return diagFromErr(err)
+	}
+	if _, err := m.(*ClientConfig).PackageRepositories.Update(id, getPackageRepositoryParams(d)); err != nil {
+		return diagFromErr(err)
+	}
+
+	return resourcePackageRepositoryRead(ctx, d, m)
+}
+
+func resourcePackageRepositoryDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	if isDefaultPackageRepository(d.Get("name").(string)) {
+		log.Printf("[DEBUG] Package repository (%s) is a built-in Ubuntu archive, leaving it in MAAS\n", d.Id())
+		return nil
+	}
+	id, err := strconv.Atoi(d.Id())
+	if err != nil {
+		return diagFromErr(err)
+	}
+	if err := m.(*ClientConfig).PackageRepositories.Delete(id); err != nil {
+		return diagFromErr(err)
+	}
+
+	return nil
+}
+
+func getPackageRepositoryParams(d *schema.ResourceData) *PackageRepositoryParams {
+	params := &PackageRepositoryParams{
+		Name:           d.Get("name").(string),
+		URL:            d.Get("url").(string),
+		DisableSources: d.Get("disable_sources").(bool),
+		Key:            d.Get("key").(string),
+		Enabled:        d.Get("enabled").(bool),
+	}
+	if p, ok := d.GetOk("distributions"); ok {
+		params.Distributions = convertToStringSlice(p.(*schema.Set).List())
+	}
+	if p, ok := d.GetOk("components"); ok {
+		params.Components = convertToStringSlice(p.(*schema.Set).List())
+	}
+	if p, ok := d.GetOk("arches"); ok {
+		params.Arches = convertToStringSlice(p.(*schema.Set).List())
+	}
+	if p, ok := d.GetOk("disabled_pockets"); ok {
+		params.DisabledPockets = convertToStringSlice(p.(*schema.Set).List())
+	}
+	if p, ok := d.GetOk("disabled_components"); ok {
+		params.DisabledComponents = convertToStringSlice(p.(*schema.Set).List())
+	}
+	return params
+}
+
+func isDefaultPackageRepository(name string) bool {
+	for _, n := range defaultPackageRepositories {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+func findPackageRepository(clientConfig *ClientConfig, identifier string) (*PackageRepository, error) {
+	packageRepositories, err := clientConfig.PackageRepositories.Get()
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range packageRepositories {
+		if fmt.Sprintf("%v", p.ID) == identifier || p.Name == identifier {
+			return &p, nil
+		}
+	}
+	return nil, nil
+}
+
+func getPackageRepository(clientConfig *ClientConfig, identifier string) (*PackageRepository, error) {
+	packageRepository, err := findPackageRepository(clientConfig, identifier)
+	if err != nil {
+		return nil, err
+	}
+	if packageRepository == nil {
+		return nil, fmt.Errorf("package repository (%s) was not found", identifier)
+	}
+	return packageRepository, nil
+}
@@ -0,0 +1,347 @@
+package maas
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/maas/gomaasclient/client"
+	"github.com/maas/gomaasclient/entity"
+)
+
+func resourceMaasPartition() *schema.Resource {
+	return &schema.Resource{
+		Description:   "Provides a resource to manage a partition of a MAAS machine's block device. It must not be used for the block devices whose `partitions` are managed by a `maas_block_device` resource.",
+		CreateContext: resourcePartitionCreate,
+		ReadContext:   resourcePartitionRead,
+		UpdateContext: resourcePartitionUpdate,
+		DeleteContext: resourcePartitionDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: func(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+				idParts := strings.Split(d.Id(), ":")
+				if len(idParts) != 3 || idParts[0] == "" || idParts[1] == "" || idParts[2] == "" {
+					return nil, fmt.Errorf("unexpected format of ID (%q), expected MACHINE:BLOCK_DEVICE:PARTITION, where MACHINE is a system ID, hostname, FQDN, or MAC address, BLOCK_DEVICE is an ID or name, and PARTITION is an ID or path", d.Id())
+				}
+				client := m.(*ClientConfig).Client
+				machine, err := getMachine(client, idParts[0])
+				if err != nil {
+					return nil, err
+				}
+				blockDevice, err := getBlockDevice(client, machine.SystemID, idParts[1])
+				if err != nil {
+					return nil, err
+				}
+				var partition *entity.BlockDevicePartition
+				for _, p := range blockDevice.Partitions {
+					if isPartitionIdentifier(&p, idParts[2]) {
+						partition = &p
+						break
+					}
+				}
+				if partition == nil {
+					return nil, fmt.Errorf("partition (%s) was not found on block device (%s)", idParts[2], blockDevice.Name)
+				}
+				tfState := map[string]interface{}{
+					"id":             fmt.Sprintf("%v", partition.ID),
+					"machine":        machine.SystemID,
+					"block_device":   idParts[1],
+					"size_gigabytes": int(partition.Size / (1024 * 1024 * 1024)),
+					"bootable":       partition.Bootable,
+				}
+				if err := setTerraformState(d, tfState); err != nil {
+					return nil, err
+				}
+				return []*schema.ResourceData{d}, nil
+			},
+		},
+
+		Schema: map[string]*schema.Schema{
+			"machine": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The machine identifier (system ID, hostname, FQDN, or MAC address) that owns the partition.",
+			},
+			"block_device": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The identifier (ID, name, ID path, or path) of the block device to create the partition on.",
+			},
+			"size_gigabytes": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Computed:     true,
+				ForceNew:     true,
+				ExactlyOneOf: []string{"size", "size_gigabytes"},
+				Description:  "The partition size (given in GB). Exactly one of `size` or `size_gigabytes` must be set.",
+			},
+			"size": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Computed:         true,
+				ForceNew:         true,
+				ExactlyOneOf:     []string{"size", "size_gigabytes"},
+				ValidateDiagFunc: validation.ToDiagFunc(validateSize),
+				Description:      "The partition size as a human-readable string (e.g. `100G`, `1.5T`, `512M`). The units are powers of 1024, and the size is rounded down to the block size. Exactly one of `size` or `size_gigabytes` must be set.",
+			},
+			"bootable": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				ForceNew:    true,
+				Description: "Boolean value indicating if the partition is set as bootable.",
+			},
+			"tags": {
+				Type:     schema.TypeSet,
+				Optional: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+				Description: "The tags assigned to the partition.",
+			},
+			"fs_type": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The file system type (e.g. `ext4`). If this is not set, the partition is unformatted.",
+			},
+			"label": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				RequiredWith: []string{"fs_type"},
+				Description:  "The label assigned if the partition is formatted.",
+			},
+			"mount_point": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				RequiredWith: []string{"fs_type"},
+				Description:  "The mount point used. If this is not set, the partition is not mounted. This is used only if the partition is formatted.",
+			},
+			"mount_options": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				RequiredWith: []string{"mount_point"},
+				Description:  "The options used for the partition mount.",
+			},
+			"partition_id": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The MAAS ID of the partition. It can be used to reference the partition from other storage resources (e.g. `maas_volume_group`).",
+			},
+			"uuid": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The partition UUID.",
+			},
+			"path": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The path of the partition.",
+			},
+		},
+	}
+}
+
+func resourcePartitionCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*ClientConfig).Client
+
+	machine, err := getMachine(client, d.Get("machine").(string))
+	if err != nil {
+		return diagFromErr(err)
+	}
+	blockDevice, err := getBlockDevice(client, machine.SystemID, d.Get("block_device").(string))
+	if err != nil {
+		return diagFromErr(err)
+	}
+	size := int64(d.Get("size_gigabytes").(int)) * 1024 * 1024 * 1024
+	if s, ok := d.GetOk("size"); ok {
+		if size, err = parseSize(s.(string)); err != nil {
+			return diagFromErr(err)
+		}
+		size = roundSize(size, int64(blockDevice.BlockSize))
+	}
+	params := &entity.BlockDevicePartitionParams{
+		Size:     int(size),
+		Bootable: d.Get("bootable").(bool),
+	}
+	partition, err := client.BlockDevicePartitions.Create(machine.SystemID, blockDevice.ID, params)
+	if err != nil {
+		return diagFromErr(err)
+	}
+	d.SetId(fmt.Sprintf("%v", partition.ID))
+
+	if err := setPartitionTags(client, d, partition); err != nil {
+		return diagFromErr(err)
+	}
+	if err := setPartitionFileSystem(client, d, partition); err != nil {
+		return diagFromErr(err)
+	}
+
+	return resourcePartitionRead(ctx, d, m)
+}
+
+func resourcePartitionRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*ClientConfig).Client
+
+	machine, err := getMachine(client, d.Get("machine").(string))
+	if err != nil {
+		return diagFromErr(err)
+	}
+	blockDevice, partition, err := getPartition(client, d, machine.SystemID)
+	if err != nil {
+		return diagFromErr(err)
+	}
+	// Keep the configured sizes if they match, so they don't show a diff
+	// against the exact bytes MAAS returns
+	size := formatSize(int64(partition.Size))
+	if configuredSize := d.Get("size").(string); configuredSize != "" && isSameSize(configuredSize, int64(partition.Size), int64(blockDevice.BlockSize)) {
+		size = configuredSize
+	}
+	sizeGigabytes := int(partition.Size / (1024 * 1024 * 1024))
+	if configuredSize := d.Get("size_gigabytes").(int); isSameSize(fmt.Sprintf("%dG", configuredSize), int64(partition.Size), int64(blockDevice.BlockSize)) {
+		sizeGigabytes = configuredSize
+	}
+	tfState := map[string]interface{}{
+		"size":           size,
+		"size_gigabytes": sizeGigabytes,
+		"bootable":       partition.Bootable,
+		"tags":           partition.Tags,
+		"fs_type":        partition.FileSystem.FSType,
+		"label":          partition.FileSystem.Label,
+		"mount_point":    partition.FileSystem.MountPoint,
+		"mount_options":  partition.FileSystem.MountOptions,
+		"partition_id":   fmt.Sprintf("%v", partition.ID),
+		"uuid":           partition.UUID,
+		"path":           partition.Path,
+	}
+	if err := setTerraformState(d, tfState); err != nil {
+		return diagFromErr(err)
+	}
+
+	return nil
+}
+
+func resourcePartitionUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*ClientConfig).Client
+
+	machine, err := getMachine(client, d.Get("machine").(string))
+	if err != nil {
+		return diagFromErr(err)
+	}
+	_, partition, err := getPartition(client, d, machine.SystemID)
+	if err != nil {
+		return diagFromErr(err)
+	}
+	if d.HasChange("tags") {
+		if err := setPartitionTags(client, d, partition); err != nil {
+			return diagFromErr(err)
+		}
+	}
+	if d.HasChanges("fs_type", "label", "mount_point", "mount_options") {
+		if err := setPartitionFileSystem(client, d, partition); err != nil {
+			return diagFromErr(err)
+		}
+	}
+
+	return resourcePartitionRead(ctx, d, m)
+}
+
+func resourcePartitionDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*ClientConfig).Client
+
+	machine, err := getMachine(client, d.Get("machine").(string))
+	if err != nil {
+		return diagFromErr(err)
+	}
+	_, partition, err := getPartition(client, d, machine.SystemID)
+	if err != nil {
+		return diagFromErr(err)
+	}
+	if err := client.BlockDevicePartition.Delete(machine.SystemID, partition.DeviceID, partition.ID); err != nil {
+		return diagFromErr(err)
+	}
+
+	return nil
+}
+
+// getPartition returns the partition of the resource, and its block device.
+func getPartition(client *client.Client, d *schema.ResourceData, machineID string) (*entity.BlockDevice, *entity.BlockDevicePartition, error) {
+	id, err := strconv.Atoi(d.Id())
+	if err != nil {
+		return nil, nil, err
+	}
+	blockDevice, err := getBlockDevice(client, machineID, d.Get("block_device").(string))
+	if err != nil {
+		return nil, nil, err
+	}
+	partition, err := client.BlockDevicePartition.Get(machineID, blockDevice.ID, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	return blockDevice, partition, nil
+}
+
+// setPartitionTags reconciles the partition tags with the configured ones,
+// adding and removing only the tags that differ.
+func setPartitionTags(client *client.Client, d *schema.ResourceData, partition *entity.BlockDevicePartition) error {
+	current := map[string]bool{}
+	for _, t := range partition.Tags {
+		current[t] = true
+	}
+	wanted := map[string]bool{}
+	for _, t := range d.Get("tags").(*schema.Set).List() {
+		wanted[t.(string)] = true
+		if !current[t.(string)] {
+			if _, err := client.BlockDevicePartition.AddTag(partition.SystemID, partition.DeviceID, partition.ID, t.(string)); err != nil {
+				return err
+			}
+		}
+	}
+	for _, t := range partition.Tags {
+		if !wanted[t] {
+			if _, err := client.BlockDevicePartition.RemoveTag(partition.SystemID, partition.DeviceID, partition.ID, t); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// setPartitionFileSystem unmounts and unformats the partition if needed, and
+// formats and mounts it as configured.
+func setPartitionFileSystem(client *client.Client, d *schema.ResourceData, partition *entity.BlockDevicePartition) error {
+	fsType := d.Get("fs_type").(string)
+	label := d.Get("label").(string)
+	mountPoint := d.Get("mount_point").(string)
+	mountOptions := d.Get("mount_options").(string)
+	fs := partition.FileSystem
+	reformat := fs.FSType != fsType || fs.Label != label
+	if fs.MountPoint != "" && (reformat || fs.MountPoint != mountPoint || fs.MountOptions != mountOptions) {
+		if _, err := client.BlockDevicePartition.Unmount(partition.SystemID, partition.DeviceID, partition.ID); err != nil {
+			return err
+		}
+		fs.MountPoint = ""
+	}
+	if fs.FSType != "" && reformat {
+		if _, err := client.BlockDevicePartition.Unformat(partition.SystemID, partition.DeviceID, partition.ID); err != nil {
+			return err
+		}
+	}
+	if fsType == "" {
+		return nil
+	}
+	if reformat {
+		if _, err := client.BlockDevicePartition.Format(partition.SystemID, partition.DeviceID, partition.ID, fsType, label); err != nil {
+			return err
+		}
+	}
+	if mountPoint != "" && fs.MountPoint == "" {
+		if _, err := client.BlockDevicePartition.Mount(partition.SystemID, partition.DeviceID, partition.ID, mountPoint, mountOptions); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,216 @@
+package maas
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log"
+	"net"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/maas/gomaasclient/client"
+	"github.com/maas/gomaasclient/entity"
+)
+
+func resourceMaasReservedIP() *schema.Resource {
+	return &schema.Resource{
+		Description:   "Provides a resource to reserve a static IP address in MAAS.",
+		CreateContext: resourceReservedIPCreate,
+		ReadContext:   resourceReservedIPRead,
+		DeleteContext: resourceReservedIPDelete,
+		CustomizeDiff: resourceReservedIPCustomizeDiff,
+		Importer: &schema.ResourceImporter{
+			StateContext: func(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+				client := m.(*ClientConfig).Client
+				ipAddress, err := getReservedIP(client, d.Id())
+				if err != nil {
+					return nil, err
+				}
+				tfState := map[string]interface{}{
+					"id":          ipAddress.IP.String(),
+					"subnet":      getSubnetTFIdentifier(&ipAddress.Subnet, ""),
+					"ip_address":  ipAddress.IP.String(),
+					"mac_address": getReservedIPMACAddress(ipAddress, ""),
+				}
+				if err := setTerraformState(d, tfState); err != nil {
+					return nil, err
+				}
+				return []*schema.ResourceData{d}, nil
+			},
+		},
+
+		Schema: map[string]*schema.Schema{
+			"subnet": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The identifier (ID or CIDR) of the subnet of the reserved IP address. The CIDR is used when the reserved IP address is imported.",
+			},
+			"ip_address": {
+				Type:             schema.TypeString,
+				Required:         true,
+				ForceNew:         true,
+				ValidateDiagFunc: validation.ToDiagFunc(validation.IsIPAddress),
+				Description:      "The IP address to reserve. It must be part of the subnet and outside its dynamic IP ranges.",
+			},
+			"mac_address": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				ForceNew:         true,
+				ValidateDiagFunc: validation.ToDiagFunc(validation.IsMACAddress),
+				Description:      "The MAC address to be linked to the reserved IP address.",
+			},
+			"comment": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Description: "A description of the reserved IP address. MAAS doesn't return it, so it's not read back, and it's not imported.",
+			},
+		},
+	}
+}
+
+func resourceReservedIPCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*ClientConfig).Client
+
+	subnet, err := getSubnet(client, d.Get("subnet").(string))
+	if err != nil {
+		return diagFromErr(err)
+	}
+	ip := net.ParseIP(d.Get("ip_address").(string))
+	params := IPAddressReserveParams{
+		Subnet:  fmt.Sprintf("%v", subnet.ID),
+		IP:      ip.String(),
+		MAC:     d.Get("mac_address").(string),
+		Comment: d.Get("comment").(string),
+	}
+	ipAddress, err := m.(*ClientConfig).IPAddresses.Reserve(&params)
+	if err != nil {
+		return diagFromErr(err)
+	}
+	d.SetId(ipAddress.IP.String())
+
+	return resourceReservedIPRead(ctx, d, m)
+}
+
+func resourceReservedIPRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*ClientConfig).Client
+
+	ipAddress, err := findReservedIP(client, d.Id())
+	if err != nil {
+		return diagFromErr(err)
+	}
+	// The IP address was released, so it needs to be reserved again
+	if ipAddress == nil {
+		log.Printf("[DEBUG] Reserved IP address (%s) was not found, removing it from state\n", d.Id())
+		d.SetId("")
+		return nil
+	}
+	ip := d.Get("ip_address").(string)
+	if !ipAddress.IP.Equal(net.ParseIP(ip)) {
+		ip = ipAddress.IP.String()
+	}
+	tfState := map[string]interface{}{
+		"subnet":      getSubnetTFIdentifier(&ipAddress.Subnet, d.Get("subnet").(string)),
+		"ip_address":  ip,
+		"mac_address": getReservedIPMACAddress(ipAddress, d.Get("mac_address").(string)),
+	}
+	if err := setTerraformState(d, tfState); err != nil {
+		return diagFromErr(err)
+	}
+
+	return nil
+}
+
+func resourceReservedIPDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*ClientConfig).Client
+
+	if err := client.IPAddresses.Release(&entity.IPAddressesParams{IP: d.Id()}); err != nil {
+		return diagFromErr(err)
+	}
+
+	return nil
+}
+
+// resourceReservedIPCustomizeDiff checks the IP address of the new reservations.
+// The check is skipped when the subnet is not created yet.
+func resourceReservedIPCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	if d.Id() != "" && !d.HasChanges("subnet", "ip_address") {
+		return nil
+	}
+	if !d.NewValueKnown("subnet") || !d.NewValueKnown("ip_address") {
+		return nil
+	}
+	client := m.(*ClientConfig).Client
+	subnet, err := findSubnet(client, d.Get("subnet").(string))
+	if err != nil || subnet == nil {
+		return err
+	}
+	return validateReservedIP(client, subnet, net.ParseIP(d.Get("ip_address").(string)))
+}
+
+// getReservedIPMACAddress returns the MAC address linked to the reserved IP
+// address, in the configured form if it's the same address.
+func getReservedIPMACAddress(ipAddress *entity.IPAddress, configured string) string {
+	for _, n := range ipAddress.InterfaceSet {
+		if strings.EqualFold(n.MACAddress, configured) {
+			return configured
+		}
+	}
+	if len(ipAddress.InterfaceSet) == 0 {
+		return ""
+	}
+	return ipAddress.InterfaceSet[0].MACAddress
+}
+
+// validateReservedIP checks that the IP address is part of the subnet and
+// outside its dynamic IP ranges, since MAAS can't reserve it otherwise.
+func validateReservedIP(client *client.Client, subnet *entity.Subnet, ip net.IP) error {
+	_, cidr, err := net.ParseCIDR(subnet.CIDR)
+	if err != nil {
+		return err
+	}
+	if !cidr.Contains(ip) {
+		return fmt.Errorf("IP address (%s) is not part of the subnet (%s)", ip, subnet.CIDR)
+	}
+	ipRanges, err := client.IPRanges.Get()
+	if err != nil {
+		return err
+	}
+	for _, ipr := range ipRanges {
+		if ipr.Subnet.ID != subnet.ID || ipr.Type != "dynamic" {
+			continue
+		}
+		if bytes.Compare(ip.To16(), ipr.StartIP.To16()) >= 0 && bytes.Compare(ip.To16(), ipr.EndIP.To16()) <= 0 {
+			return fmt.Errorf("IP address (%s) is part of the dynamic IP range %s - %s", ip, ipr.StartIP, ipr.EndIP)
+		}
+	}
+	return nil
+}
+
+func findReservedIP(client *client.Client, ip string) (*entity.IPAddress, error) {
+	ipAddresses, err := client.IPAddresses.Get(&entity.IPAddressesParams{IP: ip})
+	if err != nil {
+		return nil, err
+	}
+	for _, ipAddress := range ipAddresses {
+		if ipAddress.IP.String() == ip {
+			return &ipAddress, nil
+		}
+	}
+	return nil, nil
+}
+
+func getReservedIP(client *client.Client, ip string) (*entity.IPAddress, error) {
+	ipAddress, err := findReservedIP(client, ip)
+	if err != nil {
+		return nil, err
+	}
+	if ipAddress == nil {
+		return nil, fmt.Errorf("reserved IP address (%s) was not found", ip)
+	}
+	return ipAddress, nil
+}
@@ -0,0 +1,166 @@
+package maas
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/maas/gomaasclient/entity"
+)
+
+func resourceMaasResourcePool() *schema.Resource {
+	return &schema.Resource{
+		Description:   "Provides a resource to manage MAAS resource pools.",
+		CreateContext: resourceResourcePoolCreate,
+		ReadContext:   resourceResourcePoolRead,
+		UpdateContext: resourceResourcePoolUpdate,
+		DeleteContext: resourceResourcePoolDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: func(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+				resourcePool, err := getResourcePool(m.(*ClientConfig), d.Id())
+				if err != nil {
+					return nil, err
+				}
+				tfState := map[string]interface{}{
+					"id":    fmt.Sprintf("%v", resourcePool.ID),
+					"force": false,
+				}
+				if err := setTerraformState(d, tfState); err != nil {
+					return nil, err
+				}
+				return []*schema.ResourceData{d}, nil
+			},
+		},
+
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The name of the resource pool.",
+			},
+			"description": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "A description of the resource pool.",
+			},
+			"force": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Boolean value indicating if the resource pool is deleted even if machines are still assigned to it. They are moved to the default resource pool first. Otherwise, deleting a resource pool with machines fails. Defaults to `false`.",
+			},
+		},
+	}
+}
+
+func resourceResourcePoolCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	resourcePool, err := m.(*ClientConfig).ResourcePools.Create(getResourcePoolParams(d))
+	if err != nil {
+		return diagFromErr(err)
+	}
+	d.SetId(fmt.Sprintf("%v", resourcePool.ID))
+
+	return resourceResourcePoolRead(ctx, d, m)
+}
+
+func resourceResourcePoolRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	resourcePool, err := findResourcePool(m.(*ClientConfig), d.Id())
+	if err != nil {
+		return diagFromErr(err)
+	}
+	if resourcePool == nil {
+		log.Printf("[DEBUG] Resource pool (%s) was not found, removing it from state\n", d.Id())
+		d.SetId("")
+		return nil
+	}
+	tfState := map[string]interface{}{
+		"name":        resourcePool.Name,
+		"description": resourcePool.Description,
+	}
+	if err := setTerraformState(d, tfState); err != nil {
+		return diagFromErr(err)
+	}
+
+	return nil
+}
+
+func resourceResourcePoolUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	id, err := strconv.Atoi(d.Id())
+	if err != nil {
+		return diagFromErr(err)
+	}
+	if _, err := m.(*ClientConfig).ResourcePools.Update(id, getResourcePoolParams(d)); err != nil {
+		return diagFromErr(err)
+	}
+
+	return resourceResourcePoolRead(ctx, d, m)
+}
+
+func resourceResourcePoolDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	clientConfig := m.(*ClientConfig)
+
+	id, err := strconv.Atoi(d.Id())
+	if err != nil {
+		return diagFromErr(err)
+	}
+	machines, err := getMachinesSystemIDs(clientConfig.Client, func(machine *entity.Machine) bool {
+		return machine.Pool.ID == id
+	})
+	if err != nil {
+		return diagFromErr(err)
+	}
+	if len(machines) > 0 {
+		if !d.Get("force").(bool) {
+			return diagFromErr(fmt.Errorf("resource pool (%s) can't be deleted, because it still has machines: %s. Move them to another resource pool, or set `force` to move them to the default resource pool", d.Get("name").(string), strings.Join(machines, ", ")))
+		}
+		defaultPool, err := getResourcePool(clientConfig, "0")
+		if err != nil {
+			return diagFromErr(err)
+		}
+		for _, systemID := range machines {
+			if _, err := clientConfig.Client.Machine.Update(systemID, &entity.MachineParams{Pool: defaultPool.Name}, map[string]string{}); err != nil {
+				return diagFromErr(err)
+			}
+		}
+	}
+	if err := clientConfig.ResourcePools.Delete(id); err != nil {
+		return diagFromErr(err)
+	}
+
+	return nil
+}
+
+func getResourcePoolParams(d *schema.ResourceData) *ResourcePoolParams {
+	return &ResourcePoolParams{
+		Name:        d.Get("name").(string),
+		Description: d.Get("description").(string),
+	}
+}
+
+func findResourcePool(clientConfig *ClientConfig, identifier string) (*entity.ResourcePool, error) {
+	resourcePools, err := clientConfig.ResourcePools.Get()
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range resourcePools {
+		if fmt.Sprintf("%v", p.ID) == identifier || p.Name == identifier {
+			return &p, nil
+		}
+	}
+	return nil, nil
+}
+
+func getResourcePool(clientConfig *ClientConfig, identifier string) (*entity.ResourcePool, error) {
+	resourcePool, err := findResourcePool(clientConfig, identifier)
+	if err != nil {
+		return nil, err
+	}
+	if resourcePool == nil {
+		return nil, fmt.Errorf("resource pool (%s) was not found", identifier)
+	}
+	return resourcePool, nil
+}
@@ -3,6 +3,7 @@ package maas
 import (
 	"context"
 	"fmt"
+	"log"
 	"strconv"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
@@ -20,7 +21,7 @@ func resourceMaasSpace() *schema.Resource {
 		DeleteContext: resourceSpaceDelete,
 		Importer: &schema.ResourceImporter{
 			StateContext: func(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
-				client := m.(*client.Client)
+				client := m.(*ClientConfig).Client
 				space, err := getSpace(client, d.Id())
 				if err != nil {
 					return nil, err
@@ -47,11 +48,11 @@ func resourceMaasSpace() *schema.Resource {
 }
 
 func resourceSpaceCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	client := m.(*client.Client)
+	client := m.(*ClientConfig).Client
 
 	space, err := client.Spaces.Create(d.Get("name").(string))
 	if err != nil {
-		return diag.FromErr(err)
+		return diagFromErr(err)
 	}
 	d.SetId(fmt.Sprintf("%v", space.ID))
 
@@ -59,42 +60,47 @@ func resourceSpaceCreate(ctx context.Context, d *schema.ResourceData, m interfac
 }
 
 func resourceSpaceRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	client := m.(*client.Client)
+	client := m.(*ClientConfig).Client
 
-	id, err := strconv.Atoi(d.Id())
+	space, err := findSpace(client, d.Id())
 	if err != nil {
-		return diag.FromErr(err)
+		return diagFromErr(err)
+	}
+	if space == nil {
+		log.Printf("[DEBUG] Space (%s) was not found, removing it from state\n", d.Id())
+		d.SetId("")
+		return nil
 	}
-	if _, err := client.Space.Get(id); err != nil {
-		return diag.FromErr(err)
+	if err := d.Set("name", space.Name); err != nil {
+		return diagFromErr(err)
 	}
 
 	return nil
 }
 
 func resourceSpaceUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	client := m.(*client.Client)
+	client := m.(*ClientConfig).Client
 
 	id, err := strconv.Atoi(d.Id())
 	if err != nil {
-		return diag.FromErr(err)
+		return diagFromErr(err)
 	}
 	if _, err := client.Space.Update(id, d.Get("name").(string)); err != nil {
-		return diag.FromErr(err)
+		return diagFromErr(err)
 	}
 
 	return resourceSpaceRead(ctx, d, m)
 }
 
 func resourceSpaceDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	client := m.(*client.Client)
+	client := m.(*ClientConfig).Client
 
 	id, err := strconv.Atoi(d.Id())
 	if err != nil {
-		return diag.FromErr(err)
+		return diagFromErr(err)
 	}
 	if err := client.Space.Delete(id); err != nil {
-		return diag.FromErr(err)
+		return diagFromErr(err)
 	}
 
 	return nil
@@ -0,0 +1,187 @@
+package maas
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func resourceMaasSpecialFilesystem() *schema.Resource {
+	return &schema.Resource{
+		Description:   "Provides a resource to mount a special file system (tmpfs or ramfs) on a MAAS machine. It can be mounted only while the machine is in the `Ready` or `Allocated` state.",
+		CreateContext: resourceSpecialFilesystemCreate,
+		ReadContext:   resourceSpecialFilesystemRead,
+		DeleteContext: resourceSpecialFilesystemDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: func(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+				idParts := strings.SplitN(d.Id(), ":", 2)
+				if len(idParts) != 2 || idParts[0] == "" || idParts[1] == "" {
+					return nil, fmt.Errorf("unexpected format of ID (%q), expected MACHINE:MOUNT_POINT, where MACHINE is a system ID, hostname, FQDN, or MAC address", d.Id())
+				}
+				clientConfig := m.(*ClientConfig)
+				machine, err := getMachine(clientConfig.Client, idParts[0])
+				if err != nil {
+					return nil, err
+				}
+				specialFilesystem, err := findSpecialFilesystem(clientConfig, machine.SystemID, idParts[1])
+				if err != nil {
+					return nil, err
+				}
+				if specialFilesystem == nil {
+					return nil, fmt.Errorf("special file system (%s) was not found on machine (%s)", idParts[1], machine.SystemID)
+				}
+				tfState := map[string]interface{}{
+					"id":          specialFilesystem.MountPoint,
+					"machine":     machine.SystemID,
+					"mount_point": specialFilesystem.MountPoint,
+				}
+				if err := setTerraformState(d, tfState); err != nil {
+					return nil, err
+				}
+				return []*schema.ResourceData{d}, nil
+			},
+		},
+
+		Schema: map[string]*schema.Schema{
+			"machine": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The machine identifier (system ID, hostname, FQDN, or MAC address) to mount the special file system on.",
+			},
+			"fs_type": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringInSlice([]string{"tmpfs", "ramfs"}, false),
+				Description:  "The special file system type. Valid options are: `tmpfs` and `ramfs`.",
+			},
+			"mount_point": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The absolute path of the mount point (e.g. `/tmp`).",
+			},
+			"size": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Description: "The maximum size of the file system (e.g. `2G` or `50%`), passed to the mount as the `size` option. It's only enforced for `tmpfs`.",
+			},
+			"mount_options": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Description: "Other comma-separated options used for the mount (e.g. `noexec,nosuid`).",
+			},
+		},
+	}
+}
+
+func resourceSpecialFilesystemCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	clientConfig := m.(*ClientConfig)
+
+	machine, err := getMachine(clientConfig.Client, d.Get("machine").(string))
+	if err != nil {
+		return diagFromErr(err)
+	}
+	mountPoint := d.Get("mount_point").(string)
+	mountOptions := getSpecialFilesystemMountOptions(d.Get("size").(string), d.Get("mount_options").(string))
+	if err := clientConfig.Machine.MountSpecial(machine.SystemID, d.Get("fs_type").(string), mountPoint, mountOptions); err != nil {
+		return diagFromErr(err)
+	}
+	d.SetId(mountPoint)
+
+	return resourceSpecialFilesystemRead(ctx, d, m)
+}
+
+func resourceSpecialFilesystemRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	clientConfig := m.(*ClientConfig)
+
+	machine, err := getMachine(clientConfig.Client, d.Get("machine").(string))
+	if err != nil {
+		return diagFromErr(err)
+	}
+	specialFilesystem, err := findSpecialFilesystem(clientConfig, machine.SystemID, d.Id())
+	if err != nil {
+		return diagFromErr(err)
+	}
+	// The special file system was unmounted, so it needs to be mounted again
+	if specialFilesystem == nil {
+		log.Printf("[DEBUG] Special file system (%s) was not found on machine (%s), removing it from state\n", d.Id(), machine.SystemID)
+		d.SetId("")
+		return nil
+	}
+	size, mountOptions := splitSpecialFilesystemMountOptions(specialFilesystem.MountOptions)
+	tfState := map[string]interface{}{
+		"fs_type":       specialFilesystem.FSType,
+		"mount_point":   specialFilesystem.MountPoint,
+		"size":          size,
+		"mount_options": mountOptions,
+	}
+	if err := setTerraformState(d, tfState); err != nil {
+		return diagFromErr(err)
+	}
+
+	return nil
+}
+
+func resourceSpecialFilesystemDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	clientConfig := m.(*ClientConfig)
+
+	machine, err := getMachine(clientConfig.Client, d.Get("machine").(string))
+	if err != nil {
+		return diagFromErr(err)
+	}
+	if err := clientConfig.Machine.UnmountSpecial(machine.SystemID, d.Id()); err != nil {
+		return diagFromErr(err)
+	}
+
+	return nil
+}
+
+func findSpecialFilesystem(clientConfig *ClientConfig, machineID string, mountPoint string) (*SpecialFilesystem, error) {
+	specialFilesystems, err := clientConfig.Machine.GetSpecialFilesystems(machineID)
+	if err != nil {
+		return nil, err
+	}
+	for _, s := range specialFilesystems {
+		if s.MountPoint == mountPoint {
+			return &s, nil
+		}
+	}
+	return nil, nil
+}
+
+// getSpecialFilesystemMountOptions returns the mount options of the special
+// file system, with the size as the first option.
+func getSpecialFilesystemMountOptions(size string, mountOptions string) string {
+	options := []string{}
+	if size != "" {
+		options = append(options, fmt.Sprintf("size=%s", size))
+	}
+	if mountOptions != "" {
+		options = append(options, mountOptions)
+	}
+	return strings.Join(options, ",")
+}
+
+// splitSpecialFilesystemMountOptions splits the size option from the other
+// mount options of the special file system.
+func splitSpecialFilesystemMountOptions(mountOptions string) (string, string) {
+	size := ""
+	options := []string{}
+	for _, option := range strings.Split(mountOptions, ",") {
+		if strings.HasPrefix(option, "size=") {
+			size = strings.TrimPrefix(option, "size=")
+		} else if option != "" {
+			options = append(options, option)
+		}
+	}
+	return size, strings.Join(options, ",")
+}
@@ -0,0 +1,63 @@
+package maas
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/maas/gomaasclient/entity"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSplitSpecialFilesystemMountOptions(t *testing.T) {
+	testCases := []struct {
+		name         string
+		mountOptions string
+		size         string
+		options      string
+	}{
+		{
+			name:         "no options",
+			mountOptions: "",
+			size:         "",
+			options:      "",
+		},
+		{
+			name:         "size only",
+			mountOptions: "size=2G",
+			size:         "2G",
+			options:      "",
+		},
+		{
+			name:         "size and other options",
+			mountOptions: "size=50%,noexec,nosuid",
+			size:         "50%",
+			options:      "noexec,nosuid",
+		},
+		{
+			name:         "size after other options",
+			mountOptions: "noexec,size=512M",
+			size:         "512M",
+			options:      "noexec",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			size, options := splitSpecialFilesystemMountOptions(testCase.mountOptions)
+			assert.Equal(t, testCase.size, size, fmt.Sprintf("splitSpecialFilesystemMountOptions(%q) size", testCase.mountOptions))
+			assert.Equal(t, testCase.options, options, fmt.Sprintf("splitSpecialFilesystemMountOptions(%q) options", testCase.mountOptions))
+		})
+	}
+}
+
+func TestIgnoreSpecialFilesystemsError(t *testing.T) {
+	var machines []entity.Machine
+	data := `[{"system_id": "abc123", "hostname": "machine-01", "special_filesystems": [{"fstype": "tmpfs", "mount_point": "/tmp"}]}]`
+	err := ignoreSpecialFilesystemsError(json.Unmarshal([]byte(data), &machines))
+	assert.NoError(t, err)
+	assert.Equal(t, "machine-01", machines[0].Hostname)
+
+	err = ignoreSpecialFilesystemsError(json.Unmarshal([]byte(`[{"hostname": 1}]`), &machines))
+	assert.Error(t, err)
+}
@@ -0,0 +1,99 @@
+package maas
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func resourceMaasSSHKey() *schema.Resource {
+	return &schema.Resource{
+		Description:   "Provides a resource to manage the SSH public keys of MAAS users.",
+		CreateContext: resourceSSHKeyCreate,
+		ReadContext:   resourceSSHKeyRead,
+		DeleteContext: resourceSSHKeyDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: func(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+				id, err := strconv.Atoi(d.Id())
+				if err != nil {
+					return nil, fmt.Errorf("unexpected format of ID (%q), expected the SSH key ID", d.Id())
+				}
+				sshKey, err := m.(*ClientConfig).SSHKeys.GetByID(id)
+				if err != nil {
+					return nil, err
+				}
+				if err := d.Set("key", sshKey.Key); err != nil {
+					return nil, err
+				}
+				return []*schema.ResourceData{d}, nil
+			},
+		},
+
+		Schema: map[string]*schema.Schema{
+			"key": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The SSH public key (e.g. the content of `~/.ssh/id_ed25519.pub`).",
+			},
+			"user": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Description: "The username of the MAAS user the SSH key is added to. This requires admin privileges, and MAAS allows only the owner to delete the key, so the key is left in MAAS with a warning when the resource is destroyed. If it's not set, the key is added to the MAAS user used by Terraform.",
+			},
+		},
+	}
+}
+
+func resourceSSHKeyCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	sshKey, err := m.(*ClientConfig).SSHKeys.Create(d.Get("key").(string), d.Get("user").(string))
+	if err != nil {
+		return diagFromErr(err)
+	}
+	d.SetId(fmt.Sprintf("%v", sshKey.ID))
+
+	return resourceSSHKeyRead(ctx, d, m)
+}
+
+func resourceSSHKeyRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	id, err := strconv.Atoi(d.Id())
+	if err != nil {
+		return diagFromErr(err)
+	}
+	if _, err := m.(*ClientConfig).SSHKeys.GetByID(id); err != nil {
+		if isNotFoundError(err) {
+			log.Printf("[DEBUG] SSH key (%s) was not found, removing it from state\n", d.Id())
+			d.SetId("")
+			return nil
+		}
+		return diagFromErr(err)
+	}
+
+	return nil
+}
+
+func resourceSSHKeyDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	id, err := strconv.Atoi(d.Id())
+	if err != nil {
+		return diagFromErr(err)
+	}
+	if err := m.(*ClientConfig).SSHKeys.Delete(id); err != nil {
+		if isForbiddenError(err) && d.Get("user").(string) != "" {
+			return diag.Diagnostics{
+				diag.Diagnostic{
+					Severity: diag.Warning,
+					Summary:  "SSH key can't be deleted",
+					Detail:   fmt.Sprintf("The SSH key (%s) belongs to the user %q, and MAAS allows only its owner to delete it. It was removed from the Terraform state, but it's left in MAAS.", d.Id(), d.Get("user").(string)),
+				},
+			}
+		}
+		return diagFromErr(err)
+	}
+
+	return nil
+}
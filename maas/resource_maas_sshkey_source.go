@@ -0,0 +1,143 @@
+package maas
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func resourceMaasSSHKeySource() *schema.Resource {
+	return &schema.Resource{
+		Description:   "Provides a resource to import the SSH keys of a Launchpad or GitHub user into the MAAS user used by Terraform.\n\n**NOTE:** The keys are imported once, when the resource is created. Destroying the resource deletes all the keys imported from the source. Changing the source imports the keys of the new one and deletes the previous ones. Use `maas_ssh_key` to manage individual keys instead.",
+		CreateContext: resourceSSHKeySourceCreate,
+		ReadContext:   resourceSSHKeySourceRead,
+		DeleteContext: resourceSSHKeySourceDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: func(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+				idParts := strings.Split(d.Id(), ":")
+				if len(idParts) != 2 || idParts[0] == "" || idParts[1] == "" {
+					return nil, fmt.Errorf("unexpected format of ID (%q), expected PROTOCOL:AUTH_ID, where PROTOCOL is `lp` or `gh`", d.Id())
+				}
+				tfState := map[string]interface{}{
+					"protocol": idParts[0],
+					"auth_id":  idParts[1],
+				}
+				if err := setTerraformState(d, tfState); err != nil {
+					return nil, err
+				}
+				return []*schema.ResourceData{d}, nil
+			},
+		},
+
+		Schema: map[string]*schema.Schema{
+			"protocol": {
+				Type:             schema.TypeString,
+				Required:         true,
+				ForceNew:         true,
+				ValidateDiagFunc: validation.ToDiagFunc(validation.StringInSlice([]string{"lp", "gh"}, false)),
+				Description:      "The SSH keys source. Valid options are: `lp` (Launchpad), `gh` (GitHub).",
+			},
+			"auth_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The username on the SSH keys source.",
+			},
+			"key_ids": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The IDs of the imported SSH keys.",
+				Elem: &schema.Schema{
+					Type: schema.TypeInt,
+				},
+			},
+			"keys": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The imported SSH public keys.",
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+		},
+	}
+}
+
+func resourceSSHKeySourceCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	sshKeys := m.(*ClientConfig).SSHKeys
+
+	keySource := fmt.Sprintf("%s:%s", d.Get("protocol").(string), d.Get("auth_id").(string))
+	if err := sshKeys.Import(keySource); err != nil {
+		return diagFromErr(err)
+	}
+	d.SetId(keySource)
+
+	return resourceSSHKeySourceRead(ctx, d, m)
+}
+
+func resourceSSHKeySourceRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	sshKeys := m.(*ClientConfig).SSHKeys
+
+	keys, err := getSourceSSHKeys(sshKeys, d.Id())
+	if err != nil {
+		return diagFromErr(err)
+	}
+	// All the keys were deleted, so they need to be imported again
+	if len(keys) == 0 {
+		log.Printf("[DEBUG] No SSH keys were found for the source (%s), removing it from state\n", d.Id())
+		d.SetId("")
+		return nil
+	}
+	keyIDs := make([]int, len(keys))
+	publicKeys := make([]string, len(keys))
+	for i, k := range keys {
+		keyIDs[i] = k.ID
+		publicKeys[i] = k.Key
+	}
+	tfState := map[string]interface{}{
+		"key_ids": keyIDs,
+		"keys":    publicKeys,
+	}
+	if err := setTerraformState(d, tfState); err != nil {
+		return diagFromErr(err)
+	}
+
+	return nil
+}
+
+func resourceSSHKeySourceDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	sshKeys := m.(*ClientConfig).SSHKeys
+
+	keys, err := getSourceSSHKeys(sshKeys, d.Id())
+	if err != nil {
+		return diagFromErr(err)
+	}
+	for _, k := range keys {
+		if err := sshKeys.Delete(k.ID); err != nil {
+			return diagFromErr(err)
+		}
+	}
+
+	return nil
+}
+
+// getSourceSSHKeys returns the SSH keys imported from the given key source
+// (e.g. `lp:username`).
+func getSourceSSHKeys(sshKeys *SSHKeys, keySource string) ([]SSHKey, error) {
+	keys, err := sshKeys.Get()
+	if err != nil {
+		return nil, err
+	}
+	result := []SSHKey{}
+	for _, k := range keys {
+		if k.KeySource == keySource {
+			result = append(result, k)
+		}
+	}
+	return result, nil
+}
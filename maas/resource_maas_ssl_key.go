@@ -0,0 +1,85 @@
+package maas
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func resourceMaasSSLKey() *schema.Resource {
+	return &schema.Resource{
+		Description:   "Provides a resource to manage the SSL keys of the MAAS user used by Terraform.",
+		CreateContext: resourceSSLKeyCreate,
+		ReadContext:   resourceSSLKeyRead,
+		DeleteContext: resourceSSLKeyDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: func(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+				id, err := strconv.Atoi(d.Id())
+				if err != nil {
+					return nil, fmt.Errorf("unexpected format of ID (%q), expected the SSL key ID", d.Id())
+				}
+				sslKey, err := m.(*ClientConfig).SSLKeys.GetByID(id)
+				if err != nil {
+					return nil, err
+				}
+				if err := d.Set("key", sslKey.Key); err != nil {
+					return nil, err
+				}
+				return []*schema.ResourceData{d}, nil
+			},
+		},
+
+		Schema: map[string]*schema.Schema{
+			"key": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Sensitive:   true,
+				Description: "The SSL key, in PEM format.",
+			},
+		},
+	}
+}
+
+func resourceSSLKeyCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	sslKey, err := m.(*ClientConfig).SSLKeys.Create(d.Get("key").(string))
+	if err != nil {
+		return diagFromErr(err)
+	}
+	d.SetId(fmt.Sprintf("%v", sslKey.ID))
+
+	return resourceSSLKeyRead(ctx, d, m)
+}
+
+func resourceSSLKeyRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	id, err := strconv.Atoi(d.Id())
+	if err != nil {
+		return diagFromErr(err)
+	}
+	if _, err := m.(*ClientConfig).SSLKeys.GetByID(id); err != nil {
+		if isNotFoundError(err) {
+			log.Printf("[DEBUG] SSL key (%s) was not found, removing it from state\n", d.Id())
+			d.SetId("")
+			return nil
+		}
+		return diagFromErr(err)
+	}
+
+	return nil
+}
+
+func resourceSSLKeyDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	id, err := strconv.Atoi(d.Id())
+	if err != nil {
+		return diagFromErr(err)
+	}
+	if err := m.(*ClientConfig).SSLKeys.Delete(id); err != nil {
+		return diagFromErr(err)
+	}
+
+	return nil
+}
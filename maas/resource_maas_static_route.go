@@ -0,0 +1,184 @@
+package maas
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func resourceMaasStaticRoute() *schema.Resource {
+	return &schema.Resource{
+		Description:   "Provides a resource to manage MAAS static routes between subnets.",
+		CreateContext: resourceStaticRouteCreate,
+		ReadContext:   resourceStaticRouteRead,
+		UpdateContext: resourceStaticRouteUpdate,
+		DeleteContext: resourceStaticRouteDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: func(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+				id, err := strconv.Atoi(d.Id())
+				if err != nil {
+					return nil, fmt.Errorf("unexpected format of ID (%q), expected the static route ID", d.Id())
+				}
+				staticRoute, err := m.(*ClientConfig).StaticRoutes.GetByID(id)
+				if err != nil {
+					return nil, err
+				}
+				tfState := map[string]interface{}{
+					"id":          fmt.Sprintf("%v", staticRoute.ID),
+					"source":      fmt.Sprintf("%v", staticRoute.Source.ID),
+					"destination": fmt.Sprintf("%v", staticRoute.Destination.ID),
+				}
+				if err := setTerraformState(d, tfState); err != nil {
+					return nil, err
+				}
+				return []*schema.ResourceData{d}, nil
+			},
+		},
+
+		Schema: map[string]*schema.Schema{
+			"source": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The identifier (ID or CIDR) of the source subnet of the static route.",
+			},
+			"destination": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The identifier (ID or CIDR) of the destination subnet of the static route.",
+			},
+			"gateway_ip": {
+				Type:             schema.TypeString,
+				Required:         true,
+				ValidateDiagFunc: validation.ToDiagFunc(validation.IsIPAddress),
+				DiffSuppressFunc: suppressEquivalentIPDiff,
+				Description:      "The IP address of the gateway on the source subnet used to reach the destination subnet.",
+			},
+			"metric": {
+				Type:             schema.TypeInt,
+				Optional:         true,
+				Default:          0,
+				ValidateDiagFunc: validation.ToDiagFunc(validation.IntAtLeast(0)),
+				Description:      "The weight of the static route on the deployed machines. Defaults to `0`.",
+			},
+		},
+	}
+}
+
+func resourceStaticRouteCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	clientConfig := m.(*ClientConfig)
+
+	params, err := getStaticRouteParams(clientConfig, d)
+	if err != nil {
+		return diagFromErr(err)
+	}
+	staticRoute, err := clientConfig.StaticRoutes.Create(params)
+	if err != nil {
+		return diagFromErr(err)
+	}
+	d.SetId(fmt.Sprintf("%v", staticRoute.ID))
+
+	return resourceStaticRouteRead(ctx, d, m)
+}
+
+func resourceStaticRouteRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	id, err := strconv.Atoi(d.Id())
+	if err != nil {
+		return diagFromErr(err)
+	}
+	staticRoute, err := findStaticRoute(m.(*ClientConfig), id)
+	if err != nil {
+		return diagFromErr(err)
+	}
+	if staticRoute == nil {
+		log.Printf("[DEBUG] Static route (%s) was not found, removing it from state\n", d.Id())
+		d.SetId("")
+		return nil
+	}
+	tfState := map[string]interface{}{
+		"source":      getStaticRouteSubnetTFState(d.Get("source").(string), staticRoute.Source.ID, staticRoute.Source.CIDR),
+		"destination": getStaticRouteSubnetTFState(d.Get("destination").(string), staticRoute.Destination.ID, staticRoute.Destination.CIDR),
+		"gateway_ip":  staticRoute.GatewayIP,
+		"metric":      staticRoute.Metric,
+	}
+	if err := setTerraformState(d, tfState); err != nil {
+		return diagFromErr(err)
+	}
+
+	return nil
+}
+
+func resourceStaticRouteUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	clientConfig := m.(*ClientConfig)
+
+	id, err := strconv.Atoi(d.Id())
+	if err != nil {
+		return diagFromErr(err)
+	}
+	params, err := getStaticRouteParams(clientConfig, d)
+	if err != nil {
+		return diagFromErr(err)
+	}
+	if _, err := clientConfig.StaticRoutes.Update(id, params); err != nil {
+		return diagFromErr(err)
+	}
+
+	return resourceStaticRouteRead(ctx, d, m)
+}
+
+func resourceStaticRouteDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	id, err := strconv.Atoi(d.Id())
+	if err != nil {
+		return diagFromErr(err)
+	}
+	if err := m.(*ClientConfig).StaticRoutes.Delete(id); err != nil {
+		return diagFromErr(err)
+	}
+
+	return nil
+}
+
+func getStaticRouteParams(clientConfig *ClientConfig, d *schema.ResourceData) (*StaticRouteParams, error) {
+	source, err := getSubnet(clientConfig.Client, d.Get("source").(string))
+	if err != nil {
+		return nil, err
+	}
+	destination, err := getSubnet(clientConfig.Client, d.Get("destination").(string))
+	if err != nil {
+		return nil, err
+	}
+	return &StaticRouteParams{
+		Source:      fmt.Sprintf("%v", source.ID),
+		Destination: fmt.Sprintf("%v", destination.ID),
+		GatewayIP:   d.Get("gateway_ip").(string),
+		Metric:      d.Get("metric").(int),
+	}, nil
+}
+
+// getStaticRouteSubnetTFState keeps the configured subnet identifier if it
+// still refers to the same subnet, and falls back to the subnet ID otherwise.
+func getStaticRouteSubnetTFState(configured string, id int, cidr string) string {
+	if configured == cidr {
+		return cidr
+	}
+	return fmt.Sprintf("%v", id)
+}
+
+func findStaticRoute(clientConfig *ClientConfig, id int) (*StaticRoute, error) {
+	staticRoutes, err := clientConfig.StaticRoutes.Get()
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range staticRoutes {
+		if r.ID == id {
+			return &r, nil
+		}
+	}
+	return nil, nil
+}
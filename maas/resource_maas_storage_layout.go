@@ -0,0 +1,174 @@
+package maas
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func resourceMaasStorageLayout() *schema.Resource {
+	return &schema.Resource{
+		Description:   "Provides a resource to apply a base storage layout to a MAAS machine, before its custom storage (e.g. partitions) is configured.\n\n**NOTE:** Applying a storage layout replaces the whole storage configuration of the machine, so this resource should be applied before the other storage resources of the machine, by referencing it in their `depends_on`. The machine must be `Ready` or `Allocated`. Destroying this resource doesn't change the machine storage.",
+		CreateContext: resourceStorageLayoutCreate,
+		ReadContext:   resourceStorageLayoutRead,
+		DeleteContext: resourceStorageLayoutDelete,
+		CustomizeDiff: resourceStorageLayoutCustomizeDiff,
+
+		Schema: map[string]*schema.Schema{
+			"machine": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The identifier (system ID, hostname, FQDN, or MAC address) of the machine.",
+			},
+			"layout": {
+				Type:             schema.TypeString,
+				Required:         true,
+				ForceNew:         true,
+				ValidateDiagFunc: validation.ToDiagFunc(validation.StringInSlice([]string{"flat", "lvm", "bcache", "vmfs6", "vmfs7", "blank", "custom"}, false)),
+				Description:      "The storage layout. Valid options are: `flat`, `lvm`, `bcache`, `vmfs6`, `vmfs7`, `blank`, `custom`. The `custom` layout is read from the output of the machine commissioning scripts.",
+			},
+			"boot_size": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				ForceNew:         true,
+				ValidateDiagFunc: validation.ToDiagFunc(validateSize),
+				Description:      "The size of the boot partition (e.g. `512M`). If this is not set, the MAAS default is used.",
+			},
+			"root_size": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				ForceNew:         true,
+				ValidateDiagFunc: validation.ToDiagFunc(validateSize),
+				Description:      "The size of the root partition (e.g. `100G`). If this is not set, the whole root device is used.",
+			},
+			"root_device": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Description: "The block device (ID or name) used for the root partition. If this is not set, the boot disk is used.",
+			},
+			"vg_name": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Description: "The name of the volume group created by the `lvm` layout. If this is not set, the MAAS default is used.",
+			},
+			"lv_name": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Description: "The name of the root logical volume created by the `lvm` layout. If this is not set, the MAAS default is used.",
+			},
+			"lv_size": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				ForceNew:         true,
+				ValidateDiagFunc: validation.ToDiagFunc(validateSize),
+				Description:      "The size of the root logical volume created by the `lvm` layout (e.g. `50G`). If this is not set, the whole volume group is used.",
+			},
+			"cache_device": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Description: "The block device (ID or name) used as cache by the `bcache` layout. If this is not set, MAAS uses the smallest SSD.",
+			},
+			"cache_mode": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				ForceNew:         true,
+				ValidateDiagFunc: validation.ToDiagFunc(validation.StringInSlice([]string{"writeback", "writethrough", "writearound"}, false)),
+				Description:      "The cache mode of the `bcache` layout. Valid options are: `writeback`, `writethrough`, `writearound`. If this is not set, the MAAS default is used.",
+			},
+			"cache_size": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				ForceNew:         true,
+				ValidateDiagFunc: validation.ToDiagFunc(validateSize),
+				Description:      "The size of the cache partition created by the `bcache` layout (e.g. `100G`). If this is not set, the whole cache device is used.",
+			},
+			"cache_no_part": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				ForceNew:    true,
+				Description: "Boolean value indicating if the whole cache device is used by the `bcache` layout, without partitioning it.",
+			},
+		},
+	}
+}
+
+func resourceStorageLayoutCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*ClientConfig).Client
+
+	machine, err := getMachine(client, d.Get("machine").(string))
+	if err != nil {
+		return diagFromErr(err)
+	}
+	params := MachineStorageLayoutParams{
+		StorageLayout: d.Get("layout").(string),
+		RootDevice:    d.Get("root_device").(string),
+		VGName:        d.Get("vg_name").(string),
+		LVName:        d.Get("lv_name").(string),
+		CacheDevice:   d.Get("cache_device").(string),
+		CacheMode:     d.Get("cache_mode").(string),
+		CacheNoPart:   d.Get("cache_no_part").(bool),
+	}
+	sizes := map[string]*int64{
+		"boot_size":  &params.BootSize,
+		"root_size":  &params.RootSize,
+		"lv_size":    &params.LVSize,
+		"cache_size": &params.CacheSize,
+	}
+	for k, size := range sizes {
+		if p, ok := d.GetOk(k); ok {
+			if *size, err = parseSize(p.(string)); err != nil {
+				return diagFromErr(err)
+			}
+		}
+	}
+	if _, err := m.(*ClientConfig).Machine.SetStorageLayout(machine.SystemID, &params); err != nil {
+		return diagFromErr(err)
+	}
+	d.SetId(machine.SystemID)
+
+	return resourceStorageLayoutRead(ctx, d, m)
+}
+
+func resourceStorageLayoutRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*ClientConfig).Client
+
+	// MAAS doesn't report the applied layout, so only the machine is checked
+	if _, err := client.Machine.Get(d.Id()); err != nil {
+		return diagFromErr(err)
+	}
+
+	return nil
+}
+
+// storageLayoutOptions are the layout-specific options, by storage layout.
+var storageLayoutOptions = map[string][]string{
+	"lvm":    {"vg_name", "lv_name", "lv_size"},
+	"bcache": {"cache_device", "cache_mode", "cache_size", "cache_no_part"},
+}
+
+func resourceStorageLayoutCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	layout := d.Get("layout").(string)
+	for optionsLayout, options := range storageLayoutOptions {
+		if optionsLayout == layout {
+			continue
+		}
+		for _, option := range options {
+			if _, ok := d.GetOk(option); ok {
+				return fmt.Errorf("%q can only be used with the %q layout", option, optionsLayout)
+			}
+		}
+	}
+	return nil
+}
+
+func resourceStorageLayoutDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	return nil
+}
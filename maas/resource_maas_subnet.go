@@ -3,6 +3,7 @@ package maas
 import (
 	"context"
 	"fmt"
+	"net"
 	"strconv"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
@@ -14,14 +15,14 @@ import (
 
 func resourceMaasSubnet() *schema.Resource {
 	return &schema.Resource{
-		Description:   "Provides a resource to manage MAAS network subnets.\n\n**NOTE:** The MAAS provider currently supports both standalone resources and in-line resources for subnet IP ranges. You cannot use in-line `ip_ranges` in conjunction with standalone `maas_subnet_ip_range` resources. Doing so will cause conflicts and will overwrite subnet IP ranges.",
+		Description:   "Provides a resource to manage MAAS network subnets.\n\n**NOTE:** The MAAS provider currently supports both standalone resources and in-line resources for subnet IP ranges. You cannot use in-line `ip_ranges` in conjunction with standalone `maas_subnet_ip_range` resources for the same ranges. The in-line `ip_ranges` only create, update, and delete the ranges they manage, so the other ranges of the subnet (e.g. the ones reserved by MAAS itself) are left unchanged.",
 		CreateContext: resourceSubnetCreate,
 		ReadContext:   resourceSubnetRead,
 		UpdateContext: resourceSubnetUpdate,
 		DeleteContext: resourceSubnetDelete,
 		Importer: &schema.ResourceImporter{
 			StateContext: func(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
-				client := m.(*client.Client)
+				client := m.(*ClientConfig).Client
 				subnet, err := getSubnet(client, d.Id())
 				if err != nil {
 					return nil, err
@@ -35,6 +36,7 @@ func resourceMaasSubnet() *schema.Resource {
 					"rdns_mode":   subnet.RDNSMode,
 					"allow_dns":   subnet.AllowDNS,
 					"allow_proxy": subnet.AllowProxy,
+					"space":       getSubnetSpaceTFState(subnet.Space),
 				}
 				if err := setTerraformState(d, tfState); err != nil {
 					return nil, err
@@ -54,6 +56,11 @@ func resourceMaasSubnet() *schema.Resource {
 				Optional:    true,
 				Description: "The subnet name.",
 			},
+			"description": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The subnet description.",
+			},
 			"fabric": {
 				Type:        schema.TypeString,
 				Optional:    true,
@@ -65,10 +72,16 @@ func resourceMaasSubnet() *schema.Resource {
 				RequiredWith: []string{"fabric"},
 				Description:  "The VLAN identifier (ID or traffic segregation ID) for the new subnet. If this is set, the `fabric` argument is required.",
 			},
+			"space": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				Description: "The name of the space of the subnet. In MAAS, the space is a property of the VLAN, so setting it moves the subnet VLAN (and all its other subnets) to the given space. It's empty if the subnet VLAN has no space. This argument is computed if it's not set.",
+			},
 			"ip_ranges": {
 				Type:        schema.TypeSet,
 				Optional:    true,
-				Description: "A set of IP ranges configured on the new subnet. Parameters defined below. This argument is processed in [attribute-as-blocks mode](https://www.terraform.io/docs/configuration/attr-as-blocks.html).",
+				Description: "A set of IP ranges configured on the new subnet. Only the ranges added to or removed from this set are created or deleted, and the other ranges of the subnet are left unchanged. Parameters defined below. This argument is processed in [attribute-as-blocks mode](https://www.terraform.io/docs/configuration/attr-as-blocks.html).",
 				Elem: &schema.Resource{
 					Schema: map[string]*schema.Schema{
 						"type": {
@@ -116,6 +129,18 @@ func resourceMaasSubnet() *schema.Resource {
 				Default:     true,
 				Description: "Boolean value that indicates if `maas-proxy` allows requests from this subnet. Defaults to `true`.",
 			},
+			"active_discovery": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Boolean value that indicates if MAAS actively scans this subnet to discover the hosts on it. Defaults to `false`.",
+			},
+			"managed": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+				Description: "Boolean value that indicates if MAAS manages this subnet. An unmanaged subnet allocates the IP addresses only from its reserved IP ranges. Defaults to `true`.",
+			},
 			"gateway_ip": {
 				Type:             schema.TypeString,
 				Optional:         true,
@@ -138,15 +163,15 @@ func resourceMaasSubnet() *schema.Resource {
 }
 
 func resourceSubnetCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	client := m.(*client.Client)
+	client := m.(*ClientConfig).Client
 
 	params, err := getSubnetParams(client, d)
 	if err != nil {
-		return diag.FromErr(err)
+		return diagFromErr(err)
 	}
 	subnet, err := client.Subnets.Create(params)
 	if err != nil {
-		return diag.FromErr(err)
+		return diagFromErr(err)
 	}
 	d.SetId(fmt.Sprintf("%v", subnet.ID))
 
@@ -154,15 +179,15 @@ func resourceSubnetCreate(ctx context.Context, d *schema.ResourceData, m interfa
 }
 
 func resourceSubnetRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	client := m.(*client.Client)
+	client := m.(*ClientConfig).Client
 
 	id, err := strconv.Atoi(d.Id())
 	if err != nil {
-		return diag.FromErr(err)
+		return diagFromErr(err)
 	}
 	subnet, err := client.Subnet.Get(id)
 	if err != nil {
-		return diag.FromErr(err)
+		return diagFromErr(err)
 	}
 	gatewayIp := subnet.GatewayIP.String()
 	if gatewayIp == "<nil>" {
@@ -173,71 +198,115 @@ func resourceSubnetRead(ctx context.Context, d *schema.ResourceData, m interface
 		dnsServers[i] = ip.String()
 	}
 	tfState := map[string]interface{}{
-		"gateway_ip":  gatewayIp,
-		"dns_servers": dnsServers,
+		"cidr":             subnet.CIDR,
+		"name":             subnet.Name,
+		"rdns_mode":        subnet.RDNSMode,
+		"allow_dns":        subnet.AllowDNS,
+		"allow_proxy":      subnet.AllowProxy,
+		"active_discovery": subnet.ActiveDiscovery,
+		"managed":          subnet.Managed,
+		"gateway_ip":       gatewayIp,
+		"dns_servers":      dnsServers,
+		"space":            getSubnetSpaceTFState(subnet.Space),
+	}
+	if tfState["description"], err = m.(*ClientConfig).Subnet.GetDescription(id); err != nil {
+		return diagFromErr(err)
+	}
+	if _, ok := d.GetOk("ip_ranges"); ok {
+		ipRanges, err := getSubnetIPRangesTFState(client, d, id)
+		if err != nil {
+			return diagFromErr(err)
+		}
+		tfState["ip_ranges"] = ipRanges
 	}
 	if err := setTerraformState(d, tfState); err != nil {
-		return diag.FromErr(err)
+		return diagFromErr(err)
 	}
 
 	return nil
 }
 
 func resourceSubnetUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	client := m.(*client.Client)
+	client := m.(*ClientConfig).Client
 
 	id, err := strconv.Atoi(d.Id())
 	if err != nil {
-		return diag.FromErr(err)
+		return diagFromErr(err)
 	}
 	params, err := getSubnetParams(client, d)
 	if err != nil {
-		return diag.FromErr(err)
+		return diagFromErr(err)
 	}
-	if _, err := client.Subnet.Update(id, params); err != nil {
-		return diag.FromErr(err)
+	subnet, err := client.Subnet.Update(id, params)
+	if err != nil {
+		return diagFromErr(err)
+	}
+	if activeDiscovery := d.Get("active_discovery").(bool); subnet.ActiveDiscovery != activeDiscovery {
+		if _, err := m.(*ClientConfig).Subnet.SetActiveDiscovery(id, activeDiscovery); err != nil {
+			return diagFromErr(err)
+		}
+	}
+	if d.HasChange("description") {
+		if err := m.(*ClientConfig).Subnet.SetDescription(id, d.Get("description").(string)); err != nil {
+			return diagFromErr(err)
+		}
 	}
 	if err := updateIPRanges(client, d, id); err != nil {
-		return diag.FromErr(err)
+		return diagFromErr(err)
+	}
+	// The space is computed, so it's only set when it's changed in the config
+	if p := d.Get("space").(string); p != "" && d.HasChange("space") {
+		if err := setSubnetSpace(client, id, p); err != nil {
+			return diagFromErr(err)
+		}
 	}
 
 	return resourceSubnetRead(ctx, d, m)
 }
 
 func resourceSubnetDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	client := m.(*client.Client)
+	client := m.(*ClientConfig).Client
 
 	id, err := strconv.Atoi(d.Id())
 	if err != nil {
-		return diag.FromErr(err)
+		return diagFromErr(err)
 	}
 	if err := client.Subnet.Delete(id); err != nil {
-		return diag.FromErr(err)
+		return diagFromErr(err)
 	}
 
 	return nil
 }
 
+// updateIPRanges reconciles the IP ranges of the subnet with the configured
+// ones. Only the delta is applied, and the ranges not managed by Terraform
+// (e.g. the ones reserved by MAAS itself) are left unchanged.
 func updateIPRanges(client *client.Client, d *schema.ResourceData, subnetID int) error {
-	p, ok := d.GetOk("ip_ranges")
-	if !ok {
+	if _, ok := d.GetOk("ip_ranges"); !ok && !d.HasChange("ip_ranges") {
 		return nil
 	}
-	// Removing existing IP ranges on this subnet
-	ipRanges, err := client.IPRanges.Get()
+	existing, err := getSubnetIPRangesByKey(client, subnetID)
 	if err != nil {
 		return err
 	}
-	for _, ipr := range ipRanges {
-		if ipr.Subnet.ID != subnetID {
-			continue
-		}
-		if err := client.IPRange.Delete(ipr.ID); err != nil {
-			return err
+	oldRanges, newRanges := d.GetChange("ip_ranges")
+	wanted := map[string]bool{}
+	for _, i := range newRanges.(*schema.Set).List() {
+		ipr := i.(map[string]interface{})
+		wanted[getIPRangeKey(ipr["type"].(string), ipr["start_ip"].(string), ipr["end_ip"].(string))] = true
+	}
+	// Only the ranges previously managed by Terraform are removed. They're
+	// removed first, so the changed ranges don't overlap their old versions.
+	for _, i := range oldRanges.(*schema.Set).List() {
+		ipr := i.(map[string]interface{})
+		key := getIPRangeKey(ipr["type"].(string), ipr["start_ip"].(string), ipr["end_ip"].(string))
+		if found, ok := existing[key]; ok && !wanted[key] {
+			if err := client.IPRange.Delete(found.ID); err != nil {
+				return err
+			}
 		}
 	}
-	// Create the new IP ranges on this subnet
-	for _, i := range p.(*schema.Set).List() {
+	for _, i := range newRanges.(*schema.Set).List() {
 		ipr := i.(map[string]interface{})
 		params := entity.IPRangeParams{
 			Subnet:  fmt.Sprintf("%v", subnetID),
@@ -246,13 +315,101 @@ func updateIPRanges(client *client.Client, d *schema.ResourceData, subnetID int)
 			EndIP:   ipr["end_ip"].(string),
 			Comment: ipr["comment"].(string),
 		}
-		if _, err := client.IPRanges.Create(&params); err != nil {
-			return err
+		found, ok := existing[getIPRangeKey(params.Type, params.StartIP, params.EndIP)]
+		if !ok {
+			if _, err := client.IPRanges.Create(&params); err != nil {
+				return err
+			}
+			continue
+		}
+		if found.Comment != params.Comment {
+			if _, err := client.IPRange.Update(found.ID, &params); err != nil {
+				return err
+			}
 		}
 	}
 	return nil
 }
 
+// getSubnetIPRangesTFState returns the IP ranges of the state which still
+// exist in MAAS, so the ranges deleted out of band show as a diff.
+func getSubnetIPRangesTFState(client *client.Client, d *schema.ResourceData, subnetID int) ([]map[string]interface{}, error) {
+	existing, err := getSubnetIPRangesByKey(client, subnetID)
+	if err != nil {
+		return nil, err
+	}
+	ipRanges := []map[string]interface{}{}
+	for _, i := range d.Get("ip_ranges").(*schema.Set).List() {
+		ipr := i.(map[string]interface{})
+		found, ok := existing[getIPRangeKey(ipr["type"].(string), ipr["start_ip"].(string), ipr["end_ip"].(string))]
+		if !ok {
+			continue
+		}
+		ipRanges = append(ipRanges, map[string]interface{}{
+			"type":     found.Type,
+			"start_ip": ipr["start_ip"],
+			"end_ip":   ipr["end_ip"],
+			"comment":  found.Comment,
+		})
+	}
+	return ipRanges, nil
+}
+
+func getSubnetIPRangesByKey(client *client.Client, subnetID int) (map[string]entity.IPRange, error) {
+	ipRanges, err := client.IPRanges.Get()
+	if err != nil {
+		return nil, err
+	}
+	result := map[string]entity.IPRange{}
+	for _, ipr := range ipRanges {
+		if ipr.Subnet.ID == subnetID {
+			result[getIPRangeKey(ipr.Type, ipr.StartIP.String(), ipr.EndIP.String())] = ipr
+		}
+	}
+	return result, nil
+}
+
+func getIPRangeKey(rangeType string, startIP string, endIP string) string {
+	return fmt.Sprintf("%s %s %s", rangeType, net.ParseIP(startIP), net.ParseIP(endIP))
+}
+
+// setSubnetSpace moves the VLAN of the given subnet to the given space, since
+// MAAS doesn't allow setting the space of a subnet directly. The VLAN is only
+// updated if it's not already in the space.
+func setSubnetSpace(client *client.Client, subnetID int, identifier string) error {
+	space, err := getSpace(client, identifier)
+	if err != nil {
+		return err
+	}
+	subnet, err := client.Subnet.Get(subnetID)
+	if err != nil {
+		return err
+	}
+	if subnet.VLAN.Space == space.Name {
+		return nil
+	}
+	// The current VLAN settings are sent along, so DHCP isn't disabled
+	params := entity.VLANParams{
+		VID:         subnet.VLAN.VID,
+		MTU:         subnet.VLAN.MTU,
+		Name:        subnet.VLAN.Name,
+		Description: subnet.VLAN.Description,
+		Space:       space.Name,
+		DHCPOn:      subnet.VLAN.DHCPOn,
+	}
+	_, err = client.VLAN.Update(subnet.VLAN.FabricID, subnet.VLAN.VID, &params)
+	return err
+}
+
+// getSubnetSpaceTFState returns the space of the subnet as it's stored in the
+// state. MAAS reports a subnet without space as being in the `undefined` one.
+func getSubnetSpaceTFState(space string) string {
+	if space == "undefined" {
+		return ""
+	}
+	return space
+}
+
 func getSubnetParams(client *client.Client, d *schema.ResourceData) (*entity.SubnetParams, error) {
 	params := entity.SubnetParams{
 		CIDR:       d.Get("cidr").(string),
@@ -262,7 +419,7 @@ func getSubnetParams(client *client.Client, d *schema.ResourceData) (*entity.Sub
 		AllowProxy: d.Get("allow_proxy").(bool),
 		GatewayIP:  d.Get("gateway_ip").(string),
 		DNSServers: convertToStringSlice(d.Get("dns_servers")),
-		Managed:    true,
+		Managed:    d.Get("managed").(bool),
 	}
 	if p, ok := d.GetOk("fabric"); ok {
 		fabric, err := getFabric(client, p.(string))
@@ -282,6 +439,16 @@ func getSubnetParams(client *client.Client, d *schema.ResourceData) (*entity.Sub
 	return &params, nil
 }
 
+// getSubnetTFIdentifier returns the configured identifier if it refers to the
+// given subnet, so it doesn't show a diff. Otherwise, the subnet CIDR is
+// returned.
+func getSubnetTFIdentifier(subnet *entity.Subnet, configured string) string {
+	if fmt.Sprintf("%v", subnet.ID) == configured || subnet.CIDR == configured {
+		return configured
+	}
+	return subnet.CIDR
+}
+
 func findSubnet(client *client.Client, identifier string) (*entity.Subnet, error) {
 	subnets, err := client.Subnets.Get()
 	if err != nil {
@@ -22,7 +22,7 @@ func resourceMaasSubnetIPRange() *schema.Resource {
 		DeleteContext: resourceSubnetIPRangeDelete,
 		Importer: &schema.ResourceImporter{
 			StateContext: func(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
-				client := m.(*client.Client)
+				client := m.(*ClientConfig).Client
 				idParts := strings.Split(d.Id(), ":")
 				var ipRange *entity.IPRange
 				var err error
@@ -74,12 +74,14 @@ func resourceMaasSubnetIPRange() *schema.Resource {
 				Type:             schema.TypeString,
 				Required:         true,
 				ValidateDiagFunc: validation.ToDiagFunc(validation.IsIPAddress),
+				DiffSuppressFunc: suppressEquivalentIPDiff,
 				Description:      "The start IP for the new IP range (inclusive).",
 			},
 			"end_ip": {
 				Type:             schema.TypeString,
 				Required:         true,
 				ValidateDiagFunc: validation.ToDiagFunc(validation.IsIPAddress),
+				DiffSuppressFunc: suppressEquivalentIPDiff,
 				Description:      "The end IP for the new IP range (inclusive).",
 			},
 			"comment": {
@@ -93,15 +95,15 @@ func resourceMaasSubnetIPRange() *schema.Resource {
 }
 
 func resourceSubnetIPRangeCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	client := m.(*client.Client)
+	client := m.(*ClientConfig).Client
 
 	subnet, err := findSubnet(client, d.Get("subnet").(string))
 	if err != nil {
-		return diag.FromErr(err)
+		return diagFromErr(err)
 	}
 	ipRange, err := client.IPRanges.Create(getSubnetIPRangeParams(d, subnet.ID))
 	if err != nil {
-		return diag.FromErr(err)
+		return diagFromErr(err)
 	}
 	d.SetId(fmt.Sprintf("%v", ipRange.ID))
 
@@ -109,53 +111,61 @@ func resourceSubnetIPRangeCreate(ctx context.Context, d *schema.ResourceData, m
 }
 
 func resourceSubnetIPRangeRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	client := m.(*client.Client)
+	client := m.(*ClientConfig).Client
 
 	id, err := strconv.Atoi(d.Id())
 	if err != nil {
-		return diag.FromErr(err)
+		return diagFromErr(err)
 	}
 	ipRange, err := client.IPRange.Get(id)
 	if err != nil {
-		return diag.FromErr(err)
+		return diagFromErr(err)
+	}
+	subnet := d.Get("subnet").(string)
+	if subnet != fmt.Sprintf("%v", ipRange.Subnet.ID) && subnet != ipRange.Subnet.CIDR {
+		subnet = fmt.Sprintf("%v", ipRange.Subnet.ID)
 	}
 	tfState := map[string]interface{}{
-		"comment": ipRange.Comment,
+		"subnet":   subnet,
+		"type":     ipRange.Type,
+		"start_ip": ipRange.StartIP.String(),
+		"end_ip":   ipRange.EndIP.String(),
+		"comment":  ipRange.Comment,
 	}
 	if err := setTerraformState(d, tfState); err != nil {
-		return diag.FromErr(err)
+		return diagFromErr(err)
 	}
 
 	return nil
 }
 
 func resourceSubnetIPRangeUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	client := m.(*client.Client)
+	client := m.(*ClientConfig).Client
 
 	id, err := strconv.Atoi(d.Id())
 	if err != nil {
-		return diag.FromErr(err)
+		return diagFromErr(err)
 	}
 	subnet, err := findSubnet(client, d.Get("subnet").(string))
 	if err != nil {
-		return diag.FromErr(err)
+		return diagFromErr(err)
 	}
 	if _, err := client.IPRange.Update(id, getSubnetIPRangeParams(d, subnet.ID)); err != nil {
-		return diag.FromErr(err)
+		return diagFromErr(err)
 	}
 
 	return resourceSubnetIPRangeRead(ctx, d, m)
 }
 
 func resourceSubnetIPRangeDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	client := m.(*client.Client)
+	client := m.(*ClientConfig).Client
 
 	id, err := strconv.Atoi(d.Id())
 	if err != nil {
-		return diag.FromErr(err)
+		return diagFromErr(err)
 	}
 	if err := client.IPRange.Delete(id); err != nil {
-		return diag.FromErr(err)
+		return diagFromErr(err)
 	}
 
 	return nil
@@ -3,6 +3,7 @@ package maas
 import (
 	"context"
 	"fmt"
+	"log"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -19,7 +20,7 @@ func resourceMaasTag() *schema.Resource {
 		DeleteContext: resourceTagDelete,
 		Importer: &schema.ResourceImporter{
 			StateContext: func(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
-				client := m.(*client.Client)
+				client := m.(*ClientConfig).Client
 				tag, err := getTag(client, d.Id())
 				if err != nil {
 					return nil, err
@@ -37,6 +38,10 @@ func resourceMaasTag() *schema.Resource {
 					"name":     tag.Name,
 					"machines": machinesSystemIDs,
 				}
+				if tag.Definition != "" {
+					// The machines of the automatic tags are managed by MAAS
+					tfState["machines"] = nil
+				}
 				if err := setTerraformState(d, tfState); err != nil {
 					return nil, err
 				}
@@ -54,7 +59,37 @@ func resourceMaasTag() *schema.Resource {
 			"machines": {
 				Type:        schema.TypeSet,
 				Optional:    true,
-				Description: "List of MAAS machines' identifiers (system ID, hostname, or FQDN) that will be tagged with the new tag.",
+				Description: "List of MAAS machines' identifiers (system ID, hostname, FQDN, or MAC address) that will be tagged with the new tag.",
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+				ConflictsWith: []string{"definition"},
+			},
+			"definition": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				Description:   "An XPath query evaluated against the machines hardware details. The machines matching it are tagged automatically by MAAS.",
+				ConflictsWith: []string{"machines"},
+			},
+			"comment": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "A description of the tag.",
+			},
+			"kernel_opts": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The kernel command line options added to the machines with the tag when they are booted.",
+			},
+			"rebuild": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Description: "Boolean value indicating if the tag is rebuilt (its definition is evaluated again against all the machines) after it's created or its definition is changed. It can be disabled to avoid the evaluation cost on large fleets. Defaults to `true` when `definition` is set.",
+			},
+			"matched_machines": {
+				Type:        schema.TypeSet,
+				Computed:    true,
+				Description: "The system IDs of the machines currently tagged with the tag.",
 				Elem: &schema.Schema{
 					Type: schema.TypeString,
 				},
@@ -64,17 +99,17 @@ func resourceMaasTag() *schema.Resource {
 }
 
 func resourceTagCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	client := m.(*client.Client)
+	client := m.(*ClientConfig).Client
 
 	params := getTagCreateParams(d)
 	tag, err := findTag(client, params.Name)
 	if err != nil {
-		return diag.FromErr(err)
+		return diagFromErr(err)
 	}
 	if tag == nil {
 		tag, err = client.Tags.Create(params)
 		if err != nil {
-			return diag.FromErr(err)
+			return diagFromErr(err)
 		}
 	}
 	d.SetId(tag.Name)
@@ -83,32 +118,67 @@ func resourceTagCreate(ctx context.Context, d *schema.ResourceData, m interface{
 }
 
 func resourceTagRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	client := m.(*client.Client)
+	client := m.(*ClientConfig).Client
 
-	if _, err := client.Tag.Get(d.Id()); err != nil {
-		return diag.FromErr(err)
+	tag, err := findTag(client, d.Id())
+	if err != nil {
+		return diagFromErr(err)
+	}
+	if tag == nil {
+		log.Printf("[DEBUG] Tag (%s) was not found, removing it from state\n", d.Id())
+		d.SetId("")
+		return nil
+	}
+	machines, err := client.Tag.GetMachines(tag.Name)
+	if err != nil {
+		return diagFromErr(err)
+	}
+	machinesSystemIDs := make([]string, len(machines))
+	for i, machine := range machines {
+		machinesSystemIDs[i] = machine.SystemID
+	}
+	tfState := map[string]interface{}{
+		"definition":       tag.Definition,
+		"comment":          tag.Comment,
+		"kernel_opts":      tag.KernelOpts,
+		"matched_machines": machinesSystemIDs,
+	}
+	if err := setTerraformState(d, tfState); err != nil {
+		return diagFromErr(err)
 	}
 
 	return nil
 }
 
 func resourceTagUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	client := m.(*client.Client)
+	client := m.(*ClientConfig).Client
 
+	if d.HasChanges("definition", "comment", "kernel_opts") {
+		if _, err := m.(*ClientConfig).Tag.Update(d.Id(), getTagCreateParams(d)); err != nil {
+			return diagFromErr(err)
+		}
+	}
+	if d.HasChange("definition") {
+		if rebuild := d.GetRawConfig().GetAttr("rebuild"); d.Get("definition").(string) != "" && (rebuild.IsNull() || rebuild.True()) {
+			if err := m.(*ClientConfig).Tag.Rebuild(d.Id()); err != nil {
+				return diagFromErr(err)
+			}
+		}
+	}
 	tagMachinesIDs, err := getTagTFMachinesSystemIDs(client, d)
 	if err != nil {
-		return diag.FromErr(err)
+		return diagFromErr(err)
 	}
 	if len(tagMachinesIDs) > 0 {
 		// Tag specified machines
 		err := client.Tag.AddMachines(d.Id(), tagMachinesIDs)
 		if err != nil {
-			return diag.FromErr(err)
+			return diagFromErr(err)
 		}
 		// Untag previously tagged machines
 		err = untagOtherMachines(client, d.Id(), tagMachinesIDs)
 		if err != nil {
-			return diag.FromErr(err)
+			return diagFromErr(err)
 		}
 	}
 
@@ -116,10 +186,10 @@ func resourceTagUpdate(ctx context.Context, d *schema.ResourceData, m interface{
 }
 
 func resourceTagDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	client := m.(*client.Client)
+	client := m.(*ClientConfig).Client
 
 	if err := client.Tag.Delete(d.Id()); err != nil {
-		return diag.FromErr(err)
+		return diagFromErr(err)
 	}
 
 	return nil
@@ -127,7 +197,10 @@ func resourceTagDelete(ctx context.Context, d *schema.ResourceData, m interface{
 
 func getTagCreateParams(d *schema.ResourceData) *entity.TagParams {
 	return &entity.TagParams{
-		Name: d.Get("name").(string),
+		Name:       d.Get("name").(string),
+		Definition: d.Get("definition").(string),
+		Comment:    d.Get("comment").(string),
+		KernelOpts: d.Get("kernel_opts").(string),
 	}
 }
 
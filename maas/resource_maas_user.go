@@ -3,6 +3,7 @@ package maas
 import (
 	"context"
 	"fmt"
+	"log"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -15,10 +16,12 @@ func resourceMaasUser() *schema.Resource {
 		Description:   "Provides a resource to manage MAAS users.",
 		CreateContext: resourceUserCreate,
 		ReadContext:   resourceUserRead,
+		UpdateContext: resourceUserUpdate,
 		DeleteContext: resourceUserDelete,
+		CustomizeDiff: resourceUserCustomizeDiff,
 		Importer: &schema.ResourceImporter{
 			StateContext: func(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
-				client := m.(*client.Client)
+				client := m.(*ClientConfig).Client
 				user, err := getUser(client, d.Id())
 				if err != nil {
 					return nil, err
@@ -28,6 +31,7 @@ func resourceMaasUser() *schema.Resource {
 					"name":     user.UserName,
 					"email":    user.Email,
 					"is_admin": user.IsSuperUser,
+					"is_local": user.IsLocal,
 				}
 				if err := setTerraformState(d, tfState); err != nil {
 					return nil, err
@@ -47,8 +51,7 @@ func resourceMaasUser() *schema.Resource {
 				Type:        schema.TypeString,
 				Required:    true,
 				Sensitive:   true,
-				ForceNew:    true,
-				Description: "The user password.",
+				Description: "The user password. MAAS can't change it for an existing user, so changing it replaces the user. MAAS never returns it, so after the user is imported, the configured password is only recorded in the state.",
 			},
 			"email": {
 				Type:             schema.TypeString,
@@ -62,44 +65,75 @@ func resourceMaasUser() *schema.Resource {
 				Optional:    true,
 				Default:     false,
 				ForceNew:    true,
-				Description: "Boolean value indicating if the user is a MAAS administrator. Defaults to `false`.",
+				Description: "Boolean value indicating if the user is a MAAS administrator. The MAAS API can't change it for an existing user, so changing it replaces the user. Defaults to `false`.",
+			},
+			"is_local": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Boolean value indicating if the user is managed by MAAS, as opposed to an external identity provider (e.g. Candid or RBAC).",
 			},
 		},
 	}
 }
 
 func resourceUserCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	client := m.(*client.Client)
+	client := m.(*ClientConfig).Client
 
 	user, err := client.Users.Create(getUserParams(d))
 	if err != nil {
-		return diag.FromErr(err)
+		return diagFromErr(err)
 	}
 	d.SetId(user.UserName)
 
-	return nil
+	return resourceUserRead(ctx, d, m)
 }
 
 func resourceUserRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	client := m.(*client.Client)
+	client := m.(*ClientConfig).Client
 
-	if _, err := client.User.Get(d.Id()); err != nil {
-		return diag.FromErr(err)
+	user, err := client.User.Get(d.Id())
+	if err != nil {
+		if isNotFoundError(err) {
+			log.Printf("[DEBUG] User (%s) was not found, removing it from state\n", d.Id())
+			d.SetId("")
+			return nil
+		}
+		return diagFromErr(err)
+	}
+	tfState := map[string]interface{}{
+		"name":     user.UserName,
+		"email":    user.Email,
+		"is_admin": user.IsSuperUser,
+		"is_local": user.IsLocal,
+	}
+	if err := setTerraformState(d, tfState); err != nil {
+		return diagFromErr(err)
 	}
 
 	return nil
 }
 
+func resourceUserUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	// The password is the only argument updated in place, and only after an
+	// import, when it's unknown. It's recorded in the state from the config.
+	return resourceUserRead(ctx, d, m)
+}
+
 func resourceUserDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	client := m.(*client.Client)
+	client := m.(*ClientConfig).Client
 
 	if err := client.User.Delete(d.Id()); err != nil {
-		return diag.FromErr(err)
+		return diagFromErr(err)
 	}
 
 	return nil
 }
 
+func resourceUserCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	oldPassword, _ := d.GetChange("password")
+	return forceNewOnWriteOnlyChange(d, "password", oldPassword.(string) == "")
+}
+
 func getUserParams(d *schema.ResourceData) *entity.UserParams {
 	return &entity.UserParams{
 		UserName:    d.Get("name").(string),
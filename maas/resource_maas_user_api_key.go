@@ -0,0 +1,118 @@
+package maas
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func resourceMaasUserAPIKey() *schema.Resource {
+	return &schema.Resource{
+		Description:   "Provides a resource to manage additional API keys of the MAAS user used by Terraform. The MAAS API can create API keys only for the authenticated user, so use a provider configured with the credentials of the user that needs the API key.",
+		CreateContext: resourceUserAPIKeyCreate,
+		ReadContext:   resourceUserAPIKeyRead,
+		UpdateContext: resourceUserAPIKeyUpdate,
+		DeleteContext: resourceUserAPIKeyDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				Description: "The name of the API key. This argument is computed if it's not set.",
+			},
+			"consumer_key": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Sensitive:   true,
+				Description: "The OAuth consumer key of the API key.",
+			},
+			"token_key": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The OAuth token key of the API key, which is also the resource ID. It isn't a secret, since the API key can't be used without the `token_secret`.",
+			},
+			"token_secret": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Sensitive:   true,
+				Description: "The OAuth token secret of the API key.",
+			},
+			"api_key": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Sensitive:   true,
+				Description: "The API key, in the `CONSUMER_KEY:TOKEN_KEY:TOKEN_SECRET` format used by the provider `api_key` argument and the MAAS CLI.",
+			},
+		},
+	}
+}
+
+func resourceUserAPIKeyCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	token, err := m.(*ClientConfig).Account.CreateAuthorisationToken(d.Get("name").(string))
+	if err != nil {
+		return diagFromErr(err)
+	}
+	d.SetId(token.TokenKey)
+
+	return resourceUserAPIKeyRead(ctx, d, m)
+}
+
+func resourceUserAPIKeyRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	token, err := findAuthorisationToken(m.(*ClientConfig), d.Id())
+	if err != nil {
+		return diagFromErr(err)
+	}
+	if token == nil {
+		log.Printf("[DEBUG] API key (%s) was not found, removing it from state\n", d.Id())
+		d.SetId("")
+		return nil
+	}
+	tfState := map[string]interface{}{
+		"name":         token.Name,
+		"consumer_key": token.ConsumerKey,
+		"token_key":    token.TokenKey,
+		"token_secret": token.TokenSecret,
+		"api_key":      fmt.Sprintf("%s:%s:%s", token.ConsumerKey, token.TokenKey, token.TokenSecret),
+	}
+	if err := setTerraformState(d, tfState); err != nil {
+		return diagFromErr(err)
+	}
+
+	return nil
+}
+
+func resourceUserAPIKeyUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	if err := m.(*ClientConfig).Account.UpdateTokenName(d.Id(), d.Get("name").(string)); err != nil {
+		return diagFromErr(err)
+	}
+
+	return resourceUserAPIKeyRead(ctx, d, m)
+}
+
+func resourceUserAPIKeyDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	if err := m.(*ClientConfig).Account.DeleteAuthorisationToken(d.Id()); err != nil {
+		return diagFromErr(err)
+	}
+
+	return nil
+}
+
+func findAuthorisationToken(clientConfig *ClientConfig, tokenKey string) (*AuthorisationToken, error) {
+	tokens, err := clientConfig.Account.ListAuthorisationTokens()
+	if err != nil {
+		return nil, err
+	}
+	for _, t := range tokens {
+		if t.TokenKey == tokenKey {
+			return &t, nil
+		}
+	}
+	return nil, nil
+}
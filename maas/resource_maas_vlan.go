@@ -3,9 +3,12 @@ package maas
 import (
 	"context"
 	"fmt"
+	"log"
 	"strings"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/maas/gomaasclient/client"
 	"github.com/maas/gomaasclient/entity"
@@ -22,9 +25,9 @@ func resourceMaasVlan() *schema.Resource {
 			StateContext: func(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
 				idParts := strings.Split(d.Id(), ":")
 				if len(idParts) != 2 || idParts[0] == "" || idParts[1] == "" {
-					return nil, fmt.Errorf("unexpected format of ID (%q), expected FABRIC:VLAN", d.Id())
+					return nil, fmt.Errorf("unexpected format of ID (%q), expected FABRIC:VLAN, where FABRIC is an ID or name, and VLAN is an ID, traffic segregation ID, or name", d.Id())
 				}
-				client := m.(*client.Client)
+				client := m.(*ClientConfig).Client
 				fabric, err := getFabric(client, idParts[0])
 				if err != nil {
 					return nil, err
@@ -68,7 +71,20 @@ func resourceMaasVlan() *schema.Resource {
 				Type:        schema.TypeBool,
 				Optional:    true,
 				Computed:    true,
-				Description: "Boolean value. Whether or not DHCP should be managed on the new VLAN. This argument is computed if it's not set.",
+				Description: "Boolean value. Whether or not DHCP should be managed on the new VLAN. When enabled, Terraform waits until the VLAN primary rack controller is connected and serving DHCP. This argument is computed if it's not set.",
+			},
+			"primary_rack": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				Description: "The identifier (system ID or hostname) of the rack controller serving DHCP on the VLAN. This argument is computed if it's not set.",
+			},
+			"secondary_rack": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				RequiredWith: []string{"primary_rack"},
+				Description:  "The identifier (system ID or hostname) of the rack controller serving DHCP on the VLAN when the primary one is down. If this is set, the `primary_rack` argument is required. This argument is computed if it's not set.",
 			},
 			"name": {
 				Type:        schema.TypeString,
@@ -83,19 +99,23 @@ func resourceMaasVlan() *schema.Resource {
 				Description: "The space of the new VLAN. Passing in an empty string (or the string `undefined`) will cause the VLAN to be placed in the `undefined` space. This argument is computed if it's not set.",
 			},
 		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(10 * time.Minute),
+			Update: schema.DefaultTimeout(10 * time.Minute),
+		},
 	}
 }
 
 func resourceVlanCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	client := m.(*client.Client)
+	client := m.(*ClientConfig).Client
 
 	fabric, err := getFabric(client, d.Get("fabric").(string))
 	if err != nil {
-		return diag.FromErr(err)
+		return diagFromErr(err)
 	}
 	vlan, err := client.VLANs.Create(fabric.ID, getVlanParams(d))
 	if err != nil {
-		return diag.FromErr(err)
+		return diagFromErr(err)
 	}
 	d.SetId(fmt.Sprintf("%v", vlan.ID))
 
@@ -103,60 +123,86 @@ func resourceVlanCreate(ctx context.Context, d *schema.ResourceData, m interface
 }
 
 func resourceVlanRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	client := m.(*client.Client)
+	client := m.(*ClientConfig).Client
 
 	fabric, err := getFabric(client, d.Get("fabric").(string))
 	if err != nil {
-		return diag.FromErr(err)
+		return diagFromErr(err)
 	}
 	vlan, err := getVlan(client, fabric.ID, d.Id())
 	if err != nil {
-		return diag.FromErr(err)
+		return diagFromErr(err)
+	}
+	primaryRack, err := getVlanRackTFState(m.(*ClientConfig), vlan.PrimaryRack, d.Get("primary_rack").(string))
+	if err != nil {
+		return diagFromErr(err)
+	}
+	secondaryRack, err := getVlanRackTFState(m.(*ClientConfig), vlan.SecondaryRack, d.Get("secondary_rack").(string))
+	if err != nil {
+		return diagFromErr(err)
 	}
 	tfState := map[string]interface{}{
-		"mtu":     vlan.MTU,
-		"dhcp_on": vlan.DHCPOn,
-		"name":    vlan.Name,
-		"space":   vlan.Space,
+		"mtu":            vlan.MTU,
+		"dhcp_on":        vlan.DHCPOn,
+		"primary_rack":   primaryRack,
+		"secondary_rack": secondaryRack,
+		"name":           vlan.Name,
+		"space":          vlan.Space,
 	}
 	if err := setTerraformState(d, tfState); err != nil {
-		return diag.FromErr(err)
+		return diagFromErr(err)
 	}
 
 	return nil
 }
 
 func resourceVlanUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	client := m.(*client.Client)
+	client := m.(*ClientConfig).Client
 
 	fabric, err := getFabric(client, d.Get("fabric").(string))
 	if err != nil {
-		return diag.FromErr(err)
+		return diagFromErr(err)
 	}
 	vlan, err := getVlan(client, fabric.ID, d.Id())
 	if err != nil {
-		return diag.FromErr(err)
+		return diagFromErr(err)
+	}
+	params := getVlanParams(d)
+	if params.PrimaryRack, err = getRackControllerSystemID(m.(*ClientConfig), d.Get("primary_rack").(string)); err != nil {
+		return diagFromErr(err)
 	}
-	if _, err := client.VLAN.Update(fabric.ID, vlan.VID, getVlanParams(d)); err != nil {
-		return diag.FromErr(err)
+	if params.SecondaryRack, err = getRackControllerSystemID(m.(*ClientConfig), d.Get("secondary_rack").(string)); err != nil {
+		return diagFromErr(err)
+	}
+	if _, err := client.VLAN.Update(fabric.ID, vlan.VID, params); err != nil {
+		return diagFromErr(err)
+	}
+	if d.Get("dhcp_on").(bool) {
+		timeout := d.Timeout(schema.TimeoutUpdate)
+		if d.IsNewResource() {
+			timeout = d.Timeout(schema.TimeoutCreate)
+		}
+		if err := waitForVlanDHCP(ctx, m.(*ClientConfig), fabric.ID, vlan.ID, timeout); err != nil {
+			return diagFromErr(err)
+		}
 	}
 
 	return resourceVlanRead(ctx, d, m)
 }
 
 func resourceVlanDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	client := m.(*client.Client)
+	client := m.(*ClientConfig).Client
 
 	fabric, err := getFabric(client, d.Get("fabric").(string))
 	if err != nil {
-		return diag.FromErr(err)
+		return diagFromErr(err)
 	}
 	vlan, err := getVlan(client, fabric.ID, d.Id())
 	if err != nil {
-		return diag.FromErr(err)
+		return diagFromErr(err)
 	}
 	if err := client.VLAN.Delete(fabric.ID, vlan.VID); err != nil {
-		return diag.FromErr(err)
+		return diagFromErr(err)
 	}
 
 	return nil
@@ -182,6 +228,12 @@ func findVlan(client *client.Client, fabricID int, identifier string) (*entity.V
 			return &v, nil
 		}
 	}
+	// The VLAN name is matched only if no numeric identifier matches
+	for _, v := range vlans {
+		if v.Name == identifier {
+			return &v, nil
+		}
+	}
 	return nil, nil
 }
 
@@ -195,3 +247,93 @@ func getVlan(client *client.Client, fabricID int, identifier string) (*entity.VL
 	}
 	return vlan, nil
 }
+
+// getRackControllerSystemID returns the system ID of the rack controller with
+// the given system ID or hostname.
+func getRackControllerSystemID(clientConfig *ClientConfig, identifier string) (string, error) {
+	if identifier == "" {
+		return "", nil
+	}
+	rackControllers, err := clientConfig.RackControllers.Get("")
+	if err != nil {
+		return "", err
+	}
+	for _, r := range rackControllers {
+		if r.SystemID == identifier || r.Hostname == identifier {
+			return r.SystemID, nil
+		}
+	}
+	return "", fmt.Errorf("rack controller (%s) was not found", identifier)
+}
+
+// getVlanRackTFState returns the system ID of the VLAN rack controller, or
+// the configured hostname if it refers to the same rack controller.
+func getVlanRackTFState(clientConfig *ClientConfig, systemID string, configured string) (string, error) {
+	if systemID == "" || configured == "" || configured == systemID {
+		return systemID, nil
+	}
+	rackController, err := clientConfig.RackController.Get(systemID)
+	if err != nil {
+		return "", err
+	}
+	if rackController.Hostname == configured {
+		return configured, nil
+	}
+	return systemID, nil
+}
+
+// waitForVlanDHCP polls the VLAN until its primary rack controller is
+// connected and the DHCP service is running on it. On timeout, the returned
+// error contains the last known state of the rack controller services.
+func waitForVlanDHCP(ctx context.Context, clientConfig *ClientConfig, fabricID int, vlanID int, timeout time.Duration) error {
+	log.Printf("[DEBUG] Waiting for DHCP to be active on VLAN (%d)\n", vlanID)
+	rackState := "no primary rack controller"
+	stateConf := &resource.StateChangeConf{
+		Pending: []string{"pending"},
+		Target:  []string{"active"},
+		Refresh: func() (interface{}, string, error) {
+			vlan, err := getVlan(clientConfig.Client, fabricID, fmt.Sprintf("%v", vlanID))
+			if err != nil {
+				return nil, "", err
+			}
+			if vlan.PrimaryRack == "" {
+				return vlan, "pending", nil
+			}
+			rackController, err := clientConfig.RackController.Get(vlan.PrimaryRack)
+			if err != nil {
+				return nil, "", err
+			}
+			services := map[string]string{}
+			for _, s := range rackController.ServiceSet {
+				services[s.Name] = s.Status
+			}
+			rackState = getRackControllerServicesState(rackController)
+			if !vlan.DHCPOn || services["rackd"] != "running" || services["dhcpd"] != "running" {
+				return vlan, "pending", nil
+			}
+			return vlan, "active", nil
+		},
+		Timeout:    timeout,
+		Delay:      5 * time.Second,
+		MinTimeout: 3 * time.Second,
+	}
+	if _, err := stateConf.WaitForStateContext(ctx); err != nil {
+		return fmt.Errorf("DHCP is not active on VLAN (%d), rack controller state: %s: %w", vlanID, rackState, err)
+	}
+	return nil
+}
+
+func getRackControllerServicesState(rackController *entity.RackController) string {
+	state := []string{}
+	for _, s := range rackController.ServiceSet {
+		if s.Name != "rackd" && s.Name != "dhcpd" {
+			continue
+		}
+		service := fmt.Sprintf("%s=%s", s.Name, s.Status)
+		if s.StatusInfo != "" {
+			service = fmt.Sprintf("%s (%s)", service, s.StatusInfo)
+		}
+		state = append(state, service)
+	}
+	return fmt.Sprintf("%s [%s]", rackController.Hostname, strings.Join(state, ", "))
+}
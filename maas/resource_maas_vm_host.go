@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -27,9 +28,10 @@ func resourceMaasVMHost() *schema.Resource {
 		ReadContext:   resourceVMHostRead,
 		UpdateContext: resourceVMHostUpdate,
 		DeleteContext: resourceVMHostDelete,
+		CustomizeDiff: resourceVMHostCustomizeDiff,
 		Importer: &schema.ResourceImporter{
 			StateContext: func(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
-				client := m.(*client.Client)
+				client := m.(*ClientConfig).Client
 				vmHost, err := getVMHost(client, d.Id())
 				if err != nil {
 					return nil, err
@@ -94,6 +96,37 @@ func resourceMaasVMHost() *schema.Resource {
 				ConflictsWith: []string{"machine"},
 				Description:   "User password to use for power control of the VM host. Cannot be set if `machine` parameter is used.",
 			},
+			"project": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				Computed:      true,
+				ForceNew:      true,
+				ConflictsWith: []string{"machine"},
+				Description:   "The LXD project used by MAAS for the VMs of the `lxd` VM host. It's created if it doesn't exist. If it's not set, the MAAS default project is used. This is computed if it's not set. It can't be set if `machine` argument is used.",
+			},
+			"certificate": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				Sensitive:     true,
+				ConflictsWith: []string{"machine"},
+				RequiredWith:  []string{"key"},
+				Description:   "The PEM certificate used by MAAS to authenticate to the `lxd` VM host. If it's not set, MAAS generates one. Changing it rotates the certificate without recreating the VM host. It can't be set if `machine` argument is used.",
+			},
+			"key": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				Sensitive:     true,
+				ConflictsWith: []string{"machine"},
+				RequiredWith:  []string{"certificate"},
+				Description:   "The PEM private key of the `certificate`. It can't be set if `machine` argument is used.",
+			},
+			"password": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				Sensitive:     true,
+				ConflictsWith: []string{"machine"},
+				Description:   "The LXD trust password, used to add the MAAS certificate to the trusted certificates of the `lxd` VM host when it's created, and when the `certificate` is rotated. It isn't needed if the certificate is already trusted. It can't be set if `machine` argument is used.",
+			},
 			"name": {
 				Type:        schema.TypeString,
 				Optional:    true,
@@ -104,13 +137,13 @@ func resourceMaasVMHost() *schema.Resource {
 				Type:        schema.TypeString,
 				Optional:    true,
 				Computed:    true,
-				Description: "The new VM host zone name. This is computed if it's not set.",
+				Description: "The new VM host zone name. If it's not set, the provider `default_zone` is used. This is computed if it's not set.",
 			},
 			"pool": {
 				Type:        schema.TypeString,
 				Optional:    true,
 				Computed:    true,
-				Description: "The new VM host pool name. This is computed if it's not set.",
+				Description: "The new VM host pool name. If it's not set, the provider `default_pool` is used. This is computed if it's not set.",
 			},
 			"tags": {
 				Type:        schema.TypeSet,
@@ -139,6 +172,12 @@ func resourceMaasVMHost() *schema.Resource {
 				Computed:    true,
 				Description: "The new VM host default macvlan mode. Supported values are: `bridge`, `passthru`, `private`, `vepa`. This is computed if it's not set.",
 			},
+			"default_storage_pool": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				Description: "The name or ID of the VM host default storage pool, used for the VMs storage when no pool is given. This is computed if it's not set.",
+			},
 			"resources_cores_total": {
 				Type:        schema.TypeInt,
 				Computed:    true,
@@ -154,26 +193,82 @@ func resourceMaasVMHost() *schema.Resource {
 				Computed:    true,
 				Description: "The VM host total local storage (in bytes).",
 			},
+			"storage_pools": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The VM host storage pools. Parameters defined below.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The storage pool name.",
+						},
+						"type": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The storage pool type (e.g. `dir`, `lvm`, `zfs`).",
+						},
+						"path": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The storage pool path on the VM host.",
+						},
+						"total": {
+							Type:        schema.TypeInt,
+							Computed:    true,
+							Description: "The storage pool total size (in bytes).",
+						},
+						"used": {
+							Type:        schema.TypeInt,
+							Computed:    true,
+							Description: "The storage pool used size (in bytes).",
+						},
+						"available": {
+							Type:        schema.TypeInt,
+							Computed:    true,
+							Description: "The storage pool available size (in bytes).",
+						},
+						"default": {
+							Type:        schema.TypeBool,
+							Computed:    true,
+							Description: "Boolean value indicating if this is the default storage pool of the VM host.",
+						},
+					},
+				},
+			},
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(20 * time.Minute),
+			Delete: schema.DefaultTimeout(20 * time.Minute),
 		},
 	}
 }
 
 func resourceVMHostCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	client := m.(*client.Client)
+	client := m.(*ClientConfig).Client
 
 	// Create VM host
 	var vmHost *entity.VMHost
 	var err error
 	if p, ok := d.GetOk("machine"); ok {
 		// Deploy machine, and register it as VM host
-		vmHost, err = deployMachineAsVMHost(ctx, client, p.(string), d.Get("type").(string))
+		vmHost, err = deployMachineAsVMHost(ctx, client, p.(string), d.Get("type").(string), d.Timeout(schema.TimeoutCreate))
 		if err != nil {
-			return diag.FromErr(err)
+			return diagFromErr(err)
 		}
 	} else {
-		vmHost, err = client.VMHosts.Create(getVMHostParams(d))
+		// The storage pools are known only after the VM host is created, so
+		// the default storage pool is set by the update
+		params := getVMHostParams(d, m.(*ClientConfig))
+		params.DefaultStoragePool = ""
+		params.Project = d.Get("project").(string)
+		params.Certificate = d.Get("certificate").(string)
+		params.Key = d.Get("key").(string)
+		params.Password = d.Get("password").(string)
+		vmHost, err = m.(*ClientConfig).VMHosts.Create(params)
 		if err != nil {
-			return diag.FromErr(err)
+			return diagFromErr(err)
 		}
 	}
 
@@ -185,20 +280,21 @@ func resourceVMHostCreate(ctx context.Context, d *schema.ResourceData, m interfa
 }
 
 func resourceVMHostRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	client := m.(*client.Client)
+	client := m.(*ClientConfig).Client
 
 	// Get VM host details
 	id, err := strconv.Atoi(d.Id())
 	if err != nil {
-		return diag.FromErr(err)
+		return diagFromErr(err)
 	}
 	vmHost, err := client.VMHost.Get(id)
 	if err != nil {
-		return diag.FromErr(err)
+		return diagFromErr(err)
 	}
 
 	// Set Terraform state
 	tfState := map[string]interface{}{
+		"project":                       "",
 		"name":                          vmHost.Name,
 		"zone":                          vmHost.Zone.Name,
 		"pool":                          vmHost.Pool.Name,
@@ -209,51 +305,68 @@ func resourceVMHostRead(ctx context.Context, d *schema.ResourceData, m interface
 		"resources_cores_total":         vmHost.Total.Cores,
 		"resources_memory_total":        vmHost.Total.Memory,
 		"resources_local_storage_total": vmHost.Total.LocalStorage,
+		"storage_pools":                 getVMHostStoragePoolsTFState(vmHost),
+		"default_storage_pool":          getVMHostDefaultStoragePool(vmHost, d.Get("default_storage_pool").(string)),
+	}
+	if vmHost.Type == "lxd" {
+		vmHostParams, err := client.VMHost.GetParameters(vmHost.ID)
+		if err != nil {
+			return diagFromErr(err)
+		}
+		tfState["project"] = vmHostParams["project"]
 	}
 	if err := setTerraformState(d, tfState); err != nil {
-		return diag.FromErr(err)
+		return diagFromErr(err)
 	}
 
 	return nil
 }
 
 func resourceVMHostUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	client := m.(*client.Client)
+	client := m.(*ClientConfig).Client
 
 	// Get the VM host
 	id, err := strconv.Atoi(d.Id())
 	if err != nil {
-		return diag.FromErr(err)
+		return diagFromErr(err)
 	}
 	vmHost, err := client.VMHost.Get(id)
 	if err != nil {
-		return diag.FromErr(err)
+		return diagFromErr(err)
 	}
 
-	// Update VM host options
-	_, err = client.VMHost.Update(vmHost.ID, getVMHostParams(d))
+	// Update VM host options, and rotate the certificate if it was changed.
+	// The trust password is sent along, so the new certificate doesn't have
+	// to be already trusted by the VM host.
+	params := getVMHostParams(d, m.(*ClientConfig))
+	if !d.IsNewResource() && d.HasChanges("certificate", "key") {
+		params.Certificate = d.Get("certificate").(string)
+		params.Key = d.Get("key").(string)
+		params.Password = d.Get("password").(string)
+	}
+	_, err = m.(*ClientConfig).VMHosts.Update(vmHost.ID, params)
 	if err != nil {
-		return diag.FromErr(err)
+		return diagFromErr(err)
 	}
 
 	return resourceVMHostRead(ctx, d, m)
 }
 
 func resourceVMHostDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	client := m.(*client.Client)
+	client := m.(*ClientConfig).Client
 
 	// Delete VM host
 	id, err := strconv.Atoi(d.Id())
 	if err != nil {
-		return diag.FromErr(err)
+		return diagFromErr(err)
 	}
 	vmHost, err := client.VMHost.Get(id)
 	if err != nil {
-		return diag.FromErr(err)
+		return diagFromErr(err)
 	}
 	err = client.VMHost.Delete(vmHost.ID)
 	if err != nil {
-		return diag.FromErr(err)
+		return diagFromErr(err)
 	}
 
 	// If the VM host was deployed from a machine, release the machine.
@@ -261,20 +374,36 @@ func resourceVMHostDelete(ctx context.Context, d *schema.ResourceData, m interfa
 		// Release machine
 		err = client.Machines.Release([]string{vmHost.Host.SystemID}, "Released by Terraform")
 		if err != nil {
-			return diag.FromErr(err)
+			return diagFromErr(err)
 		}
 		// Wait machine to be released
-		_, err = waitForMachineStatus(ctx, client, vmHost.Host.SystemID, []string{"Releasing"}, []string{"Ready"})
+		_, err = waitForMachineStatus(ctx, client, vmHost.Host.SystemID, []string{"Releasing"}, []string{"Ready"}, d.Timeout(schema.TimeoutDelete))
 		if err != nil {
-			return diag.FromErr(err)
+			return diagFromErr(err)
 		}
 	}
 
 	return nil
 }
 
-func getVMHostParams(d *schema.ResourceData) *entity.VMHostParams {
-	return &entity.VMHostParams{
+func resourceVMHostCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	rawConfig := d.GetRawConfig()
+	if d.Get("type").(string) == "lxd" || rawConfig.IsNull() {
+		return nil
+	}
+	for _, k := range []string{"project", "certificate", "key", "password"} {
+		if rawConfig.GetAttr(k).IsNull() {
+			continue
+		}
+		return fmt.Errorf("%s can be set only for the lxd VM hosts", k)
+	}
+	return nil
+}
+
+// getVMHostParams returns the VM host options. The LXD project, certificate
+// and password are set only when they're needed.
+func getVMHostParams(d *schema.ResourceData, clientConfig *ClientConfig) *VMHostParams {
+	return &VMHostParams{VMHostParams: entity.VMHostParams{
 		Name:                  d.Get("name").(string),
 		Type:                  d.Get("type").(string),
 		PowerAddress:          d.Get("power_address").(string),
@@ -283,13 +412,14 @@ func getVMHostParams(d *schema.ResourceData) *entity.VMHostParams {
 		CPUOverCommitRatio:    d.Get("cpu_over_commit_ratio").(float64),
 		MemoryOverCommitRatio: d.Get("memory_over_commit_ratio").(float64),
 		DefaultMacvlanMode:    d.Get("default_macvlan_mode").(string),
-		Zone:                  d.Get("zone").(string),
-		Pool:                  d.Get("pool").(string),
+		DefaultStoragePool:    d.Get("default_storage_pool").(string),
+		Zone:                  getStringOrDefault(d, "zone", clientConfig.DefaultZone),
+		Pool:                  getStringOrDefault(d, "pool", clientConfig.DefaultPool),
 		Tags:                  strings.Join(convertToStringSlice(d.Get("tags").(*schema.Set).List()), ","),
-	}
+	}}
 }
 
-func deployMachineAsVMHost(ctx context.Context, client *client.Client, machineIdentifier string, vmHostType string) (*entity.VMHost, error) {
+func deployMachineAsVMHost(ctx context.Context, client *client.Client, machineIdentifier string, vmHostType string, timeout time.Duration) (*entity.VMHost, error) {
 	// Find machine
 	machine, err := getMachine(client, machineIdentifier)
 	if err != nil {
@@ -309,13 +439,16 @@ func deployMachineAsVMHost(ctx context.Context, client *client.Client, machineId
 		InstallKVM:     (vmHostType == "virsh"),
 		RegisterVMHost: (vmHostType == "lxd"),
 	}
-	machine, err = client.Machine.Deploy(machine.SystemID, &deployParams)
+	err = retryMachineConflict(ctx, client, machine.SystemID, isMachineStatus("Deploying", "Deployed"), func() error {
+		_, err := client.Machine.Deploy(machine.SystemID, &deployParams)
+		return err
+	})
 	if err != nil {
 		return nil, err
 	}
 
 	// Wait for MAAS machine to be deployed
-	machine, err = waitForMachineStatus(ctx, client, machine.SystemID, []string{"Deploying"}, []string{"Deployed"})
+	machine, err = waitForMachineStatus(ctx, client, machine.SystemID, []string{"Deploying"}, []string{"Deployed"}, timeout)
 	if err != nil {
 		return nil, err
 	}
@@ -334,6 +467,37 @@ func deployMachineAsVMHost(ctx context.Context, client *client.Client, machineId
 	return nil, fmt.Errorf("cannot find registered VM host on machine '%s'", machineIdentifier)
 }
 
+func getVMHostStoragePoolsTFState(vmHost *entity.VMHost) []map[string]interface{} {
+	storagePools := make([]map[string]interface{}, len(vmHost.StoragePools))
+	for i, p := range vmHost.StoragePools {
+		storagePools[i] = map[string]interface{}{
+			"name":      p.Name,
+			"type":      p.Type,
+			"path":      p.Path,
+			"total":     p.Total,
+			"used":      p.Used,
+			"available": p.Available,
+			"default":   p.Default,
+		}
+	}
+	return storagePools
+}
+
+// getVMHostDefaultStoragePool returns the default storage pool of the VM
+// host, keeping the configured identifier if it refers to the same pool.
+func getVMHostDefaultStoragePool(vmHost *entity.VMHost, configured string) string {
+	for _, p := range vmHost.StoragePools {
+		if !p.Default {
+			continue
+		}
+		if configured == p.ID {
+			return p.ID
+		}
+		return p.Name
+	}
+	return ""
+}
+
 func getVMHost(client *client.Client, identifier string) (*entity.VMHost, error) {
 	vmHosts, err := client.VMHosts.Get()
 	if err != nil {
@@ -4,10 +4,12 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"time"
 
+	"github.com/hashicorp/go-cty/cty"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
-	"github.com/maas/gomaasclient/client"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	"github.com/maas/gomaasclient/entity"
 )
 
@@ -18,9 +20,10 @@ func resourceMaasVMHostMachine() *schema.Resource {
 		ReadContext:   resourceVMHostMachineRead,
 		UpdateContext: resourceVMHostMachineUpdate,
 		DeleteContext: resourceVMHostMachineDelete,
+		CustomizeDiff: resourceVMHostMachineCustomizeDiff,
 		Importer: &schema.ResourceImporter{
 			StateContext: func(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
-				client := m.(*client.Client)
+				client := m.(*ClientConfig).Client
 				machine, err := getMachine(client, d.Id())
 				if err != nil {
 					return nil, err
@@ -49,16 +52,24 @@ func resourceMaasVMHostMachine() *schema.Resource {
 				Description: "ID or name of the VM host used to compose the new machine.",
 			},
 			"cores": {
-				Type:        schema.TypeInt,
-				Optional:    true,
-				ForceNew:    true,
-				Description: "The number of CPU cores (defaults to 1).",
+				Type:          schema.TypeInt,
+				Optional:      true,
+				ForceNew:      true,
+				ConflictsWith: []string{"pinned_cores"},
+				Description:   "The number of CPU cores (defaults to 1). Conflicts with `pinned_cores`.",
 			},
 			"pinned_cores": {
-				Type:        schema.TypeInt,
+				Type:          schema.TypeInt,
+				Optional:      true,
+				ForceNew:      true,
+				ConflictsWith: []string{"cores"},
+				Description:   "List of host CPU cores to pin the VM host machine to. Conflicts with `cores`.",
+			},
+			"hugepages_backed": {
+				Type:        schema.TypeBool,
 				Optional:    true,
 				ForceNew:    true,
-				Description: "List of host CPU cores to pin the VM host machine to. If this is passed, the `cores` parameter is ignored.",
+				Description: "Boolean value indicating if the VM host machine memory is backed by the VM host hugepages.",
 			},
 			"memory": {
 				Type:        schema.TypeInt,
@@ -88,6 +99,11 @@ func resourceMaasVMHostMachine() *schema.Resource {
 							Optional:    true,
 							Description: "The VLAN for the network interface.",
 						},
+						"space": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "The space for the network interface.",
+						},
 						"subnet_cidr": {
 							Type:        schema.TypeString,
 							Optional:    true,
@@ -98,6 +114,12 @@ func resourceMaasVMHostMachine() *schema.Resource {
 							Optional:    true,
 							Description: "Static IP configured on the new network interface.",
 						},
+						"numa_node": {
+							Type:             schema.TypeInt,
+							Optional:         true,
+							ValidateDiagFunc: validation.ToDiagFunc(validation.IntAtLeast(0)),
+							Description:      "The VM host NUMA node the network interface is attached to. If this is not set, the network interface has no NUMA affinity.",
+						},
 					},
 				},
 			},
@@ -116,7 +138,41 @@ func resourceMaasVMHostMachine() *schema.Resource {
 						"pool": {
 							Type:        schema.TypeString,
 							Optional:    true,
-							Description: "The VM host storage pool name.",
+							Description: "The VM host storage pool name. It must be one of the VM host `storage_pools`. If it's not set, the VM host default storage pool is used.",
+						},
+						"tags": {
+							Type:        schema.TypeSet,
+							Optional:    true,
+							Description: "A set of tags used as constraints of the storage disk.",
+							Elem: &schema.Schema{
+								Type: schema.TypeString,
+							},
+						},
+					},
+				},
+			},
+			"deploy_params": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				ForceNew:    true,
+				MaxItems:    1,
+				Description: "Nested argument with the config used to deploy the composed machine. If this is set, the machine is allocated and deployed once it's ready. Defined below.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"distro_series": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "The distro series used to deploy the machine. If it's not given, the MAAS server default value is used.",
+						},
+						"hwe_kernel": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "Hardware enablement kernel to use with the image (e.g. `hwe-22.04`). Only used when deploying Ubuntu.",
+						},
+						"user_data": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "Cloud-init user data script that gets run on the machine once it has deployed.",
 						},
 					},
 				},
@@ -131,63 +187,101 @@ func resourceMaasVMHostMachine() *schema.Resource {
 				Type:        schema.TypeString,
 				Optional:    true,
 				Computed:    true,
-				Description: "The VM host machine domain. This is computed if it's not set.",
+				Description: "The VM host machine domain. If it's not set, the provider `default_domain` is used. This is computed if it's not set.",
 			},
 			"zone": {
 				Type:        schema.TypeString,
 				Optional:    true,
 				Computed:    true,
-				Description: "The VM host machine zone. This is computed if it's not set.",
+				Description: "The VM host machine zone. If it's not set, the provider `default_zone` is used. This is computed if it's not set.",
 			},
 			"pool": {
 				Type:        schema.TypeString,
 				Optional:    true,
 				Computed:    true,
-				Description: "The VM host machine pool. This is computed if it's not set.",
+				Description: "The VM host machine pool. If it's not set, the provider `default_pool` is used. This is computed if it's not set.",
 			},
 		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(30 * time.Minute),
+		},
 	}
 }
 
 func resourceVMHostMachineCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	client := m.(*client.Client)
+	client := m.(*ClientConfig).Client
 
 	// Find VM host
 	vmHost, err := getVMHost(client, d.Get("vm_host").(string))
 	if err != nil {
-		return diag.FromErr(err)
+		return diagFromErr(err)
 	}
 
 	// Create VM host machine
 	params, err := getVMHostMachineParams(d)
 	if err != nil {
-		return diag.FromErr(err)
+		return diagFromErr(err)
 	}
 	machine, err := client.VMHost.Compose(vmHost.ID, params)
 	if err != nil {
-		return diag.FromErr(err)
+		// MAAS doesn't tell which storage pool is out of space, so the
+		// available space of the VM host storage pools is added to the error
+		if len(d.Get("storage_disks").([]interface{})) > 0 {
+			return diagFromErr(fmt.Errorf("failed to compose machine on VM host (%s), storage pools available space (%s): %w", vmHost.Name, getVMHostStoragePoolsAvailable(vmHost), err))
+		}
+		return diagFromErr(fmt.Errorf("failed to compose machine on VM host (%s): %w", vmHost.Name, err))
 	}
 
 	// Save system id
 	d.SetId(machine.SystemID)
 
 	// Wait for VM host machine to be ready
-	_, err = waitForMachineStatus(ctx, client, machine.SystemID, []string{"Commissioning", "Testing"}, []string{"Ready"})
+	_, err = waitForMachineStatus(ctx, client, machine.SystemID, []string{"Commissioning", "Testing"}, []string{"Ready"}, d.Timeout(schema.TimeoutCreate))
 	if err != nil {
-		return diag.FromErr(err)
+		return diagFromErr(err)
 	}
 
-	// Return updated VM host machine
-	return resourceVMHostMachineUpdate(ctx, d, m)
+	// Set the hostname, domain, zone and pool before the machine is deployed
+	if diags := resourceVMHostMachineUpdate(ctx, d, m); diags.HasError() {
+		return diags
+	}
+
+	// Deploy VM host machine
+	if p, ok := d.GetOk("deploy_params"); ok {
+		deployParams := p.([]interface{})[0].(map[string]interface{})
+		if _, err := client.Machines.Allocate(&entity.MachineAllocateParams{SystemID: machine.SystemID}); err != nil {
+			return diagFromErr(err)
+		}
+		params := &MachineDeployParams{
+			MachineDeployParams: entity.MachineDeployParams{
+				DistroSeries: deployParams["distro_series"].(string),
+				HWEKernel:    deployParams["hwe_kernel"].(string),
+				UserData:     base64Encode([]byte(deployParams["user_data"].(string))),
+			},
+		}
+		err = retryMachineConflict(ctx, client, machine.SystemID, isMachineStatus("Deploying", "Deployed"), func() error {
+			_, err := m.(*ClientConfig).Machine.Deploy(machine.SystemID, params)
+			return err
+		})
+		if err != nil {
+			return diagFromErr(err)
+		}
+		_, err = waitForMachineStatus(ctx, client, machine.SystemID, []string{"Deploying"}, []string{"Deployed"}, d.Timeout(schema.TimeoutCreate))
+		if err != nil {
+			return diagFromErr(err)
+		}
+	}
+
+	return resourceVMHostMachineRead(ctx, d, m)
 }
 
 func resourceVMHostMachineRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	client := m.(*client.Client)
+	client := m.(*ClientConfig).Client
 
 	// Get VM host machine
 	machine, err := client.Machine.Get(d.Id())
 	if err != nil {
-		return diag.FromErr(err)
+		return diagFromErr(err)
 	}
 
 	// Set Terraform state
@@ -198,69 +292,106 @@ func resourceVMHostMachineRead(ctx context.Context, d *schema.ResourceData, m in
 		"pool":     machine.Pool.Name,
 	}
 	if err := setTerraformState(d, tfState); err != nil {
-		return diag.FromErr(err)
+		return diagFromErr(err)
 	}
 
 	return nil
 }
 
 func resourceVMHostMachineUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	client := m.(*client.Client)
+	client := m.(*ClientConfig).Client
 
 	// Update VM host machine
-	if _, err := client.Machine.Update(d.Id(), getVMHostMachineUpdateParams(d), map[string]string{}); err != nil {
-		return diag.FromErr(err)
+	if _, err := client.Machine.Update(d.Id(), getVMHostMachineUpdateParams(d, m.(*ClientConfig)), map[string]string{}); err != nil {
+		return diagFromErr(err)
 	}
 
 	return resourceVMHostMachineRead(ctx, d, m)
 }
 
+func resourceVMHostMachineCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	// Check that the storage disks pools exist on the VM host
+	if !d.HasChange("storage_disks") || !d.NewValueKnown("storage_disks") || !d.NewValueKnown("vm_host") {
+		return nil
+	}
+	pools := []string{}
+	for _, storageDisk := range d.Get("storage_disks").([]interface{}) {
+		if storageDisk == nil {
+			continue
+		}
+		if pool := storageDisk.(map[string]interface{})["pool"].(string); pool != "" {
+			pools = append(pools, pool)
+		}
+	}
+	if len(pools) == 0 {
+		return nil
+	}
+	vmHost, err := getVMHost(m.(*ClientConfig).Client, d.Get("vm_host").(string))
+	if err != nil {
+		return err
+	}
+	vmHostPools := make(map[string]bool, len(vmHost.StoragePools))
+	names := make([]string, len(vmHost.StoragePools))
+	for i, p := range vmHost.StoragePools {
+		vmHostPools[p.Name] = true
+		names[i] = p.Name
+	}
+	for _, pool := range pools {
+		if !vmHostPools[pool] {
+			return fmt.Errorf("storage pool (%s) was not found on VM host (%s), valid options are: %s", pool, vmHost.Name, strings.Join(names, ", "))
+		}
+	}
+	return nil
+}
+
 func resourceVMHostMachineDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	client := m.(*client.Client)
+	client := m.(*ClientConfig).Client
 
 	// Delete VM host machine
 	err := client.Machine.Delete(d.Id())
 	if err != nil {
-		return diag.FromErr(err)
+		return diagFromErr(err)
 	}
 
 	return nil
 }
 
 func getVMHostMachineParams(d *schema.ResourceData) (*entity.VMHostMachineParams, error) {
-	networkInterfaces, err := getVMHostMachineNetworkInterfaces(d.Get("network_interfaces").([]interface{}))
+	networkInterfaces, err := getVMHostMachineNetworkInterfaces(d.Get("network_interfaces").([]interface{}), d.GetRawConfig().GetAttr("network_interfaces"))
 	if err != nil {
 		return nil, err
 	}
 	params := entity.VMHostMachineParams{
-		Hostname:    d.Get("hostname").(string),
-		Cores:       d.Get("cores").(int),
-		PinnedCores: d.Get("pinned_cores").(int),
-		Memory:      d.Get("memory").(int),
-		Interfaces:  networkInterfaces,
-		Storage:     getVMHostMachineStorageDisks(d.Get("storage_disks").([]interface{})),
+		Hostname:        d.Get("hostname").(string),
+		Cores:           d.Get("cores").(int),
+		PinnedCores:     d.Get("pinned_cores").(int),
+		Memory:          d.Get("memory").(int),
+		HugepagesBacked: d.Get("hugepages_backed").(bool),
+		Interfaces:      networkInterfaces,
+		Storage:         getVMHostMachineStorageDisks(d.Get("storage_disks").([]interface{})),
 	}
 	return &params, nil
 }
 
-func getVMHostMachineUpdateParams(d *schema.ResourceData) *entity.MachineParams {
+func getVMHostMachineUpdateParams(d *schema.ResourceData, clientConfig *ClientConfig) *entity.MachineParams {
 	return &entity.MachineParams{
 		Hostname: d.Get("hostname").(string),
-		Domain:   d.Get("domain").(string),
-		Zone:     d.Get("zone").(string),
-		Pool:     d.Get("pool").(string),
+		Domain:   getStringOrDefault(d, "domain", clientConfig.DefaultDomain),
+		Zone:     getStringOrDefault(d, "zone", clientConfig.DefaultZone),
+		Pool:     getStringOrDefault(d, "pool", clientConfig.DefaultPool),
 	}
 }
 
-func getVMHostMachineNetworkInterfaces(networkInterfaces []interface{}) (string, error) {
+func getVMHostMachineNetworkInterfaces(networkInterfaces []interface{}, rawNetworkInterfaces cty.Value) (string, error) {
 	vmHostNetworkInterfaces := []string{}
-	for _, networkInterface := range networkInterfaces {
+	for i, networkInterface := range networkInterfaces {
 		n := networkInterface.(map[string]interface{})
 		vlan := n["vlan"].(string)
+		space := n["space"].(string)
 		subnet := n["subnet_cidr"].(string)
 		ip := n["ip_address"].(string)
-		if vlan == "" && subnet == "" && ip == "" {
-			return "", fmt.Errorf("at least one of the network interface properties (vlan, subnet_cidr, ip_address) is required")
+		if vlan == "" && space == "" && subnet == "" && ip == "" {
+			return "", fmt.Errorf("at least one of the network interface properties (vlan, space, subnet_cidr, ip_address) is required")
 		}
 		properties := []string{}
 		if fabric := n["fabric"].(string); fabric != "" {
@@ -269,24 +400,47 @@ func getVMHostMachineNetworkInterfaces(networkInterfaces []interface{}) (string,
 		if vlan != "" {
 			properties = append(properties, fmt.Sprintf("vlan=%s", vlan))
 		}
+		if space != "" {
+			properties = append(properties, fmt.Sprintf("space=%s", space))
+		}
 		if subnet != "" {
 			properties = append(properties, fmt.Sprintf("subnet_cidr=%s", subnet))
 		}
 		if ip != "" {
 			properties = append(properties, fmt.Sprintf("ip=%s", ip))
 		}
+		// The NUMA node 0 is a valid value, so the raw config tells if it's set
+		if !rawNetworkInterfaces.IsNull() && !rawNetworkInterfaces.Index(cty.NumberIntVal(int64(i))).GetAttr("numa_node").IsNull() {
+			properties = append(properties, fmt.Sprintf("numa_node=%d", n["numa_node"].(int)))
+		}
 		vmHostNetworkInterfaces = append(vmHostNetworkInterfaces, fmt.Sprintf("%s:%s", n["name"].(string), strings.Join(properties, ",")))
 	}
 	return strings.Join(vmHostNetworkInterfaces, ";"), nil
 }
 
+func getVMHostStoragePoolsAvailable(vmHost *entity.VMHost) string {
+	available := make([]string, len(vmHost.StoragePools))
+	for i, p := range vmHost.StoragePools {
+		available[i] = fmt.Sprintf("%s: %.1fG", p.Name, float64(p.Available)/(1024*1024*1024))
+	}
+	return strings.Join(available, ", ")
+}
+
 func getVMHostMachineStorageDisks(storageDisks []interface{}) string {
 	vmHostStorageDisks := []string{}
 	for i, storageDisk := range storageDisks {
 		d := storageDisk.(map[string]interface{})
 		disk := fmt.Sprintf("disk%d:%d", i, d["size_gigabytes"].(int))
+		// The storage pool is given as the first storage disk tag
+		tags := []string{}
 		if pool := d["pool"].(string); pool != "" {
-			disk = fmt.Sprintf("%s(%s)", disk, pool)
+			tags = append(tags, pool)
+		}
+		if t, ok := d["tags"]; ok {
+			tags = append(tags, convertToStringSlice(t.(*schema.Set).List())...)
+		}
+		if len(tags) > 0 {
+			disk = fmt.Sprintf("%s(%s)", disk, strings.Join(tags, ","))
 		}
 		vmHostStorageDisks = append(vmHostStorageDisks, disk)
 	}
@@ -0,0 +1,316 @@
+package maas
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/maas/gomaasclient/client"
+	"github.com/maas/gomaasclient/entity"
+)
+
+func resourceMaasVolumeGroup() *schema.Resource {
+	return &schema.Resource{
+		Description:   "Provides a resource to manage MAAS machines' LVM volume groups.",
+		CreateContext: resourceVolumeGroupCreate,
+		ReadContext:   resourceVolumeGroupRead,
+		UpdateContext: resourceVolumeGroupUpdate,
+		DeleteContext: resourceVolumeGroupDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: func(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+				idParts := strings.Split(d.Id(), ":")
+				if len(idParts) != 2 || idParts[0] == "" || idParts[1] == "" {
+					return nil, fmt.Errorf("unexpected format of ID (%q), expected MACHINE:VOLUME_GROUP, where MACHINE is a system ID, hostname, FQDN, or MAC address, and VOLUME_GROUP is an ID or name", d.Id())
+				}
+				clientConfig := m.(*ClientConfig)
+				machine, err := getMachine(clientConfig.Client, idParts[0])
+				if err != nil {
+					return nil, err
+				}
+				volumeGroup, err := getVolumeGroup(clientConfig, machine.SystemID, idParts[1])
+				if err != nil {
+					return nil, err
+				}
+				tfState := map[string]interface{}{
+					"id":      fmt.Sprintf("%v", volumeGroup.ID),
+					"machine": machine.SystemID,
+				}
+				if err := setTerraformState(d, tfState); err != nil {
+					return nil, err
+				}
+				return []*schema.ResourceData{d}, nil
+			},
+		},
+
+		Schema: map[string]*schema.Schema{
+			"machine": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The machine identifier (system ID, hostname, FQDN, or MAC address) that owns the volume group.",
+			},
+			"name": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The volume group name.",
+			},
+			"uuid": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				Description: "The volume group UUID. This argument is computed if it's not given.",
+			},
+			"block_devices": {
+				Type:         schema.TypeSet,
+				Optional:     true,
+				Computed:     true,
+				AtLeastOneOf: []string{"block_devices", "partitions"},
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+				Description: "A set of block device identifiers (ID, name, ID path, or path) used as the volume group physical volumes. At least one of `block_devices` or `partitions` must be set.",
+			},
+			"partitions": {
+				Type:         schema.TypeSet,
+				Optional:     true,
+				Computed:     true,
+				AtLeastOneOf: []string{"block_devices", "partitions"},
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+				Description: "A set of partition identifiers (ID or path) used as the volume group physical volumes. At least one of `block_devices` or `partitions` must be set.",
+			},
+			"size_gigabytes": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "The volume group size (given in GB).",
+			},
+			"used_size_gigabytes": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "The size (given in GB) used by the logical volumes of the volume group.",
+			},
+			"available_size_gigabytes": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "The size (given in GB) still available for new logical volumes.",
+			},
+		},
+	}
+}
+
+func resourceVolumeGroupCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	clientConfig := m.(*ClientConfig)
+
+	machine, err := getMachine(clientConfig.Client, d.Get("machine").(string))
+	if err != nil {
+		return diagFromErr(err)
+	}
+	blockDeviceIDs, partitionIDs, err := getVolumeGroupMemberIDs(clientConfig.Client, d, machine.SystemID)
+	if err != nil {
+		return diagFromErr(err)
+	}
+	params := &VolumeGroupParams{
+		Name:         d.Get("name").(string),
+		UUID:         d.Get("uuid").(string),
+		BlockDevices: blockDeviceIDs,
+		Partitions:   partitionIDs,
+	}
+	volumeGroup, err := clientConfig.VolumeGroups.Create(machine.SystemID, params)
+	if err != nil {
+		return diagFromErr(err)
+	}
+	d.SetId(fmt.Sprintf("%v", volumeGroup.ID))
+
+	return resourceVolumeGroupRead(ctx, d, m)
+}
+
+func resourceVolumeGroupRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	clientConfig := m.(*ClientConfig)
+
+	id, err := strconv.Atoi(d.Id())
+	if err != nil {
+		return diagFromErr(err)
+	}
+	machine, err := getMachine(clientConfig.Client, d.Get("machine").(string))
+	if err != nil {
+		return diagFromErr(err)
+	}
+	volumeGroup, err := clientConfig.VolumeGroups.GetByID(machine.SystemID, id)
+	if err != nil {
+		return diagFromErr(err)
+	}
+	blockDevices, err := clientConfig.Client.BlockDevices.Get(machine.SystemID)
+	if err != nil {
+		return diagFromErr(err)
+	}
+	memberBlockDevices, memberPartitions := getVolumeGroupMembersTFState(d, blockDevices, volumeGroup)
+	tfState := map[string]interface{}{
+		"name":                     volumeGroup.Name,
+		"uuid":                     volumeGroup.UUID,
+		"block_devices":            memberBlockDevices,
+		"partitions":               memberPartitions,
+		"size_gigabytes":           int(volumeGroup.Size / (1024 * 1024 * 1024)),
+		"used_size_gigabytes":      int(volumeGroup.UsedSize / (1024 * 1024 * 1024)),
+		"available_size_gigabytes": int(volumeGroup.AvailableSize / (1024 * 1024 * 1024)),
+	}
+	if err := setTerraformState(d, tfState); err != nil {
+		return diagFromErr(err)
+	}
+
+	return nil
+}
+
+func resourceVolumeGroupUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	clientConfig := m.(*ClientConfig)
+
+	id, err := strconv.Atoi(d.Id())
+	if err != nil {
+		return diagFromErr(err)
+	}
+	machine, err := getMachine(clientConfig.Client, d.Get("machine").(string))
+	if err != nil {
+		return diagFromErr(err)
+	}
+	volumeGroup, err := clientConfig.VolumeGroups.GetByID(machine.SystemID, id)
+	if err != nil {
+		return diagFromErr(err)
+	}
+	blockDeviceIDs, partitionIDs, err := getVolumeGroupMemberIDs(clientConfig.Client, d, machine.SystemID)
+	if err != nil {
+		return diagFromErr(err)
+	}
+	params := &VolumeGroupUpdateParams{
+		Name: d.Get("name").(string),
+		UUID: d.Get("uuid").(string),
+	}
+	currentBlockDeviceIDs := []int{}
+	currentPartitionIDs := []int{}
+	for _, device := range volumeGroup.Devices {
+		if device.Type == "partition" {
+			currentPartitionIDs = append(currentPartitionIDs, device.ID)
+		} else {
+			currentBlockDeviceIDs = append(currentBlockDeviceIDs, device.ID)
+		}
+	}
+	params.AddBlockDevices, params.RemoveBlockDevices = getIDsDiff(currentBlockDeviceIDs, blockDeviceIDs)
+	params.AddPartitions, params.RemovePartitions = getIDsDiff(currentPartitionIDs, partitionIDs)
+	if _, err := clientConfig.VolumeGroups.Update(machine.SystemID, id, params); err != nil {
+		return diagFromErr(err)
+	}
+
+	return resourceVolumeGroupRead(ctx, d, m)
+}
+
+func resourceVolumeGroupDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	clientConfig := m.(*ClientConfig)
+
+	id, err := strconv.Atoi(d.Id())
+	if err != nil {
+		return diagFromErr(err)
+	}
+	machine, err := getMachine(clientConfig.Client, d.Get("machine").(string))
+	if err != nil {
+		return diagFromErr(err)
+	}
+	if err := clientConfig.VolumeGroups.Delete(machine.SystemID, id); err != nil {
+		return diagFromErr(err)
+	}
+
+	return nil
+}
+
+func findVolumeGroup(clientConfig *ClientConfig, machineID string, identifier string) (*VolumeGroup, error) {
+	volumeGroups, err := clientConfig.VolumeGroups.Get(machineID)
+	if err != nil {
+		return nil, err
+	}
+	for _, v := range volumeGroups {
+		if fmt.Sprintf("%v", v.ID) == identifier || v.Name == identifier {
+			return &v, nil
+		}
+	}
+	return nil, nil
+}
+
+func getVolumeGroup(clientConfig *ClientConfig, machineID string, identifier string) (*VolumeGroup, error) {
+	volumeGroup, err := findVolumeGroup(clientConfig, machineID, identifier)
+	if err != nil {
+		return nil, err
+	}
+	if volumeGroup == nil {
+		return nil, fmt.Errorf("volume group (%s) was not found on machine (%s)", identifier, machineID)
+	}
+	return volumeGroup, nil
+}
+
+// getVolumeGroupMemberIDs returns the IDs of the configured block devices and
+// partitions of the volume group.
+func getVolumeGroupMemberIDs(client *client.Client, d *schema.ResourceData, machineID string) ([]int, []int, error) {
+	blockDevices, err := client.BlockDevices.Get(machineID)
+	if err != nil {
+		return nil, nil, err
+	}
+	blockDeviceIDs := []int{}
+	for _, identifier := range d.Get("block_devices").(*schema.Set).List() {
+		id, err := getStorageDeviceID(blockDevices, machineID, identifier.(string), false)
+		if err != nil {
+			return nil, nil, err
+		}
+		blockDeviceIDs = append(blockDeviceIDs, id)
+	}
+	partitionIDs := []int{}
+	for _, identifier := range d.Get("partitions").(*schema.Set).List() {
+		id, err := getStorageDeviceID(blockDevices, machineID, identifier.(string), true)
+		if err != nil {
+			return nil, nil, err
+		}
+		partitionIDs = append(partitionIDs, id)
+	}
+	return blockDeviceIDs, partitionIDs, nil
+}
+
+// getVolumeGroupMembersTFState returns the block devices and the partitions of
+// the volume group, keeping the configured identifiers.
+func getVolumeGroupMembersTFState(d *schema.ResourceData, blockDevices []entity.BlockDevice, volumeGroup *VolumeGroup) ([]string, []string) {
+	memberBlockDevices := []string{}
+	memberPartitions := []string{}
+	for _, device := range volumeGroup.Devices {
+		if device.Type == "partition" {
+			memberPartitions = append(memberPartitions, getStorageDeviceTFIdentifier(blockDevices, &device, d.Get("partitions").(*schema.Set).List()))
+		} else {
+			memberBlockDevices = append(memberBlockDevices, getStorageDeviceTFIdentifier(blockDevices, &device, d.Get("block_devices").(*schema.Set).List()))
+		}
+	}
+	return memberBlockDevices, memberPartitions
+}
+
+// getIDsDiff returns the IDs to add to and to remove from the current ones to
+// get the wanted ones.
+func getIDsDiff(current []int, wanted []int) ([]int, []int) {
+	toAdd := []int{}
+	toRemove := []int{}
+	for _, id := range wanted {
+		if !containsInt(current, id) {
+			toAdd = append(toAdd, id)
+		}
+	}
+	for _, id := range current {
+		if !containsInt(wanted, id) {
+			toRemove = append(toRemove, id)
+		}
+	}
+	return toAdd, toRemove
+}
+
+func containsInt(values []int, value int) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,169 @@
+package maas
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/maas/gomaasclient/client"
+	"github.com/maas/gomaasclient/entity"
+)
+
+func resourceMaasZone() *schema.Resource {
+	return &schema.Resource{
+		Description:   "Provides a resource to manage MAAS availability zones.",
+		CreateContext: resourceZoneCreate,
+		ReadContext:   resourceZoneRead,
+		UpdateContext: resourceZoneUpdate,
+		DeleteContext: resourceZoneDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: func(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+				zone, err := getZone(m.(*ClientConfig), d.Id())
+				if err != nil {
+					return nil, err
+				}
+				tfState := map[string]interface{}{
+					"id":    fmt.Sprintf("%v", zone.ID),
+					"force": false,
+				}
+				if err := setTerraformState(d, tfState); err != nil {
+					return nil, err
+				}
+				return []*schema.ResourceData{d}, nil
+			},
+		},
+
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The name of the zone.",
+			},
+			"description": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "A description of the zone.",
+			},
+			"force": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Boolean value indicating if the zone is deleted even if machines are still assigned to it. MAAS moves them to the default zone. Otherwise, deleting a zone with machines fails. Defaults to `false`.",
+			},
+		},
+	}
+}
+
+func resourceZoneCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	zone, err := m.(*ClientConfig).Zones.Create(getZoneParams(d))
+	if err != nil {
+		return diagFromErr(err)
+	}
+	d.SetId(fmt.Sprintf("%v", zone.ID))
+
+	return resourceZoneRead(ctx, d, m)
+}
+
+func resourceZoneRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	zone, err := findZone(m.(*ClientConfig), d.Id())
+	if err != nil {
+		return diagFromErr(err)
+	}
+	if zone == nil {
+		log.Printf("[DEBUG] Zone (%s) was not found, removing it from state\n", d.Id())
+		d.SetId("")
+		return nil
+	}
+	tfState := map[string]interface{}{
+		"name":        zone.Name,
+		"description": zone.Description,
+	}
+	if err := setTerraformState(d, tfState); err != nil {
+		return diagFromErr(err)
+	}
+
+	return nil
+}
+
+func resourceZoneUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	if d.HasChanges("name", "description") {
+		oldName, _ := d.GetChange("name")
+		if _, err := m.(*ClientConfig).Zones.Update(oldName.(string), getZoneParams(d)); err != nil {
+			return diagFromErr(err)
+		}
+	}
+
+	return resourceZoneRead(ctx, d, m)
+}
+
+func resourceZoneDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	clientConfig := m.(*ClientConfig)
+
+	name := d.Get("name").(string)
+	if !d.Get("force").(bool) {
+		machines, err := getMachinesSystemIDs(clientConfig.Client, func(machine *entity.Machine) bool {
+			return machine.Zone.Name == name
+		})
+		if err != nil {
+			return diagFromErr(err)
+		}
+		if len(machines) > 0 {
+			return diagFromErr(fmt.Errorf("zone (%s) can't be deleted, because it still has machines: %s. Move them to another zone, or set `force` to move them to the default zone", name, strings.Join(machines, ", ")))
+		}
+	}
+	if err := clientConfig.Zones.Delete(name); err != nil {
+		return diagFromErr(err)
+	}
+
+	return nil
+}
+
+func getZoneParams(d *schema.ResourceData) *ZoneParams {
+	return &ZoneParams{
+		Name:        d.Get("name").(string),
+		Description: d.Get("description").(string),
+	}
+}
+
+// getMachinesSystemIDs returns the system IDs of the machines matching the
+// given filter.
+func getMachinesSystemIDs(client *client.Client, filter func(*entity.Machine) bool) ([]string, error) {
+	machines, err := client.Machines.Get()
+	if err != nil {
+		return nil, err
+	}
+	systemIDs := []string{}
+	for i := range machines {
+		if filter(&machines[i]) {
+			systemIDs = append(systemIDs, machines[i].SystemID)
+		}
+	}
+	return systemIDs, nil
+}
+
+func findZone(clientConfig *ClientConfig, identifier string) (*entity.Zone, error) {
+	zones, err := clientConfig.Zones.Get()
+	if err != nil {
+		return nil, err
+	}
+	for _, z := range zones {
+		if fmt.Sprintf("%v", z.ID) == identifier || z.Name == identifier {
+			return &z, nil
+		}
+	}
+	return nil, nil
+}
+
+func getZone(clientConfig *ClientConfig, identifier string) (*entity.Zone, error) {
+	zone, err := findZone(clientConfig, identifier)
+	if err != nil {
+		return nil, err
+	}
+	if zone == nil {
+		return nil, fmt.Errorf("zone (%s) was not found", identifier)
+	}
+	return zone, nil
+}
@@ -2,14 +2,24 @@ package maas
 
 import (
 	"encoding/base64"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"log"
+	"net"
+	"net/http"
 	"net/mail"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
 
 	"github.com/hashicorp/go-cty/cty"
 	"github.com/hashicorp/go-cty/cty/gocty"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	gomaasapi "github.com/juju/gomaasapi/v2"
 	"github.com/maas/gomaasclient/client"
 	"github.com/maas/gomaasclient/entity"
 )
@@ -103,6 +113,85 @@ func getNetworkInterface(client *client.Client, machineSystemID string, identifi
 	return nil, fmt.Errorf("network interface (%s) was not found on machine (%s)", identifier, machineSystemID)
 }
 
+// isLinkedNetworkInterfaceVLANChange checks if moving the network interface to
+// the given VLAN (ID) requires removing its subnet links first, since MAAS
+// rejects changing the VLAN of a network interface linked to subnets.
+func isLinkedNetworkInterfaceVLANChange(networkInterface *entity.NetworkInterface, vlan string) bool {
+	return vlan != "" && len(networkInterface.Links) > 0 && fmt.Sprintf("%v", networkInterface.VLAN.ID) != vlan
+}
+
+// unlinkNetworkInterfaceSubnets removes all the subnet links of the network
+// interface, and returns the updated network interface.
+func unlinkNetworkInterfaceSubnets(client *client.Client, systemID string, networkInterface *entity.NetworkInterface) (*entity.NetworkInterface, error) {
+	log.Printf("[WARN] Removing the subnet links of the network interface (%s) of machine (%s) to change its VLAN\n", networkInterface.Name, systemID)
+	for _, link := range networkInterface.Links {
+		n, err := client.NetworkInterface.UnlinkSubnet(systemID, networkInterface.ID, link.ID)
+		if err != nil {
+			return nil, err
+		}
+		networkInterface = n
+	}
+	return networkInterface, nil
+}
+
+// forceNewOnWriteOnlyChange replaces the resource when the write-only secret
+// key, which MAAS never returns, is changed. The secret is unknown after
+// import, and then its first value is only recorded in the state.
+func forceNewOnWriteOnlyChange(d *schema.ResourceDiff, key string, unknown bool) error {
+	if d.Id() == "" || unknown || !d.HasChange(key) {
+		return nil
+	}
+	return d.ForceNew(key)
+}
+
+// suppressEquivalentIPDiff suppresses the diff between two notations of the
+// same IP address (e.g. `2001:db8::1` and `2001:0db8:0:0:0:0:0:1`).
+func suppressEquivalentIPDiff(k, old, new string, d *schema.ResourceData) bool {
+	oldIP := net.ParseIP(old)
+	return oldIP != nil && oldIP.Equal(net.ParseIP(new))
+}
+
+// getStringOrDefault returns the value of the given attribute, or the given
+// default value if the attribute is not set.
+func getStringOrDefault(d *schema.ResourceData, key string, defaultValue string) string {
+	if v := d.Get(key).(string); v != "" {
+		return v
+	}
+	return defaultValue
+}
+
+// getServerError returns the MAAS server error wrapped by the given error.
+// gomaasapi only unwraps the juju errors, so the errors wrapped with context
+// by fmt.Errorf are unwrapped too.
+func getServerError(err error) (gomaasapi.ServerError, bool) {
+	for ; err != nil; err = errors.Unwrap(err) {
+		if serverErr, ok := gomaasapi.GetServerError(err); ok {
+			return serverErr, true
+		}
+	}
+	return gomaasapi.ServerError{}, false
+}
+
+// isConflictError checks if the error is a MAAS 409 Conflict response, which
+// MAAS returns when the request conflicts with the current state of an object.
+func isConflictError(err error) bool {
+	serverErr, ok := getServerError(err)
+	return ok && serverErr.StatusCode == http.StatusConflict
+}
+
+// isNotFoundError checks if the error is a MAAS 404 Not Found response.
+func isNotFoundError(err error) bool {
+	serverErr, ok := getServerError(err)
+	return ok && serverErr.StatusCode == http.StatusNotFound
+}
+
+// isForbiddenError checks if the error is a MAAS 403 Forbidden response, which
+// MAAS returns when the user is not allowed to operate on an object.
+func isForbiddenError(err error) bool {
+	serverErr, ok := getServerError(err)
+	return ok && serverErr.StatusCode == http.StatusForbidden
+}
+
 func setTerraformState(d *schema.ResourceData, tfState map[string]interface{}) error {
 	if val, ok := tfState["id"]; ok {
 		d.SetId(val.(string))
@@ -115,3 +204,119 @@ func setTerraformState(d *schema.ResourceData, tfState map[string]interface{}) e
 	}
 	return nil
 }
+
+// diagFromErr converts the given error to diagnostics. When the error is
+// returned by the MAAS server, the diagnostic summary contains the message
+// MAAS returned in the response body (e.g. which field failed validation),
+// instead of just the HTTP status, and the context the error was wrapped
+// with goes in the detail.
+func diagFromErr(err error) diag.Diagnostics {
+	if err == nil {
+		return nil
+	}
+	serverErr, ok := getServerError(err)
+	if !ok {
+		return diag.FromErr(err)
+	}
+	summary := fmt.Sprintf("MAAS API error (%d %s)", serverErr.StatusCode, http.StatusText(serverErr.StatusCode))
+	if message := getServerErrorMessage(serverErr.BodyMessage); message != "" {
+		summary = fmt.Sprintf("%s: %s", summary, message)
+	}
+	return diag.Diagnostics{
+		diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  summary,
+			Detail:   err.Error(),
+		},
+	}
+}
+
+// getServerErrorMessage returns a readable message from the body of a MAAS
+// error response. The validation errors are returned by MAAS as a JSON object
+// with the invalid fields as keys, and the errors list as values.
+func getServerErrorMessage(body string) string {
+	body = strings.TrimSpace(body)
+	var fieldErrors map[string][]string
+	if err := json.Unmarshal([]byte(body), &fieldErrors); err != nil {
+		return body
+	}
+	fields := make([]string, 0, len(fieldErrors))
+	for field := range fieldErrors {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	messages := make([]string, len(fields))
+	for i, field := range fields {
+		messages[i] = fmt.Sprintf("%s: %s", field, strings.Join(fieldErrors[field], " "))
+	}
+	return strings.Join(messages, "; ")
+}
+
+// partitionAlignment is the size MAAS aligns the partitions to.
+const partitionAlignment = 4 * 1024 * 1024
+
+var (
+	sizeRegexp = regexp.MustCompile(`^(?i)(\d+(?:\.\d+)?)\s*([KMGTP]?)(?:I?B)?$`)
+	sizeUnits  = []string{"", "K", "M", "G", "T", "P"}
+)
+
+// parseSize parses a human-readable size (e.g. `100G`, `1.5T`, `512M`) to
+// bytes. The units are powers of 1024, and a number without unit is bytes.
+func parseSize(size string) (int64, error) {
+	matches := sizeRegexp.FindStringSubmatch(strings.TrimSpace(size))
+	if matches == nil {
+		return 0, fmt.Errorf("invalid size (%q), expected a number followed by an optional unit (K, M, G, T, or P), e.g. `100G`", size)
+	}
+	value, err := strconv.ParseFloat(matches[1], 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid size (%q): %w", size, err)
+	}
+	for _, unit := range sizeUnits {
+		if strings.EqualFold(unit, matches[2]) {
+			break
+		}
+		value *= 1024
+	}
+	return int64(value), nil
+}
+
+// formatSize formats the given bytes with the largest unit dividing them
+// exactly, so MAAS sizes read back look like the ones users write.
+func formatSize(size int64) string {
+	i := 0
+	for i < len(sizeUnits)-1 && size != 0 && size%1024 == 0 {
+		size /= 1024
+		i++
+	}
+	return fmt.Sprintf("%d%s", size, sizeUnits[i])
+}
+
+// roundSize rounds the given bytes down to a multiple of the block size.
+func roundSize(size int64, blockSize int64) int64 {
+	if blockSize <= 0 {
+		return size
+	}
+	return size - size%blockSize
+}
+
+// isSameSize checks if the human-readable size matches the bytes returned by
+// MAAS, which may differ because of the block size and partition alignment.
+func isSameSize(size string, bytes int64, blockSize int64) bool {
+	parsed, err := parseSize(size)
+	if err != nil {
+		return false
+	}
+	diff := roundSize(parsed, blockSize) - bytes
+	return diff > -partitionAlignment && diff < partitionAlignment
+}
+
+func validateSize(i interface{}, k string) ([]string, []error) {
+	v, ok := i.(string)
+	if !ok {
+		return nil, []error{fmt.Errorf("expected type of %q to be string", k)}
+	}
+	if _, err := parseSize(v); err != nil {
+		return nil, []error{fmt.Errorf("%s: %w", k, err)}
+	}
+	return nil, nil
+}
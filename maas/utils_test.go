@@ -1,10 +1,17 @@
 package maas
 
 import (
+	"errors"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
 	"reflect"
 	"testing"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	gomaasapi "github.com/juju/gomaasapi/v2"
+	"github.com/maas/gomaasclient/entity"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -66,3 +73,252 @@ func TestConvertToStringSlice(t *testing.T) {
 		})
 	}
 }
+
+func TestGetServerErrorMessage(t *testing.T) {
+	testCases := []struct {
+		name string
+		in   string
+		out  string
+	}{
+		{
+			name: "plain text message",
+			in:   "No rack controllers can access the BMC.\n",
+			out:  "No rack controllers can access the BMC.",
+		},
+		{
+			name: "validation errors",
+			in:   `{"size": ["Ensure this value is greater than or equal to 1."], "name": ["This field is required."]}`,
+			out:  "name: This field is required.; size: Ensure this value is greater than or equal to 1.",
+		},
+		{
+			name: "empty body",
+			in:   "",
+			out:  "",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			out := getServerErrorMessage(testCase.in)
+			assert.Equal(t, testCase.out, out, fmt.Sprintf("getServerErrorMessage(%s) => %s, want %s", testCase.in, out, testCase.out))
+		})
+	}
+}
+
+func TestDiagFromErr(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprint(w, `{"name": ["This field is required."]}`)
+	}))
+	defer server.Close()
+	client, err := gomaasapi.NewAnonymousClient(server.URL, "2.0")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	_, serverErr := client.Get(&url.URL{Path: "machines/"}, "", url.Values{})
+	testCases := []struct {
+		name string
+		in   error
+		out  diag.Diagnostics
+	}{
+		{
+			name: "server error",
+			in:   serverErr,
+			out: diag.Diagnostics{{
+				Severity: diag.Error,
+				Summary:  "MAAS API error (400 Bad Request): name: This field is required.",
+				Detail:   serverErr.Error(),
+			}},
+		},
+		{
+			name: "server error wrapped with context",
+			in:   fmt.Errorf("failed to compose machine on VM host (kvm01): %w", serverErr),
+			out: diag.Diagnostics{{
+				Severity: diag.Error,
+				Summary:  "MAAS API error (400 Bad Request): name: This field is required.",
+				Detail:   fmt.Sprintf("failed to compose machine on VM host (kvm01): %s", serverErr),
+			}},
+		},
+		{
+			name: "other error",
+			in:   errors.New("machine (abc123) was not found"),
+			out:  diag.FromErr(errors.New("machine (abc123) was not found")),
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			out := diagFromErr(testCase.in)
+			assert.Equal(t, testCase.out, out, fmt.Sprintf("diagFromErr(%v) => %v, want %v", testCase.in, out, testCase.out))
+		})
+	}
+}
+
+func TestParseSize(t *testing.T) {
+	testCases := []struct {
+		name string
+		in   string
+		out  int64
+		err  bool
+	}{
+		{
+			name: "bytes",
+			in:   "4096",
+			out:  4096,
+		},
+		{
+			name: "gigabytes",
+			in:   "100G",
+			out:  100 * 1024 * 1024 * 1024,
+		},
+		{
+			name: "fractional terabytes",
+			in:   "1.5T",
+			out:  1536 * 1024 * 1024 * 1024,
+		},
+		{
+			name: "lowercase unit with suffix",
+			in:   "512mib",
+			out:  512 * 1024 * 1024,
+		},
+		{
+			name: "unknown unit",
+			in:   "10X",
+			err:  true,
+		},
+		{
+			name: "missing number",
+			in:   "G",
+			err:  true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			out, err := parseSize(testCase.in)
+			if testCase.err {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, testCase.out, out, fmt.Sprintf("parseSize(%s) => %d, want %d", testCase.in, out, testCase.out))
+		})
+	}
+}
+
+func TestFormatSize(t *testing.T) {
+	testCases := []struct {
+		name string
+		in   int64
+		out  string
+	}{
+		{
+			name: "gigabytes",
+			in:   100 * 1024 * 1024 * 1024,
+			out:  "100G",
+		},
+		{
+			name: "fractional terabytes",
+			in:   1536 * 1024 * 1024 * 1024,
+			out:  "1536G",
+		},
+		{
+			name: "bytes",
+			in:   1000,
+			out:  "1000",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			out := formatSize(testCase.in)
+			assert.Equal(t, testCase.out, out, fmt.Sprintf("formatSize(%d) => %s, want %s", testCase.in, out, testCase.out))
+		})
+	}
+}
+
+func TestIsLinkedNetworkInterfaceVLANChange(t *testing.T) {
+	multipleLinks := []entity.NetworkInterfaceLink{
+		{ID: 1, Mode: "static", Subnet: entity.Subnet{ID: 1}},
+		{ID: 2, Mode: "auto", Subnet: entity.Subnet{ID: 2}},
+	}
+	testCases := []struct {
+		name             string
+		networkInterface *entity.NetworkInterface
+		vlan             string
+		out              bool
+	}{
+		{
+			name:             "multiple links moving VLAN",
+			networkInterface: &entity.NetworkInterface{VLAN: entity.VLAN{ID: 5001}, Links: multipleLinks},
+			vlan:             "5002",
+			out:              true,
+		},
+		{
+			name:             "multiple links on the same VLAN",
+			networkInterface: &entity.NetworkInterface{VLAN: entity.VLAN{ID: 5001}, Links: multipleLinks},
+			vlan:             "5001",
+			out:              false,
+		},
+		{
+			name:             "no links moving VLAN",
+			networkInterface: &entity.NetworkInterface{VLAN: entity.VLAN{ID: 5001}},
+			vlan:             "5002",
+			out:              false,
+		},
+		{
+			name:             "VLAN not set",
+			networkInterface: &entity.NetworkInterface{VLAN: entity.VLAN{ID: 5001}, Links: multipleLinks},
+			vlan:             "",
+			out:              false,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			out := isLinkedNetworkInterfaceVLANChange(testCase.networkInterface, testCase.vlan)
+			assert.Equal(t, testCase.out, out, fmt.Sprintf("isLinkedNetworkInterfaceVLANChange(%d, %s) => %t, want %t", testCase.networkInterface.VLAN.ID, testCase.vlan, out, testCase.out))
+		})
+	}
+}
+
+func TestSuppressEquivalentIPDiff(t *testing.T) {
+	testCases := []struct {
+		name string
+		old  string
+		new  string
+		out  bool
+	}{
+		{
+			name: "same IPv4 address",
+			old:  "10.0.0.10",
+			new:  "10.0.0.10",
+			out:  true,
+		},
+		{
+			name: "different IPv4 addresses",
+			old:  "10.0.0.10",
+			new:  "10.0.0.20",
+			out:  false,
+		},
+		{
+			name: "IPv6 address notations",
+			old:  "2001:db8::1",
+			new:  "2001:0db8:0:0:0:0:0:1",
+			out:  true,
+		},
+		{
+			name: "no old IP address",
+			old:  "",
+			new:  "10.0.0.10",
+			out:  false,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			out := suppressEquivalentIPDiff("start_ip", testCase.old, testCase.new, nil)
+			assert.Equal(t, testCase.out, out, fmt.Sprintf("suppressEquivalentIPDiff(%s, %s) => %t, want %t", testCase.old, testCase.new, out, testCase.out))
+		})
+	}
+}